@@ -0,0 +1,51 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"time"
+
+	"go.opentelemetry.io/collector/config/configmodels"
+)
+
+// Config defines configuration for docker observer.
+type Config struct {
+	configmodels.ExtensionSettings `mapstructure:",squash"`
+
+	// The URL of the docker server.  Default is "unix:///var/run/docker.sock"
+	Endpoint string `mapstructure:"endpoint"`
+
+	// The maximum amount of time to wait for docker API responses.  Default is 5s
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// A list of filters whose matching images are to be excluded.  Supports literals, globs, and regex.
+	ExcludedImages []string `mapstructure:"excluded_images"`
+
+	// UseHostBindings makes the endpoint target the host IP and port a container
+	// port is published on instead of the container IP and port.  Container ports
+	// that aren't published are skipped.  Default is false
+	UseHostBindings bool `mapstructure:"use_host_bindings"`
+
+	// RefreshInterval determines how frequently the observer resyncs the
+	// endpoints with the tracked containers, in addition to the changes pushed
+	// by the container events.  Default is 10s
+	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// DebounceInterval is how long endpoint changes are batched after the
+	// first one before being reported, so that bursts of container events are
+	// reported at once.  Changes are reported right away when it is zero.
+	// Default is 0
+	DebounceInterval time.Duration `mapstructure:"debounce_interval"`
+}
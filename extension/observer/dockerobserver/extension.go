@@ -0,0 +1,270 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	"go.opentelemetry.io/collector/component"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
+)
+
+const userAgent = "OpenTelemetry-Collector Docker Observer/v0.0.1"
+
+type dockerObserver struct {
+	observer.EndpointsWatcher
+	logger  *zap.Logger
+	config  *Config
+	dClient *docker.Client
+	cancel  context.CancelFunc
+
+	mu sync.Mutex
+	// containerEndpoints are the endpoints last pushed for each container.
+	containerEndpoints map[string][]observer.Endpoint
+}
+
+var _ component.ServiceExtension = (*dockerObserver)(nil)
+
+func newObserver(logger *zap.Logger, config *Config) (component.ServiceExtension, error) {
+	if config.Endpoint == "" {
+		return nil, errors.New("config.Endpoint must be specified")
+	}
+
+	d := &dockerObserver{
+		logger:             logger,
+		config:             config,
+		containerEndpoints: map[string][]observer.Endpoint{},
+	}
+	d.EndpointsWatcher = observer.EndpointsWatcher{
+		RefreshInterval:  config.RefreshInterval,
+		Endpointslister:  d,
+		DebounceInterval: config.DebounceInterval,
+	}
+
+	return d, nil
+}
+
+// Start connects to the docker daemon, loads the running containers and
+// keeps them up to date by watching container events until Shutdown. The
+// endpoints of the containers are pushed to the subscribers as the events
+// are received, and the containers are polled every RefreshInterval to
+// resync any event that was missed.
+func (d *dockerObserver) Start(ctx context.Context, host component.Host) error {
+	dClient, err := docker.NewDockerClient(&docker.Config{
+		Endpoint:       d.config.Endpoint,
+		Timeout:        d.config.Timeout,
+		ExcludedImages: d.config.ExcludedImages,
+		UserAgent:      userAgent,
+	}, d.logger)
+	if err != nil {
+		return fmt.Errorf("could not create docker client: %w", err)
+	}
+
+	var eventCtx context.Context
+	eventCtx, d.cancel = context.WithCancel(context.Background())
+	if err = dClient.LoadContainerList(eventCtx); err != nil {
+		d.cancel()
+		return fmt.Errorf("could not list docker containers: %w", err)
+	}
+
+	d.dClient = dClient
+	d.dClient.SetEventHandler(d.handleContainerEvent)
+	go d.dClient.ContainerEventLoop(eventCtx)
+	return nil
+}
+
+func (d *dockerObserver) Shutdown(context.Context) error {
+	d.StopListAndWatch()
+	if d.cancel != nil {
+		d.cancel()
+	}
+	return nil
+}
+
+// ListEndpoints returns the endpoints of the containers currently tracked by
+// the docker client.
+func (d *dockerObserver) ListEndpoints() []observer.Endpoint {
+	if d.dClient == nil {
+		return nil
+	}
+
+	var endpoints []observer.Endpoint
+	for _, c := range d.dClient.Containers() {
+		endpoints = append(endpoints, d.endpointsForContainer(c.ContainerJSON)...)
+	}
+	return endpoints
+}
+
+// handleContainerEvent pushes the endpoints of a container that was updated,
+// and removes the endpoints of a container that stopped running.
+func (d *dockerObserver) handleContainerEvent(_ devents.Message, c *docker.Container) {
+	if c == nil || c.ContainerJSON == nil || c.ContainerJSONBase == nil {
+		return
+	}
+
+	var endpoints []observer.Endpoint
+	if c.State != nil && c.State.Running && !c.State.Paused {
+		endpoints = d.endpointsForContainer(c.ContainerJSON)
+	}
+
+	d.mu.Lock()
+	previous := d.containerEndpoints[c.ID]
+	if len(endpoints) > 0 {
+		d.containerEndpoints[c.ID] = endpoints
+	} else {
+		delete(d.containerEndpoints, c.ID)
+	}
+	d.mu.Unlock()
+
+	current := make(map[observer.EndpointID]bool, len(endpoints))
+	for _, e := range endpoints {
+		current[e.ID] = true
+	}
+	existing := make(map[observer.EndpointID]bool, len(previous))
+	var removed []observer.Endpoint
+	for _, e := range previous {
+		existing[e.ID] = true
+		if !current[e.ID] {
+			removed = append(removed, e)
+		}
+	}
+	var added, changed []observer.Endpoint
+	for _, e := range endpoints {
+		if existing[e.ID] {
+			changed = append(changed, e)
+		} else {
+			added = append(added, e)
+		}
+	}
+
+	if len(removed) > 0 {
+		d.OnRemove(removed)
+	}
+	if len(added) > 0 {
+		d.OnAdd(added)
+	}
+	if len(changed) > 0 {
+		d.OnChange(changed)
+	}
+}
+
+// endpointsForContainer creates an endpoint for each port of the container.
+func (d *dockerObserver) endpointsForContainer(c *dtypes.ContainerJSON) []observer.Endpoint {
+	if c == nil || c.Config == nil || c.NetworkSettings == nil {
+		return nil
+	}
+
+	image, tag := parseImage(c.Config.Image)
+	ip := containerIP(c)
+
+	endpoints := make([]observer.Endpoint, 0, len(c.NetworkSettings.Ports))
+	for port, bindings := range c.NetworkSettings.Ports {
+		details := observer.Container{
+			Name:        strings.TrimPrefix(c.Name, "/"),
+			Image:       image,
+			Tag:         tag,
+			Port:        uint16(port.Int()),
+			Command:     strings.Join(c.Config.Cmd, " "),
+			ContainerID: c.ID,
+			Host:        ip,
+			Transport:   portProtoToTransport(port.Proto()),
+			Labels:      c.Config.Labels,
+		}
+
+		targetPort := details.Port
+		if len(bindings) > 0 {
+			hostPort, err := strconv.ParseUint(bindings[0].HostPort, 10, 16)
+			if err != nil {
+				d.logger.Debug("Invalid host port binding",
+					zap.String("id", c.ID), zap.String("port", string(port)), zap.Error(err))
+			}
+			details.AlternatePort = uint16(hostPort)
+		}
+
+		if d.config.UseHostBindings {
+			if details.AlternatePort == 0 {
+				continue
+			}
+			details.Host = bindings[0].HostIP
+			// A host IP of 0.0.0.0 means the port is published on all
+			// interfaces, including localhost.
+			if details.Host == "" || details.Host == "0.0.0.0" {
+				details.Host = "127.0.0.1"
+			}
+			targetPort = details.AlternatePort
+		}
+
+		// Containers without an IP (e.g. network mode "none") can't be reached.
+		if details.Host == "" {
+			continue
+		}
+
+		endpoints = append(endpoints, observer.Endpoint{
+			ID: observer.EndpointID(
+				fmt.Sprintf("(%s)%s:%d/%s", d.config.Name(), c.ID, details.Port, port.Proto()),
+			),
+			Target:  fmt.Sprintf("%s:%d", details.Host, targetPort),
+			Details: details,
+		})
+	}
+
+	return endpoints
+}
+
+// containerIP returns the IP of the container on the default bridge network
+// or, if not attached to it, on the first network it has an IP on.
+func containerIP(c *dtypes.ContainerJSON) string {
+	if c.NetworkSettings.IPAddress != "" {
+		return c.NetworkSettings.IPAddress
+	}
+	for _, n := range c.NetworkSettings.Networks {
+		if n != nil && n.IPAddress != "" {
+			return n.IPAddress
+		}
+	}
+	return ""
+}
+
+// parseImage splits an image reference like "registry:5000/redis:6.0" into
+// its name and tag.
+func parseImage(ref string) (string, string) {
+	if i := strings.Index(ref, "@"); i >= 0 {
+		ref = ref[:i]
+	}
+	if i := strings.LastIndex(ref, ":"); i > strings.LastIndex(ref, "/") {
+		return ref[:i], ref[i+1:]
+	}
+	return ref, "latest"
+}
+
+func portProtoToTransport(proto string) observer.Transport {
+	switch proto {
+	case "tcp":
+		return observer.ProtocolTCP
+	case "udp":
+		return observer.ProtocolUDP
+	}
+	return observer.ProtocolUnknown
+}
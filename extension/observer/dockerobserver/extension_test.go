@@ -0,0 +1,253 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerobserver
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/docker/docker/api/types/container"
+	devents "github.com/docker/docker/api/types/events"
+	"github.com/docker/docker/api/types/network"
+	"github.com/docker/go-connections/nat"
+	"github.com/stretchr/testify/assert"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
+)
+
+func redisContainer() *dtypes.ContainerJSON {
+	return &dtypes.ContainerJSON{
+		ContainerJSONBase: &dtypes.ContainerJSONBase{
+			ID:   "abcdef",
+			Name: "/redis-1",
+		},
+		Config: &container.Config{
+			Image:  "redis:6.0",
+			Cmd:    []string{"redis-server", "--appendonly", "yes"},
+			Labels: map[string]string{"app": "cache"},
+		},
+		NetworkSettings: &dtypes.NetworkSettings{
+			NetworkSettingsBase: dtypes.NetworkSettingsBase{
+				Ports: nat.PortMap{
+					"6379/tcp": []nat.PortBinding{{HostIP: "0.0.0.0", HostPort: "16379"}},
+					"8125/udp": nil,
+				},
+			},
+			DefaultNetworkSettings: dtypes.DefaultNetworkSettings{
+				IPAddress: "172.17.0.2",
+			},
+		},
+	}
+}
+
+func newTestObserver(t *testing.T, useHostBindings bool) *dockerObserver {
+	config := createDefaultConfig().(*Config)
+	config.UseHostBindings = useHostBindings
+	ext, err := newObserver(zap.NewNop(), config)
+	assert.NoError(t, err)
+	return ext.(*dockerObserver)
+}
+
+func TestEndpointsForContainer(t *testing.T) {
+	d := newTestObserver(t, false)
+
+	assert.ElementsMatch(t, []observer.Endpoint{
+		{
+			ID:     "(docker_observer)abcdef:6379/tcp",
+			Target: "172.17.0.2:6379",
+			Details: observer.Container{
+				Name:          "redis-1",
+				Image:         "redis",
+				Tag:           "6.0",
+				Port:          6379,
+				AlternatePort: 16379,
+				Command:       "redis-server --appendonly yes",
+				ContainerID:   "abcdef",
+				Host:          "172.17.0.2",
+				Transport:     observer.ProtocolTCP,
+				Labels:        map[string]string{"app": "cache"},
+			},
+		},
+		{
+			ID:     "(docker_observer)abcdef:8125/udp",
+			Target: "172.17.0.2:8125",
+			Details: observer.Container{
+				Name:        "redis-1",
+				Image:       "redis",
+				Tag:         "6.0",
+				Port:        8125,
+				Command:     "redis-server --appendonly yes",
+				ContainerID: "abcdef",
+				Host:        "172.17.0.2",
+				Transport:   observer.ProtocolUDP,
+				Labels:      map[string]string{"app": "cache"},
+			},
+		},
+	}, d.endpointsForContainer(redisContainer()))
+}
+
+func TestEndpointsForContainerUseHostBindings(t *testing.T) {
+	d := newTestObserver(t, true)
+
+	// Unpublished ports are skipped.
+	assert.Equal(t, []observer.Endpoint{
+		{
+			ID:     "(docker_observer)abcdef:6379/tcp",
+			Target: "127.0.0.1:16379",
+			Details: observer.Container{
+				Name:          "redis-1",
+				Image:         "redis",
+				Tag:           "6.0",
+				Port:          6379,
+				AlternatePort: 16379,
+				Command:       "redis-server --appendonly yes",
+				ContainerID:   "abcdef",
+				Host:          "127.0.0.1",
+				Transport:     observer.ProtocolTCP,
+				Labels:        map[string]string{"app": "cache"},
+			},
+		},
+	}, d.endpointsForContainer(redisContainer()))
+}
+
+func TestEndpointsForContainerWithoutIP(t *testing.T) {
+	d := newTestObserver(t, false)
+
+	c := redisContainer()
+	c.NetworkSettings.IPAddress = ""
+	assert.Empty(t, d.endpointsForContainer(c))
+
+	c.NetworkSettings.Networks = map[string]*network.EndpointSettings{
+		"custom": {IPAddress: "10.1.0.3"},
+	}
+	endpoints := d.endpointsForContainer(c)
+	assert.Len(t, endpoints, 2)
+	for _, e := range endpoints {
+		assert.Equal(t, "10.1.0.3", e.Details.(observer.Container).Host)
+	}
+}
+
+func TestListEndpointsBeforeStart(t *testing.T) {
+	d := newTestObserver(t, false)
+	assert.Nil(t, d.ListEndpoints())
+}
+
+func TestParseImage(t *testing.T) {
+	tests := []struct {
+		ref   string
+		image string
+		tag   string
+	}{
+		{ref: "redis", image: "redis", tag: "latest"},
+		{ref: "redis:6.0", image: "redis", tag: "6.0"},
+		{ref: "localhost:5000/redis", image: "localhost:5000/redis", tag: "latest"},
+		{ref: "localhost:5000/redis:6.0", image: "localhost:5000/redis", tag: "6.0"},
+		{ref: "redis@sha256:abcdef", image: "redis", tag: "latest"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.ref, func(t *testing.T) {
+			image, tag := parseImage(tt.ref)
+			assert.Equal(t, tt.image, image)
+			assert.Equal(t, tt.tag, tag)
+		})
+	}
+}
+
+type recordingNotify struct {
+	mu      sync.Mutex
+	added   []observer.EndpointID
+	removed []observer.EndpointID
+	changed []observer.EndpointID
+}
+
+func ids(endpoints []observer.Endpoint) []observer.EndpointID {
+	var ids []observer.EndpointID
+	for _, e := range endpoints {
+		ids = append(ids, e.ID)
+	}
+	return ids
+}
+
+func (n *recordingNotify) OnAdd(added []observer.Endpoint) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.added = append(n.added, ids(added)...)
+}
+
+func (n *recordingNotify) OnRemove(removed []observer.Endpoint) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.removed = append(n.removed, ids(removed)...)
+}
+
+func (n *recordingNotify) OnChange(changed []observer.Endpoint) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.changed = append(n.changed, ids(changed)...)
+}
+
+func (n *recordingNotify) counts() (int, int, int) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	return len(n.added), len(n.removed), len(n.changed)
+}
+
+func TestHandleContainerEvent(t *testing.T) {
+	d := newTestObserver(t, false)
+	notify := &recordingNotify{}
+	d.ListAndWatch(notify)
+	defer d.StopListAndWatch()
+
+	c := redisContainer()
+	c.State = &dtypes.ContainerState{Running: true}
+	d.handleContainerEvent(devents.Message{Action: "start"}, &docker.Container{ContainerJSON: c})
+	assert.Eventually(t, func() bool {
+		added, _, _ := notify.counts()
+		return added == 2
+	}, time.Second, time.Millisecond)
+
+	// The UDP port is no longer exposed and the labels of the TCP port changed.
+	c = redisContainer()
+	c.State = &dtypes.ContainerState{Running: true}
+	c.Config.Labels = map[string]string{"app": "db"}
+	delete(c.NetworkSettings.Ports, "8125/udp")
+	d.handleContainerEvent(devents.Message{Action: "update"}, &docker.Container{ContainerJSON: c})
+	assert.Eventually(t, func() bool {
+		_, removed, changed := notify.counts()
+		return removed == 1 && changed == 1
+	}, time.Second, time.Millisecond)
+
+	c = redisContainer()
+	c.State = &dtypes.ContainerState{Running: false}
+	c.NetworkSettings.IPAddress = ""
+	d.handleContainerEvent(devents.Message{Action: "die"}, &docker.Container{ContainerJSON: c})
+	assert.Eventually(t, func() bool {
+		_, removed, _ := notify.counts()
+		return removed == 2
+	}, time.Second, time.Millisecond)
+
+	notify.mu.Lock()
+	defer notify.mu.Unlock()
+	assert.Equal(t, []observer.EndpointID{
+		"(docker_observer)abcdef:8125/udp",
+		"(docker_observer)abcdef:6379/tcp",
+	}, notify.removed)
+	assert.Equal(t, []observer.EndpointID{"(docker_observer)abcdef:6379/tcp"}, notify.changed)
+	assert.Empty(t, d.containerEndpoints)
+}
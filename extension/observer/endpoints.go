@@ -36,6 +36,10 @@ func (e *Endpoint) String() string {
 type Pod struct {
 	// Name of the pod.
 	Name string
+	// UID is the unique ID in the cluster for the pod.
+	UID string
+	// Namespace is the namespace the pod is running in.
+	Namespace string
 	// Labels is a map of user-specified metadata.
 	Labels map[string]string
 	// Annotations is a map of user-specified metadata.
@@ -48,6 +52,10 @@ type Port struct {
 	Name string
 	// Pod is the k8s pod in which the container is running.
 	Pod Pod
+	// ContainerName is the name of the container exposing the port.
+	ContainerName string
+	// ContainerImage is the image of the container exposing the port.
+	ContainerImage string
 	// Port number of the endpoint.
 	Port uint16
 	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
@@ -68,6 +76,76 @@ type HostPort struct {
 	Transport Transport
 	// IsIPv6 indicates whether or not the Endpoint is IPv6.
 	IsIPv6 bool
+	// ContainerID is the ID of the container the process is running in,
+	// or an empty string if it isn't running in a container.
+	ContainerID string
+}
+
+// K8sService is a discovered k8s service port.
+type K8sService struct {
+	// Name of the service.
+	Name string
+	// UID is the unique ID in the cluster for the service.
+	UID string
+	// Namespace is the namespace the service is in.
+	Namespace string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// ClusterIP is the IP the service is reachable at inside the cluster.
+	ClusterIP string
+	// ServiceType is the type of the service (ClusterIP, NodePort, LoadBalancer or ExternalName).
+	ServiceType string
+	// PortName is the name of the service port.
+	PortName string
+	// Port number of the service port.
+	Port uint16
+	// Transport is the transport protocol used by the service port. (TCP or UDP).
+	Transport Transport
+}
+
+// K8sNode is a discovered k8s node.
+type K8sNode struct {
+	// Name of the node.
+	Name string
+	// UID is the unique ID in the cluster for the node.
+	UID string
+	// Labels is a map of user-specified metadata.
+	Labels map[string]string
+	// Annotations is a map of user-specified metadata.
+	Annotations map[string]string
+	// InternalIP is the internal IP address of the node.
+	InternalIP string
+	// Hostname is the hostname of the node.
+	Hostname string
+	// KubeletEndpointPort is the port the kubelet is listening on.
+	KubeletEndpointPort uint16
+}
+
+// Container is a discovered container port.
+type Container struct {
+	// Name is the primary name of the container.
+	Name string
+	// Image is the image name of the container.
+	Image string
+	// Tag is the tag of the container image.
+	Tag string
+	// Port is the container's internal port.
+	Port uint16
+	// AlternatePort is the port the container's internal port is
+	// mapped to on the host, or 0 if it is not published.
+	AlternatePort uint16
+	// Command used to invoke the process using the Endpoint.
+	Command string
+	// ContainerID is the ID of the container.
+	ContainerID string
+	// Host is the IP address the endpoint is reachable at.
+	Host string
+	// Transport is the transport protocol used by the Endpoint. (TCP or UDP).
+	Transport Transport
+	// Labels is a map of user-specified metadata on the container.
+	Labels map[string]string
 }
 
 type EndpointEnv map[string]interface{}
@@ -75,8 +153,11 @@ type EndpointEnv map[string]interface{}
 // EndpointToEnv converts an endpoint into a map suitable for expr evaluation.
 func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 	ruleTypes := map[string]interface{}{
-		"port": false,
-		"pod":  false,
+		"port":      false,
+		"pod":       false,
+		"service":   false,
+		"node":      false,
+		"container": false,
 	}
 
 	switch o := endpoint.Details.(type) {
@@ -86,6 +167,8 @@ func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 			"type":        ruleTypes,
 			"endpoint":    endpoint.Target,
 			"name":        o.Name,
+			"uid":         o.UID,
+			"namespace":   o.Namespace,
 			"labels":      o.Labels,
 			"annotations": o.Annotations,
 		}, nil
@@ -96,23 +179,75 @@ func EndpointToEnv(endpoint Endpoint) (EndpointEnv, error) {
 			"endpoint": endpoint.Target,
 			"name":     o.Name,
 			"port":     o.Port,
+			"container": map[string]interface{}{
+				"name":  o.ContainerName,
+				"image": o.ContainerImage,
+			},
 			"pod": map[string]interface{}{
 				"name":        o.Pod.Name,
+				"uid":         o.Pod.UID,
+				"namespace":   o.Pod.Namespace,
 				"labels":      o.Pod.Labels,
 				"annotations": o.Pod.Annotations,
 			},
 			"transport": o.Transport,
 		}, nil
+	case K8sService:
+		ruleTypes["service"] = true
+		return map[string]interface{}{
+			"type":         ruleTypes,
+			"endpoint":     endpoint.Target,
+			"name":         o.Name,
+			"uid":          o.UID,
+			"namespace":    o.Namespace,
+			"labels":       o.Labels,
+			"annotations":  o.Annotations,
+			"cluster_ip":   o.ClusterIP,
+			"service_type": o.ServiceType,
+			"port_name":    o.PortName,
+			"port":         o.Port,
+			"transport":    o.Transport,
+		}, nil
+	case K8sNode:
+		ruleTypes["node"] = true
+		return map[string]interface{}{
+			"type":                  ruleTypes,
+			"endpoint":              endpoint.Target,
+			"name":                  o.Name,
+			"uid":                   o.UID,
+			"labels":                o.Labels,
+			"annotations":           o.Annotations,
+			"internal_ip":           o.InternalIP,
+			"hostname":              o.Hostname,
+			"kubelet_endpoint_port": o.KubeletEndpointPort,
+		}, nil
+	case Container:
+		ruleTypes["container"] = true
+		return map[string]interface{}{
+			"type":           ruleTypes,
+			"endpoint":       endpoint.Target,
+			"name":           o.Name,
+			"image":          o.Image,
+			"tag":            o.Tag,
+			"port":           o.Port,
+			"alternate_port": o.AlternatePort,
+			"command":        o.Command,
+			"container_id":   o.ContainerID,
+			"host":           o.Host,
+			"transport":      o.Transport,
+			"labels":         o.Labels,
+		}, nil
 	case HostPort:
 		ruleTypes["port"] = true
 		return map[string]interface{}{
-			"type":      ruleTypes,
-			"endpoint":  endpoint.Target,
-			"name":      o.Name,
-			"command":   o.Command,
-			"is_ipv6":   o.IsIPv6,
-			"port":      o.Port,
-			"transport": o.Transport,
+			"type":         ruleTypes,
+			"endpoint":     endpoint.Target,
+			"name":         o.Name,
+			"command":      o.Command,
+			"is_ipv6":      o.IsIPv6,
+			"port":         o.Port,
+			"transport":    o.Transport,
+			"container_id": o.ContainerID,
 		}, nil
 
 	default:
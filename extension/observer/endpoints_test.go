@@ -32,7 +32,9 @@ func TestEndpointToEnv(t *testing.T) {
 				ID:     EndpointID("pod_id"),
 				Target: "192.68.73.2",
 				Details: Pod{
-					Name: "pod_name",
+					Name:      "pod_name",
+					UID:       "pod-uid",
+					Namespace: "pod-namespace",
 					Labels: map[string]string{
 						"label_key": "label_val",
 					},
@@ -43,11 +45,16 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": false,
-					"pod":  true,
+					"port":      false,
+					"pod":       true,
+					"service":   false,
+					"node":      false,
+					"container": false,
 				},
-				"endpoint": "192.68.73.2",
-				"name":     "pod_name",
+				"endpoint":  "192.68.73.2",
+				"name":      "pod_name",
+				"uid":       "pod-uid",
+				"namespace": "pod-namespace",
 				"labels": map[string]string{
 					"label_key": "label_val",
 				},
@@ -65,7 +72,9 @@ func TestEndpointToEnv(t *testing.T) {
 				Details: Port{
 					Name: "port_name",
 					Pod: Pod{
-						Name: "pod_name",
+						Name:      "pod_name",
+						UID:       "pod-uid",
+						Namespace: "pod-namespace",
 						Labels: map[string]string{
 							"label_key": "label_val",
 						},
@@ -73,20 +82,31 @@ func TestEndpointToEnv(t *testing.T) {
 							"annotation_1": "value_1",
 						},
 					},
-					Port:      2379,
-					Transport: ProtocolTCP,
+					ContainerName:  "container_name",
+					ContainerImage: "container_image",
+					Port:           2379,
+					Transport:      ProtocolTCP,
 				},
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": true,
-					"pod":  false,
+					"port":      true,
+					"pod":       false,
+					"service":   false,
+					"node":      false,
+					"container": false,
 				},
 				"endpoint": "192.68.73.2",
 				"name":     "port_name",
 				"port":     uint16(2379),
+				"container": map[string]interface{}{
+					"name":  "container_name",
+					"image": "container_image",
+				},
 				"pod": map[string]interface{}{
-					"name": "pod_name",
+					"name":      "pod_name",
+					"uid":       "pod-uid",
+					"namespace": "pod-namespace",
 					"labels": map[string]string{
 						"label_key": "label_val",
 					},
@@ -98,30 +118,149 @@ func TestEndpointToEnv(t *testing.T) {
 			},
 			wantErr: false,
 		},
+		{
+			name: "K8s service",
+			endpoint: Endpoint{
+				ID:     EndpointID("service_id"),
+				Target: "10.0.0.10:8080",
+				Details: K8sService{
+					Name:        "service_name",
+					UID:         "service-uid",
+					Namespace:   "service-namespace",
+					Labels:      map[string]string{"label_key": "label_val"},
+					Annotations: map[string]string{"annotation_1": "value_1"},
+					ClusterIP:   "10.0.0.10",
+					ServiceType: "ClusterIP",
+					PortName:    "http",
+					Port:        8080,
+					Transport:   ProtocolTCP,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":      false,
+					"pod":       false,
+					"service":   true,
+					"node":      false,
+					"container": false,
+				},
+				"endpoint":     "10.0.0.10:8080",
+				"name":         "service_name",
+				"uid":          "service-uid",
+				"namespace":    "service-namespace",
+				"labels":       map[string]string{"label_key": "label_val"},
+				"annotations":  map[string]string{"annotation_1": "value_1"},
+				"cluster_ip":   "10.0.0.10",
+				"service_type": "ClusterIP",
+				"port_name":    "http",
+				"port":         uint16(8080),
+				"transport":    ProtocolTCP,
+			},
+			wantErr: false,
+		},
+		{
+			name: "K8s node",
+			endpoint: Endpoint{
+				ID:     EndpointID("node_id"),
+				Target: "192.68.73.5",
+				Details: K8sNode{
+					Name:                "node_name",
+					UID:                 "node-uid",
+					Labels:              map[string]string{"label_key": "label_val"},
+					Annotations:         map[string]string{"annotation_1": "value_1"},
+					InternalIP:          "192.68.73.5",
+					Hostname:            "node-hostname",
+					KubeletEndpointPort: 10250,
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":      false,
+					"pod":       false,
+					"service":   false,
+					"node":      true,
+					"container": false,
+				},
+				"endpoint":              "192.68.73.5",
+				"name":                  "node_name",
+				"uid":                   "node-uid",
+				"labels":                map[string]string{"label_key": "label_val"},
+				"annotations":           map[string]string{"annotation_1": "value_1"},
+				"internal_ip":           "192.68.73.5",
+				"hostname":              "node-hostname",
+				"kubelet_endpoint_port": uint16(10250),
+			},
+			wantErr: false,
+		},
+		{
+			name: "Container",
+			endpoint: Endpoint{
+				ID:     EndpointID("container_id"),
+				Target: "127.0.0.1:8080",
+				Details: Container{
+					Name:          "container_name",
+					Image:         "redis",
+					Tag:           "6.0",
+					Port:          6379,
+					AlternatePort: 8080,
+					Command:       "redis-server",
+					ContainerID:   "abcdef",
+					Host:          "127.0.0.1",
+					Transport:     ProtocolTCP,
+					Labels:        map[string]string{"label_key": "label_val"},
+				},
+			},
+			want: EndpointEnv{
+				"type": map[string]interface{}{
+					"port":      false,
+					"pod":       false,
+					"service":   false,
+					"node":      false,
+					"container": true,
+				},
+				"endpoint":       "127.0.0.1:8080",
+				"name":           "container_name",
+				"image":          "redis",
+				"tag":            "6.0",
+				"port":           uint16(6379),
+				"alternate_port": uint16(8080),
+				"command":        "redis-server",
+				"container_id":   "abcdef",
+				"host":           "127.0.0.1",
+				"transport":      ProtocolTCP,
+				"labels":         map[string]string{"label_key": "label_val"},
+			},
+			wantErr: false,
+		},
 		{
 			name: "Host port",
 			endpoint: Endpoint{
 				ID:     EndpointID("port_id"),
 				Target: "127.0.0.1",
 				Details: HostPort{
-					Name:      "process_name",
-					Command:   "./cmd --config config.yaml",
-					Port:      2379,
-					Transport: ProtocolUDP,
-					IsIPv6:    true,
+					Name:        "process_name",
+					Command:     "./cmd --config config.yaml",
+					Port:        2379,
+					Transport:   ProtocolUDP,
+					IsIPv6:      true,
+					ContainerID: "abcdef",
 				},
 			},
 			want: EndpointEnv{
 				"type": map[string]interface{}{
-					"port": true,
-					"pod":  false,
-				},
-				"endpoint":  "127.0.0.1",
-				"name":      "process_name",
-				"command":   "./cmd --config config.yaml",
-				"is_ipv6":   true,
-				"port":      uint16(2379),
-				"transport": ProtocolUDP,
+					"port":      true,
+					"pod":       false,
+					"service":   false,
+					"node":      false,
+					"container": false,
+				},
+				"endpoint":     "127.0.0.1",
+				"name":         "process_name",
+				"command":      "./cmd --config config.yaml",
+				"is_ipv6":      true,
+				"port":         uint16(2379),
+				"transport":    ProtocolUDP,
+				"container_id": "abcdef",
 			},
 			wantErr: false,
 		},
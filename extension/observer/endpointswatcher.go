@@ -16,28 +16,70 @@ package observer
 
 import (
 	"reflect"
+	"sync"
 	"time"
 )
 
-// EndpointsWatcher provides a generic mechanism to run ListEndpoints every
-// RefreshInterval and report any new or removed endpoints using Notify
-// passed into ListAndWatch. Any observer that lists endpoints can make
-// use of EndpointsWatcher to poll for endpoints by embedding this struct
-// in the observer struct.
+var _ Observable = (*EndpointsWatcher)(nil)
+var _ Notify = (*EndpointsWatcher)(nil)
+
+// EndpointsWatcher provides a generic mechanism to keep track of the endpoints
+// of an observer and report any new, removed or changed endpoints to the Notify
+// subscribers passed into ListAndWatch. Endpoints are either polled by running
+// ListEndpoints every RefreshInterval, or pushed by the observer as they happen
+// with OnAdd, OnRemove and OnChange. Any observer can make use of EndpointsWatcher
+// by embedding this struct in the observer struct.
 type EndpointsWatcher struct {
-	Endpointslister   EndpointsLister
-	RefreshInterval   time.Duration
+	// Endpointslister is polled for endpoints every RefreshInterval when set.
+	Endpointslister EndpointsLister
+	RefreshInterval time.Duration
+	// DebounceInterval is how long changes are batched after the first one
+	// before being sent to the subscribers. Changes are sent right away when
+	// it is zero.
+	DebounceInterval time.Duration
+
+	mu sync.Mutex
+	// existingEndpoints is the latest state of the endpoints.
 	existingEndpoints map[EndpointID]Endpoint
-	stop              chan struct{}
+	// notifiedEndpoints is the state of the endpoints last sent to the subscribers.
+	notifiedEndpoints map[EndpointID]Endpoint
+	// dirty holds the latest value of the endpoints updated since the last
+	// notification, including the removed ones.
+	dirty       map[EndpointID]Endpoint
+	flushTimer  *time.Timer
+	subscribers []*subscriber
+	stop        chan struct{}
 }
 
-// ListAndWatch runs ListEndpoints on a regular interval and keeps the list.
+// ListAndWatch subscribes listener to endpoint changes. The endpoints already
+// known are sent to listener with OnAdd first. Polling of the Endpointslister
+// starts with the first subscriber and the initial listing is done before
+// ListAndWatch returns. Each subscriber is notified from its own goroutine,
+// so a slow subscriber doesn't hold back the others.
 func (ew *EndpointsWatcher) ListAndWatch(listener Notify) {
-	ew.existingEndpoints = map[EndpointID]Endpoint{}
-	ew.stop = make(chan struct{})
+	ew.mu.Lock()
+	ew.init()
+
+	sub := newSubscriber(listener)
+	if len(ew.notifiedEndpoints) > 0 {
+		sub.enqueue(notification{added: endpointsOf(ew.notifiedEndpoints)})
+	}
+	ew.subscribers = append(ew.subscribers, sub)
+	go sub.run()
+
+	startPolling := ew.Endpointslister != nil && ew.stop == nil
+	if startPolling {
+		ew.stop = make(chan struct{})
+	}
+	stop := ew.stop
+	ew.mu.Unlock()
+
+	if !startPolling {
+		return
+	}
 
 	// Do the initial listing immediately so that services can be monitored ASAP.
-	ew.refreshEndpoints(listener)
+	ew.refreshEndpoints()
 
 	go func() {
 		ticker := time.NewTicker(ew.RefreshInterval)
@@ -45,72 +87,337 @@ func (ew *EndpointsWatcher) ListAndWatch(listener Notify) {
 
 		for {
 			select {
-			case <-ew.stop:
+			case <-stop:
 				return
 			case <-ticker.C:
-				ew.refreshEndpoints(listener)
+				ew.refreshEndpoints()
 			}
 		}
 	}()
 }
 
-// refreshEndpoints updates the listener with the latest list
+// Unsubscribe stops sending endpoint changes to listener, which must be
+// the same comparable value passed into ListAndWatch. It waits for the
+// notification being delivered to listener, if any, to complete so it must
+// not be called from a Notify callback.
+func (ew *EndpointsWatcher) Unsubscribe(listener Notify) {
+	ew.mu.Lock()
+	var stopped []*subscriber
+	subscribers := ew.subscribers[:0]
+	for _, sub := range ew.subscribers {
+		if sub.listener == listener {
+			sub.stop()
+			stopped = append(stopped, sub)
+			continue
+		}
+		subscribers = append(subscribers, sub)
+	}
+	ew.subscribers = subscribers
+	ew.mu.Unlock()
+
+	for _, sub := range stopped {
+		<-sub.exited
+	}
+}
+
+// StopListAndWatch stops polling the ListEndpoints and unsubscribes all the
+// subscribers. It waits for notifications being delivered to complete so it
+// must not be called from a Notify callback.
+func (ew *EndpointsWatcher) StopListAndWatch() {
+	ew.mu.Lock()
+	if ew.stop != nil {
+		close(ew.stop)
+		ew.stop = nil
+	}
+	if ew.flushTimer != nil {
+		ew.flushTimer.Stop()
+		ew.flushTimer = nil
+	}
+	subscribers := ew.subscribers
+	ew.subscribers = nil
+	ew.mu.Unlock()
+
+	for _, sub := range subscribers {
+		sub.stop()
+		<-sub.exited
+	}
+}
+
+// OnAdd is used by observers pushing endpoint changes to report new endpoints.
+func (ew *EndpointsWatcher) OnAdd(added []Endpoint) {
+	ew.update(added, false)
+}
+
+// OnRemove is used by observers pushing endpoint changes to report removed endpoints.
+func (ew *EndpointsWatcher) OnRemove(removed []Endpoint) {
+	ew.update(removed, true)
+}
+
+// OnChange is used by observers pushing endpoint changes to report modified endpoints.
+func (ew *EndpointsWatcher) OnChange(changed []Endpoint) {
+	ew.update(changed, false)
+}
+
+func (ew *EndpointsWatcher) update(endpoints []Endpoint, remove bool) {
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	ew.init()
+
+	for _, e := range endpoints {
+		if remove {
+			delete(ew.existingEndpoints, e.ID)
+		} else {
+			ew.existingEndpoints[e.ID] = e
+		}
+		ew.dirty[e.ID] = e
+	}
+
+	ew.scheduleNotification()
+}
+
+// refreshEndpoints updates the existing endpoints with the latest list
 // of active endpoints.
-func (ew *EndpointsWatcher) refreshEndpoints(listener Notify) {
+func (ew *EndpointsWatcher) refreshEndpoints() {
 	latestEndpoints := ew.Endpointslister.ListEndpoints()
 
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	ew.init()
+
 	// Create map from ID to endpoint for lookup.
 	latestEndpointsMap := make(map[EndpointID]bool, len(latestEndpoints))
 	for _, e := range latestEndpoints {
 		latestEndpointsMap[e.ID] = true
-	}
 
-	var removedEndpoints, addedEndpoints, updatedEndpoints []Endpoint
-	// Iterate over the latest endpoints obtained. An endpoint needs
-	// to be added in case it is not already available in existingEndpoints.
-	for _, e := range latestEndpoints {
-		if existingEndpoint, ok := ew.existingEndpoints[e.ID]; !ok {
+		// An endpoint needs to be added or updated in case it is not already
+		// available in existingEndpoints or it has been modified.
+		if existingEndpoint, ok := ew.existingEndpoints[e.ID]; !ok || !reflect.DeepEqual(existingEndpoint, e) {
 			ew.existingEndpoints[e.ID] = e
-			addedEndpoints = append(addedEndpoints, e)
-		} else {
-			// Collect updated endpoints.
-			if !reflect.DeepEqual(existingEndpoint, e) {
-				ew.existingEndpoints[e.ID] = e
-				updatedEndpoints = append(updatedEndpoints, e)
-			}
+			ew.dirty[e.ID] = e
 		}
 	}
 
 	// If endpoint present in existingEndpoints does not exist in the latest
 	// list, it needs to be removed.
 	for id, e := range ew.existingEndpoints {
-		if !latestEndpointsMap[e.ID] {
+		if !latestEndpointsMap[id] {
 			delete(ew.existingEndpoints, id)
-			removedEndpoints = append(removedEndpoints, e)
+			ew.dirty[id] = e
 		}
 	}
 
-	if len(removedEndpoints) > 0 {
-		listener.OnRemove(removedEndpoints)
+	ew.scheduleNotification()
+}
+
+// scheduleNotification notifies the subscribers of the dirty endpoints, right
+// away or after DebounceInterval. Must be called with ew.mu held.
+func (ew *EndpointsWatcher) scheduleNotification() {
+	if len(ew.dirty) == 0 {
+		return
 	}
 
-	if len(addedEndpoints) > 0 {
-		listener.OnAdd(addedEndpoints)
+	if ew.DebounceInterval <= 0 {
+		ew.notify()
+		return
 	}
 
-	if len(updatedEndpoints) > 0 {
-		listener.OnChange(updatedEndpoints)
+	if ew.flushTimer == nil {
+		ew.flushTimer = time.AfterFunc(ew.DebounceInterval, func() {
+			ew.mu.Lock()
+			defer ew.mu.Unlock()
+			ew.flushTimer = nil
+			ew.notify()
+		})
 	}
 }
 
-// StopListAndWatch polling the ListEndpoints.
-func (ew *EndpointsWatcher) StopListAndWatch() {
-	close(ew.stop)
+// notify sends the difference between the notified and existing state of the
+// dirty endpoints to the subscribers. Endpoints that were updated several times
+// since the last notification are only sent once, and endpoints that are back to
+// their notified state aren't sent at all. Must be called with ew.mu held.
+func (ew *EndpointsWatcher) notify() {
+	var n notification
+	for id, latest := range ew.dirty {
+		notified, wasNotified := ew.notifiedEndpoints[id]
+		existing, exists := ew.existingEndpoints[id]
+
+		switch {
+		case exists && !wasNotified:
+			n.added = append(n.added, existing)
+			ew.notifiedEndpoints[id] = existing
+		case !exists && wasNotified:
+			n.removed = append(n.removed, latest)
+			delete(ew.notifiedEndpoints, id)
+		case exists && wasNotified && !reflect.DeepEqual(notified, existing):
+			n.changed = append(n.changed, existing)
+			ew.notifiedEndpoints[id] = existing
+		}
+	}
+	ew.dirty = map[EndpointID]Endpoint{}
+
+	if n.isEmpty() {
+		return
+	}
+	for _, sub := range ew.subscribers {
+		sub.enqueue(n)
+	}
+}
+
+// init lazily initializes the internal state. Must be called with ew.mu held.
+func (ew *EndpointsWatcher) init() {
+	if ew.existingEndpoints == nil {
+		ew.existingEndpoints = map[EndpointID]Endpoint{}
+	}
+	if ew.notifiedEndpoints == nil {
+		ew.notifiedEndpoints = map[EndpointID]Endpoint{}
+	}
+	if ew.dirty == nil {
+		ew.dirty = map[EndpointID]Endpoint{}
+	}
+}
+
+func endpointsOf(m map[EndpointID]Endpoint) []Endpoint {
+	endpoints := make([]Endpoint, 0, len(m))
+	for _, e := range m {
+		endpoints = append(endpoints, e)
+	}
+	return endpoints
 }
 
 // EndpointsLister that provides a list of endpoints.
 type EndpointsLister interface {
 	// ListEndpoints provides a list of endpoints and is expected to be
-	// implemented by an observer looking for endpoints.
+	// implemented by an observer looking for endpoints. Observers that are
+	// told about endpoint changes as they happen push them with the Notify
+	// methods of EndpointsWatcher instead.
 	ListEndpoints() []Endpoint
 }
+
+// notification is a batch of endpoint changes sent to a subscriber.
+type notification struct {
+	added   []Endpoint
+	removed []Endpoint
+	changed []Endpoint
+}
+
+func (n notification) isEmpty() bool {
+	return len(n.added) == 0 && len(n.removed) == 0 && len(n.changed) == 0
+}
+
+func (n notification) send(listener Notify) {
+	if len(n.removed) > 0 {
+		listener.OnRemove(n.removed)
+	}
+
+	if len(n.added) > 0 {
+		listener.OnAdd(n.added)
+	}
+
+	if len(n.changed) > 0 {
+		listener.OnChange(n.changed)
+	}
+}
+
+// subscriber delivers the endpoint changes to a Notify from its own goroutine
+// until stopped. The changes that are pending while the Notify is busy are
+// coalesced to the latest state of each endpoint, so that a slow Notify holds
+// at most one pending change per endpoint.
+type subscriber struct {
+	listener Notify
+	mu       sync.Mutex
+	// pending is the latest state of the endpoints changed since the last
+	// delivery.
+	pending map[EndpointID]pendingEndpoint
+	// delivered is the state of the endpoints last sent to listener. It is
+	// only accessed by run.
+	delivered map[EndpointID]Endpoint
+	wake      chan struct{}
+	done      chan struct{}
+	exited    chan struct{}
+}
+
+// pendingEndpoint is the latest state of an endpoint pending delivery.
+type pendingEndpoint struct {
+	endpoint Endpoint
+	removed  bool
+}
+
+func newSubscriber(listener Notify) *subscriber {
+	return &subscriber{
+		listener:  listener,
+		pending:   map[EndpointID]pendingEndpoint{},
+		delivered: map[EndpointID]Endpoint{},
+		wake:      make(chan struct{}, 1),
+		done:      make(chan struct{}),
+		exited:    make(chan struct{}),
+	}
+}
+
+func (s *subscriber) enqueue(n notification) {
+	s.mu.Lock()
+	for _, e := range n.removed {
+		s.pending[e.ID] = pendingEndpoint{endpoint: e, removed: true}
+	}
+	for _, e := range n.added {
+		s.pending[e.ID] = pendingEndpoint{endpoint: e}
+	}
+	for _, e := range n.changed {
+		s.pending[e.ID] = pendingEndpoint{endpoint: e}
+	}
+	s.mu.Unlock()
+
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+}
+
+// next returns the difference between the delivered and latest state of the
+// pending endpoints, and records it as delivered.
+func (s *subscriber) next() notification {
+	s.mu.Lock()
+	pending := s.pending
+	s.pending = map[EndpointID]pendingEndpoint{}
+	s.mu.Unlock()
+
+	var n notification
+	for id, latest := range pending {
+		delivered, wasDelivered := s.delivered[id]
+		switch {
+		case latest.removed && wasDelivered:
+			n.removed = append(n.removed, latest.endpoint)
+			delete(s.delivered, id)
+		case !latest.removed && !wasDelivered:
+			n.added = append(n.added, latest.endpoint)
+			s.delivered[id] = latest.endpoint
+		case !latest.removed && !reflect.DeepEqual(delivered, latest.endpoint):
+			n.changed = append(n.changed, latest.endpoint)
+			s.delivered[id] = latest.endpoint
+		}
+	}
+	return n
+}
+
+func (s *subscriber) run() {
+	defer close(s.exited)
+	for {
+		select {
+		case <-s.done:
+			return
+		case <-s.wake:
+		}
+
+		select {
+		case <-s.done:
+			return
+		default:
+		}
+		if n := s.next(); !n.isEmpty() {
+			n.send(s.listener)
+		}
+	}
+}
+
+func (s *subscriber) stop() {
+	close(s.done)
+}
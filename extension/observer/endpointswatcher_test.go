@@ -37,10 +37,10 @@ func TestRefreshEndpointsOnStartup(t *testing.T) {
 }
 
 func TestRefreshEndpoints(t *testing.T) {
-	ml, ew, mn := setup()
+	ml, ew, _ := setup()
 
 	ml.addEndpoint(0)
-	ew.refreshEndpoints(mn)
+	ew.refreshEndpoints()
 
 	expected := map[EndpointID]Endpoint{"0": {ID: "0"}}
 	require.Equal(t, expected, ew.existingEndpoints)
@@ -48,7 +48,7 @@ func TestRefreshEndpoints(t *testing.T) {
 	ml.addEndpoint(1)
 	ml.addEndpoint(2)
 	ml.removeEndpoint(0)
-	ew.refreshEndpoints(mn)
+	ew.refreshEndpoints()
 
 	expected["1"] = Endpoint{ID: "1"}
 	expected["2"] = Endpoint{ID: "2"}
@@ -56,18 +56,155 @@ func TestRefreshEndpoints(t *testing.T) {
 	require.Equal(t, expected, ew.existingEndpoints)
 
 	ml.updateEndpoint(2, "updated_target")
-	ew.refreshEndpoints(mn)
+	ew.refreshEndpoints()
 
 	expected["2"] = Endpoint{ID: "2", Target: "updated_target"}
 	require.Equal(t, expected, ew.existingEndpoints)
 }
 
-func setup() (*mockEndpointsLister, EndpointsWatcher, mockNotifier) {
+func TestPushedEndpoints(t *testing.T) {
+	ew := &EndpointsWatcher{}
+	rn := &recordingNotifier{}
+	ew.ListAndWatch(rn)
+	defer ew.StopListAndWatch()
+
+	ew.OnAdd([]Endpoint{{ID: "0"}, {ID: "1"}})
+	rn.assertEventually(t, recordedNotifications{added: []Endpoint{{ID: "0"}, {ID: "1"}}})
+
+	ew.OnChange([]Endpoint{{ID: "1", Target: "updated_target"}})
+	// Changes that don't modify the endpoint aren't sent.
+	ew.OnChange([]Endpoint{{ID: "0"}})
+	ew.OnRemove([]Endpoint{{ID: "0", Target: "removed_target"}})
+	rn.assertEventually(t, recordedNotifications{
+		added:   []Endpoint{{ID: "0"}, {ID: "1"}},
+		changed: []Endpoint{{ID: "1", Target: "updated_target"}},
+		// The latest value of removed endpoints is sent.
+		removed: []Endpoint{{ID: "0", Target: "removed_target"}},
+	})
+}
+
+func TestDebouncedEndpoints(t *testing.T) {
+	ew := &EndpointsWatcher{DebounceInterval: 100 * time.Millisecond}
+	rn := &recordingNotifier{}
+	ew.ListAndWatch(rn)
+	defer ew.StopListAndWatch()
+
+	ew.OnAdd([]Endpoint{{ID: "0"}})
+	ew.OnAdd([]Endpoint{{ID: "1"}})
+	ew.OnChange([]Endpoint{{ID: "1", Target: "updated_target"}})
+	// Endpoints added and removed within the interval are never sent.
+	ew.OnAdd([]Endpoint{{ID: "2"}})
+	ew.OnRemove([]Endpoint{{ID: "2"}})
+
+	rn.assertEventually(t, recordedNotifications{
+		added: []Endpoint{{ID: "0"}, {ID: "1", Target: "updated_target"}},
+	})
+	// All the changes are sent in a single batch.
+	require.Equal(t, 1, rn.calls())
+}
+
+func TestMultipleSubscribers(t *testing.T) {
+	ew := &EndpointsWatcher{}
+	defer ew.StopListAndWatch()
+
+	first := &recordingNotifier{}
+	ew.ListAndWatch(first)
+	ew.OnAdd([]Endpoint{{ID: "0"}})
+	first.assertEventually(t, recordedNotifications{added: []Endpoint{{ID: "0"}}})
+
+	// Late subscribers are sent the existing endpoints first.
+	second := &recordingNotifier{}
+	ew.ListAndWatch(second)
+	second.assertEventually(t, recordedNotifications{added: []Endpoint{{ID: "0"}}})
+
+	ew.Unsubscribe(first)
+	ew.OnAdd([]Endpoint{{ID: "1"}})
+	second.assertEventually(t, recordedNotifications{added: []Endpoint{{ID: "0"}, {ID: "1"}}})
+	first.assertEventually(t, recordedNotifications{added: []Endpoint{{ID: "0"}}})
+}
+
+func TestSlowSubscriber(t *testing.T) {
+	ew := &EndpointsWatcher{}
+	defer ew.StopListAndWatch()
+
+	release := make(chan struct{})
+	bn := &blockingNotifier{release: release}
+	ew.ListAndWatch(bn)
+
+	ew.OnAdd([]Endpoint{{ID: "0"}})
+	require.Eventually(t, func() bool { return bn.calls() == 1 }, time.Second, 10*time.Millisecond)
+
+	// The changes made while the subscriber is busy are coalesced to the
+	// latest state of each endpoint.
+	for i := 0; i < 100; i++ {
+		ew.OnChange([]Endpoint{{ID: "0", Target: strconv.Itoa(i)}})
+		ew.OnAdd([]Endpoint{{ID: "1"}})
+		ew.OnRemove([]Endpoint{{ID: "1"}})
+	}
+	ew.OnAdd([]Endpoint{{ID: "2"}})
+	for _, sub := range ew.subscribers {
+		sub.mu.Lock()
+		require.Len(t, sub.pending, 3)
+		sub.mu.Unlock()
+	}
+
+	close(release)
+	bn.assertEventually(t, recordedNotifications{
+		added:   []Endpoint{{ID: "0"}, {ID: "2"}},
+		changed: []Endpoint{{ID: "0", Target: "99"}},
+	})
+	require.Equal(t, 3, bn.calls())
+}
+
+func TestUnsubscribeWaitsForNotification(t *testing.T) {
+	ew := &EndpointsWatcher{}
+	defer ew.StopListAndWatch()
+
+	release := make(chan struct{})
+	bn := &blockingNotifier{release: release}
+	ew.ListAndWatch(bn)
+	ew.OnAdd([]Endpoint{{ID: "0"}})
+	require.Eventually(t, func() bool { return bn.calls() == 1 }, time.Second, 10*time.Millisecond)
+
+	unsubscribed := make(chan struct{})
+	go func() {
+		ew.Unsubscribe(bn)
+		close(unsubscribed)
+	}()
+	select {
+	case <-unsubscribed:
+		t.Fatal("Unsubscribe returned while a notification was being delivered")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	close(release)
+	select {
+	case <-unsubscribed:
+	case <-time.After(time.Second):
+		t.Fatal("Unsubscribe didn't return once the notification was delivered")
+	}
+}
+
+func TestStopListAndWatch(t *testing.T) {
+	ml, ew, _ := setup()
+	ml.addEndpoint(0)
+
+	rn := &recordingNotifier{}
+	ew.ListAndWatch(rn)
+	rn.assertEventually(t, recordedNotifications{added: []Endpoint{{ID: "0"}}})
+	ew.StopListAndWatch()
+
+	ew.OnAdd([]Endpoint{{ID: "1"}})
+	time.Sleep(50 * time.Millisecond)
+	require.Equal(t, 1, rn.calls())
+}
+
+func setup() (*mockEndpointsLister, *EndpointsWatcher, mockNotifier) {
 	ml := &mockEndpointsLister{
 		endpointsMap: map[EndpointID]Endpoint{},
 	}
 
-	ew := EndpointsWatcher{
+	ew := &EndpointsWatcher{
 		Endpointslister:   ml,
 		RefreshInterval:   2 * time.Second,
 		existingEndpoints: map[EndpointID]Endpoint{},
@@ -92,6 +229,87 @@ func (m mockNotifier) OnRemove([]Endpoint) {
 func (m mockNotifier) OnChange([]Endpoint) {
 }
 
+type recordedNotifications struct {
+	added   []Endpoint
+	removed []Endpoint
+	changed []Endpoint
+}
+
+type recordingNotifier struct {
+	sync.Mutex
+	recorded recordedNotifications
+	numCalls int
+}
+
+var _ Notify = (*recordingNotifier)(nil)
+
+func (r *recordingNotifier) OnAdd(added []Endpoint) {
+	r.Lock()
+	defer r.Unlock()
+	r.recorded.added = append(r.recorded.added, added...)
+	r.numCalls++
+}
+
+func (r *recordingNotifier) OnRemove(removed []Endpoint) {
+	r.Lock()
+	defer r.Unlock()
+	r.recorded.removed = append(r.recorded.removed, removed...)
+	r.numCalls++
+}
+
+func (r *recordingNotifier) OnChange(changed []Endpoint) {
+	r.Lock()
+	defer r.Unlock()
+	r.recorded.changed = append(r.recorded.changed, changed...)
+	r.numCalls++
+}
+
+func (r *recordingNotifier) calls() int {
+	r.Lock()
+	defer r.Unlock()
+	return r.numCalls
+}
+
+// assertEventually waits for the notifier to have recorded the expected
+// endpoints, in any order.
+func (r *recordingNotifier) assertEventually(t *testing.T, expected recordedNotifications) {
+	require.Eventually(t, func() bool {
+		r.Lock()
+		defer r.Unlock()
+		return sameEndpoints(expected.added, r.recorded.added) &&
+			sameEndpoints(expected.removed, r.recorded.removed) &&
+			sameEndpoints(expected.changed, r.recorded.changed)
+	}, time.Second, 10*time.Millisecond)
+}
+
+func sameEndpoints(expected, actual []Endpoint) bool {
+	if len(expected) != len(actual) {
+		return false
+	}
+	byID := make(map[EndpointID]Endpoint, len(actual))
+	for _, e := range actual {
+		byID[e.ID] = e
+	}
+	for _, e := range expected {
+		if a, ok := byID[e.ID]; !ok || a != e {
+			return false
+		}
+	}
+	return true
+}
+
+// blockingNotifier blocks in its first OnAdd until release is closed.
+type blockingNotifier struct {
+	recordingNotifier
+	release <-chan struct{}
+	once    sync.Once
+}
+
+func (b *blockingNotifier) OnAdd(added []Endpoint) {
+	b.recordingNotifier.OnAdd(added)
+	b.once.Do(func() { <-b.release })
+}
+
 type mockEndpointsLister struct {
 	sync.Mutex
 	endpointsMap map[EndpointID]Endpoint
@@ -27,4 +27,31 @@ type Config struct {
 	// RefreshInterval determines how frequency at which the observer
 	// needs to poll for collecting information about new processes.
 	RefreshInterval time.Duration `mapstructure:"refresh_interval"`
+
+	// Include limits the reported endpoints to the ones matching the filter.
+	// All endpoints are reported when it is empty.
+	Include FilterConfig `mapstructure:"include"`
+
+	// Exclude drops the endpoints matching the filter. It is applied after Include.
+	Exclude FilterConfig `mapstructure:"exclude"`
+}
+
+// FilterConfig selects endpoints by the process listening on them and their port.
+// An endpoint matches the filter when it matches all the criteria that are set,
+// and it matches a criterion when it matches any of its values.  Endpoints whose
+// process couldn't be determined never match the process criteria.
+type FilterConfig struct {
+	// ProcessNames is a list of process names, matched exactly.
+	ProcessNames []string `mapstructure:"process_names"`
+
+	// CommandLines is a list of regular expressions matched against the
+	// full command line of the process.
+	CommandLines []string `mapstructure:"command_lines"`
+
+	// Users is a list of names of the users owning the process.
+	Users []string `mapstructure:"users"`
+
+	// Ports is a list of ports (e.g. "8080") or inclusive port ranges
+	// (e.g. "8000-8100").
+	Ports []string `mapstructure:"ports"`
 }
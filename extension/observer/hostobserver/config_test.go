@@ -50,6 +50,14 @@ func TestLoadConfig(t *testing.T) {
 				NameVal: "host_observer/all_settings",
 			},
 			RefreshInterval: 20 * time.Second,
+			Include: FilterConfig{
+				Ports: []string{"1024-65535"},
+			},
+			Exclude: FilterConfig{
+				ProcessNames: []string{"otelcontribcol"},
+				CommandLines: []string{"^/usr/bin/kubelet"},
+				Users:        []string{"root"},
+			},
 		},
 		ext1)
 }
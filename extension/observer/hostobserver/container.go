@@ -0,0 +1,69 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+var containerIDRe = regexp.MustCompile(`[0-9a-f]{64}`)
+
+// containerIDForPid returns the ID of the container the process is running
+// in, or an empty string if it isn't running in a container or the cgroups
+// of the process can't be read.
+func containerIDForPid(pid int32) string {
+	f, err := os.Open(procPath(strconv.Itoa(int(pid)), "cgroup"))
+	if err != nil {
+		return ""
+	}
+	defer f.Close()
+
+	return parseContainerID(f)
+}
+
+// parseContainerID looks for a container ID in the content of a
+// /proc/<pid>/cgroup file. The cgroup paths of containerized processes end
+// with the container ID, e.g. "4:memory:/docker/<id>",
+// "0::/system.slice/docker-<id>.scope" or "3:cpu:/kubepods/besteffort/pod<uid>/<id>".
+func parseContainerID(r io.Reader) string {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), ":", 3)
+		if len(parts) != 3 {
+			continue
+		}
+		if id := containerIDRe.FindString(path.Base(parts[2])); id != "" {
+			return id
+		}
+	}
+	return ""
+}
+
+// procPath joins elem to the proc filesystem root, which can be overridden
+// with the HOST_PROC environment variable like gopsutil does.
+func procPath(elem ...string) string {
+	root := os.Getenv("HOST_PROC")
+	if root == "" {
+		root = "/proc"
+	}
+	return filepath.Join(append([]string{root}, elem...)...)
+}
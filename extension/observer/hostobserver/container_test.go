@@ -0,0 +1,68 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+const testContainerID = "6ba7b8116cbad2c4f2e1f1e4dbbb1c2a8f2f4cd8ee6e9f1d0fbd3d4f0a4e0a1b"
+
+func TestParseContainerID(t *testing.T) {
+	tests := []struct {
+		name   string
+		cgroup string
+		want   string
+	}{
+		{
+			name: "docker cgroup v1",
+			cgroup: "12:pids:/docker/" + testContainerID + "\n" +
+				"4:memory:/docker/" + testContainerID + "\n",
+			want: testContainerID,
+		},
+		{
+			name:   "systemd cgroup driver",
+			cgroup: "0::/system.slice/docker-" + testContainerID + ".scope\n",
+			want:   testContainerID,
+		},
+		{
+			name:   "kubernetes",
+			cgroup: "3:cpu,cpuacct:/kubepods/besteffort/pod1f8e2c4e-5a7b-4c1d-9e3f-2b6a7c8d9e0f/" + testContainerID + "\n",
+			want:   testContainerID,
+		},
+		{
+			name:   "host process",
+			cgroup: "12:pids:/user.slice/user-1000.slice\n0::/user.slice/user-1000.slice/session-2.scope\n",
+			want:   "",
+		},
+		{
+			name:   "malformed",
+			cgroup: "not a cgroup line\n",
+			want:   "",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.want, parseContainerID(strings.NewReader(tt.cgroup)))
+		})
+	}
+}
+
+func TestContainerIDForPidMissingProcess(t *testing.T) {
+	assert.Equal(t, "", containerIDForPid(-1))
+}
@@ -35,6 +35,9 @@ type hostObserver struct {
 type endpointsLister struct {
 	logger       *zap.Logger
 	observerName string
+	// include and exclude are nil when not configured.
+	include *endpointFilter
+	exclude *endpointFilter
 
 	// For testing
 	getConnections        func() ([]net.ConnectionStat, error)
@@ -45,16 +48,33 @@ type endpointsLister struct {
 var _ component.ServiceExtension = (*hostObserver)(nil)
 
 func newObserver(logger *zap.Logger, config *Config) (component.ServiceExtension, error) {
+	include, err := newEndpointFilter(config.Include)
+	if err != nil {
+		return nil, fmt.Errorf("invalid include filter: %v", err)
+	}
+	exclude, err := newEndpointFilter(config.Exclude)
+	if err != nil {
+		return nil, fmt.Errorf("invalid exclude filter: %v", err)
+	}
+
+	lister := endpointsLister{
+		logger:                logger,
+		observerName:          config.Name(),
+		getConnections:        getConnections,
+		getProcess:            process.NewProcess,
+		collectProcessDetails: collectProcessDetails,
+	}
+	if !include.isEmpty() {
+		lister.include = include
+	}
+	if !exclude.isEmpty() {
+		lister.exclude = exclude
+	}
+
 	h := &hostObserver{
 		EndpointsWatcher: observer.EndpointsWatcher{
 			RefreshInterval: config.RefreshInterval,
-			Endpointslister: endpointsLister{
-				logger:                logger,
-				observerName:          config.Name(),
-				getConnections:        getConnections,
-				getProcess:            process.NewProcess,
-				collectProcessDetails: collectProcessDetails,
-			},
+			Endpointslister: lister,
 		},
 	}
 
@@ -115,6 +135,9 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 		// still do discovery rules on such sockets.
 		if c.Pid == 0 {
 			cd := collectConnectionDetails(&c)
+			if !e.shouldReport(cd.port, nil) {
+				continue
+			}
 			id := observer.EndpointID(
 				fmt.Sprintf(
 					"(%s)%s-%d-%s", e.observerName, cd.ip, cd.port, cd.transport,
@@ -155,6 +178,9 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 
 		for _, c := range conns {
 			cd := collectConnectionDetails(c)
+			if !e.shouldReport(cd.port, pd) {
+				continue
+			}
 
 			id := observer.EndpointID(
 				fmt.Sprintf(
@@ -167,10 +193,11 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 				ID:     id,
 				Target: cd.target,
 				Details: observer.HostPort{
-					Name:      pd.name,
-					Command:   pd.args,
-					Port:      cd.port,
-					Transport: cd.transport,
+					Name:        pd.name,
+					Command:     pd.args,
+					Port:        cd.port,
+					Transport:   cd.transport,
+					ContainerID: pd.containerID,
 					// TODO: Move this field to observer.Endpoint and
 					// update receiver_creator to filter IPv4/IPv6.
 					IsIPv6: cd.isIPv6,
@@ -183,6 +210,18 @@ func (e endpointsLister) collectEndpoints(conns []net.ConnectionStat) []observer
 	return endpoints
 }
 
+// shouldReport returns true if the endpoint on port owned by the process with
+// details pd passes the configured filters. pd is nil when the process is unknown.
+func (e endpointsLister) shouldReport(port uint16, pd *processDetails) bool {
+	if e.include != nil && !e.include.matches(port, pd) {
+		return false
+	}
+	if e.exclude != nil && e.exclude.matches(port, pd) {
+		return false
+	}
+	return true
+}
+
 type connectionDetails struct {
 	ip        string
 	isIPv6    bool
@@ -221,8 +260,10 @@ func collectConnectionDetails(c *net.ConnectionStat) connectionDetails {
 }
 
 type processDetails struct {
-	name string
-	args string
+	name        string
+	args        string
+	username    string
+	containerID string
 }
 
 func collectProcessDetails(proc *process.Process) (*processDetails, error) {
@@ -236,9 +277,15 @@ func collectProcessDetails(proc *process.Process) (*processDetails, error) {
 		return nil, fmt.Errorf("could not get process args: %v", err)
 	}
 
+	// The user isn't always known (e.g. processes running in a container
+	// under a uid that doesn't exist on the host), which isn't an error.
+	username, _ := proc.Username()
+
 	return &processDetails{
-		name: name,
-		args: args,
+		name:        name,
+		args:        args,
+		username:    username,
+		containerID: containerIDForPid(proc.Pid),
 	}, nil
 }
 
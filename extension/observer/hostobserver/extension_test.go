@@ -438,6 +438,8 @@ func TestCollectEndpoints(t *testing.T) {
 		conns       []psnet.ConnectionStat
 		newProc     func(pid int32) (*process.Process, error)
 		procDetails func(proc *process.Process) (*processDetails, error)
+		include     FilterConfig
+		exclude     FilterConfig
 		want        []observer.Endpoint
 	}{
 		{
@@ -524,6 +526,153 @@ func TestCollectEndpoints(t *testing.T) {
 			},
 			want: []observer.Endpoint{},
 		},
+		{
+			name: "Include process name",
+			conns: []psnet.ConnectionStat{
+				{
+					Family: syscall.AF_INET,
+					Type:   syscall.SOCK_STREAM,
+					Laddr: psnet.Addr{
+						IP:   "127.0.0.1",
+						Port: 6379,
+					},
+					Status: "LISTEN",
+					Pid:    9999,
+				},
+				{
+					Family: syscall.AF_INET,
+					Type:   syscall.SOCK_STREAM,
+					Laddr: psnet.Addr{
+						IP:   "0.0.0.0",
+						Port: 22,
+					},
+					Status: "LISTEN",
+					Pid:    0,
+				},
+			},
+			newProc: func(pid int32) (*process.Process, error) {
+				return &process.Process{Pid: pid}, nil
+			},
+			procDetails: func(proc *process.Process) (*processDetails, error) {
+				return &processDetails{
+					name:        "redis-server",
+					args:        "redis-server *:6379",
+					username:    "redis",
+					containerID: testContainerID,
+				}, nil
+			},
+			include: FilterConfig{ProcessNames: []string{"redis-server"}},
+			want: []observer.Endpoint{
+				{
+					ID:     observer.EndpointID("()127.0.0.1-6379-TCP-9999"),
+					Target: "127.0.0.1:6379",
+					Details: observer.HostPort{
+						Name:        "redis-server",
+						Command:     "redis-server *:6379",
+						Port:        6379,
+						Transport:   observer.ProtocolTCP,
+						ContainerID: testContainerID,
+					},
+				},
+			},
+		},
+		{
+			name: "Exclude port",
+			conns: []psnet.ConnectionStat{
+				{
+					Family: syscall.AF_INET,
+					Type:   syscall.SOCK_STREAM,
+					Laddr: psnet.Addr{
+						IP:   "127.0.0.1",
+						Port: 6379,
+					},
+					Status: "LISTEN",
+					Pid:    9999,
+				},
+				{
+					Family: syscall.AF_INET,
+					Type:   syscall.SOCK_STREAM,
+					Laddr: psnet.Addr{
+						IP:   "0.0.0.0",
+						Port: 22,
+					},
+					Status: "LISTEN",
+					Pid:    0,
+				},
+			},
+			newProc: func(pid int32) (*process.Process, error) {
+				return &process.Process{Pid: pid}, nil
+			},
+			procDetails: func(proc *process.Process) (*processDetails, error) {
+				return &processDetails{
+					name:        "redis-server",
+					args:        "redis-server *:6379",
+					username:    "redis",
+					containerID: testContainerID,
+				}, nil
+			},
+			exclude: FilterConfig{Ports: []string{"1-1024"}},
+			want: []observer.Endpoint{
+				{
+					ID:     observer.EndpointID("()127.0.0.1-6379-TCP-9999"),
+					Target: "127.0.0.1:6379",
+					Details: observer.HostPort{
+						Name:        "redis-server",
+						Command:     "redis-server *:6379",
+						Port:        6379,
+						Transport:   observer.ProtocolTCP,
+						ContainerID: testContainerID,
+					},
+				},
+			},
+		},
+		{
+			name: "Exclude user",
+			conns: []psnet.ConnectionStat{
+				{
+					Family: syscall.AF_INET,
+					Type:   syscall.SOCK_STREAM,
+					Laddr: psnet.Addr{
+						IP:   "127.0.0.1",
+						Port: 6379,
+					},
+					Status: "LISTEN",
+					Pid:    9999,
+				},
+				{
+					Family: syscall.AF_INET,
+					Type:   syscall.SOCK_STREAM,
+					Laddr: psnet.Addr{
+						IP:   "0.0.0.0",
+						Port: 22,
+					},
+					Status: "LISTEN",
+					Pid:    0,
+				},
+			},
+			newProc: func(pid int32) (*process.Process, error) {
+				return &process.Process{Pid: pid}, nil
+			},
+			procDetails: func(proc *process.Process) (*processDetails, error) {
+				return &processDetails{
+					name:        "redis-server",
+					args:        "redis-server *:6379",
+					username:    "redis",
+					containerID: testContainerID,
+				}, nil
+			},
+			exclude: FilterConfig{Users: []string{"redis"}},
+			want: []observer.Endpoint{
+				{
+					ID:     observer.EndpointID("()127.0.0.1-22-TCP"),
+					Target: "127.0.0.1:22",
+					Details: observer.HostPort{
+						Port:      22,
+						Transport: observer.ProtocolTCP,
+					},
+				},
+			},
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -541,6 +690,17 @@ func TestCollectEndpoints(t *testing.T) {
 				e.getProcess = tt.newProc
 			}
 
+			include, err := newEndpointFilter(tt.include)
+			require.NoError(t, err)
+			if !include.isEmpty() {
+				e.include = include
+			}
+			exclude, err := newEndpointFilter(tt.exclude)
+			require.NoError(t, err)
+			if !exclude.isEmpty() {
+				e.exclude = exclude
+			}
+
 			require.NotNil(t, e.collectProcessDetails)
 			require.NotNil(t, e.getProcess)
 
@@ -0,0 +1,136 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+type portRange struct {
+	start uint16
+	end   uint16
+}
+
+// endpointFilter is the compiled form of a FilterConfig.
+type endpointFilter struct {
+	processNames map[string]bool
+	commandLines []*regexp.Regexp
+	users        map[string]bool
+	portRanges   []portRange
+}
+
+func newEndpointFilter(cfg FilterConfig) (*endpointFilter, error) {
+	f := &endpointFilter{}
+
+	if len(cfg.ProcessNames) > 0 {
+		f.processNames = make(map[string]bool, len(cfg.ProcessNames))
+		for _, name := range cfg.ProcessNames {
+			f.processNames[name] = true
+		}
+	}
+
+	for _, expr := range cfg.CommandLines {
+		re, err := regexp.Compile(expr)
+		if err != nil {
+			return nil, fmt.Errorf("invalid command line regex %q: %v", expr, err)
+		}
+		f.commandLines = append(f.commandLines, re)
+	}
+
+	if len(cfg.Users) > 0 {
+		f.users = make(map[string]bool, len(cfg.Users))
+		for _, user := range cfg.Users {
+			f.users[user] = true
+		}
+	}
+
+	for _, ports := range cfg.Ports {
+		pr, err := parsePortRange(ports)
+		if err != nil {
+			return nil, err
+		}
+		f.portRanges = append(f.portRanges, pr)
+	}
+
+	return f, nil
+}
+
+func parsePortRange(s string) (portRange, error) {
+	parts := strings.SplitN(s, "-", 2)
+	start, err := strconv.ParseUint(strings.TrimSpace(parts[0]), 10, 16)
+	if err != nil {
+		return portRange{}, fmt.Errorf("invalid port range %q: %v", s, err)
+	}
+	end := start
+	if len(parts) == 2 {
+		end, err = strconv.ParseUint(strings.TrimSpace(parts[1]), 10, 16)
+		if err != nil {
+			return portRange{}, fmt.Errorf("invalid port range %q: %v", s, err)
+		}
+	}
+	if start > end {
+		return portRange{}, fmt.Errorf("invalid port range %q: start is greater than end", s)
+	}
+	return portRange{start: uint16(start), end: uint16(end)}, nil
+}
+
+// isEmpty returns true if the filter has no criteria.
+func (f *endpointFilter) isEmpty() bool {
+	return f.processNames == nil && f.commandLines == nil && f.users == nil && f.portRanges == nil
+}
+
+// matches returns true if the endpoint on port owned by the process with
+// details pd matches all criteria of the filter. pd is nil when the process
+// is unknown.
+func (f *endpointFilter) matches(port uint16, pd *processDetails) bool {
+	if f.processNames != nil && (pd == nil || !f.processNames[pd.name]) {
+		return false
+	}
+
+	if f.commandLines != nil && (pd == nil || !f.matchesCommandLine(pd.args)) {
+		return false
+	}
+
+	if f.users != nil && (pd == nil || !f.users[pd.username]) {
+		return false
+	}
+
+	if f.portRanges != nil && !f.matchesPort(port) {
+		return false
+	}
+
+	return true
+}
+
+func (f *endpointFilter) matchesCommandLine(cmdline string) bool {
+	for _, re := range f.commandLines {
+		if re.MatchString(cmdline) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f *endpointFilter) matchesPort(port uint16) bool {
+	for _, pr := range f.portRanges {
+		if port >= pr.start && port <= pr.end {
+			return true
+		}
+	}
+	return false
+}
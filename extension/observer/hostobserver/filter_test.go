@@ -0,0 +1,155 @@
+// Copyright The OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package hostobserver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePortRange(t *testing.T) {
+	tests := []struct {
+		name    string
+		ports   string
+		want    portRange
+		wantErr bool
+	}{
+		{name: "single port", ports: "8080", want: portRange{start: 8080, end: 8080}},
+		{name: "range", ports: "8000-8100", want: portRange{start: 8000, end: 8100}},
+		{name: "range with spaces", ports: "8000 - 8100", want: portRange{start: 8000, end: 8100}},
+		{name: "not a number", ports: "http", wantErr: true},
+		{name: "out of range", ports: "70000", wantErr: true},
+		{name: "invalid end", ports: "8000-", wantErr: true},
+		{name: "start greater than end", ports: "8100-8000", wantErr: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parsePortRange(tt.ports)
+			if tt.wantErr {
+				require.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func TestNewEndpointFilterErrors(t *testing.T) {
+	_, err := newEndpointFilter(FilterConfig{CommandLines: []string{"("}})
+	assert.Error(t, err)
+
+	_, err = newEndpointFilter(FilterConfig{Ports: []string{"abc"}})
+	assert.Error(t, err)
+}
+
+func TestEndpointFilterMatches(t *testing.T) {
+	redis := &processDetails{
+		name:     "redis-server",
+		args:     "/usr/bin/redis-server 127.0.0.1:6379",
+		username: "redis",
+	}
+
+	tests := []struct {
+		name   string
+		filter FilterConfig
+		port   uint16
+		pd     *processDetails
+		want   bool
+	}{
+		{
+			name:   "empty filter",
+			filter: FilterConfig{},
+			port:   6379,
+			pd:     redis,
+			want:   true,
+		},
+		{
+			name:   "process name",
+			filter: FilterConfig{ProcessNames: []string{"sshd", "redis-server"}},
+			port:   6379,
+			pd:     redis,
+			want:   true,
+		},
+		{
+			name:   "process name mismatch",
+			filter: FilterConfig{ProcessNames: []string{"sshd"}},
+			port:   6379,
+			pd:     redis,
+			want:   false,
+		},
+		{
+			name:   "command line",
+			filter: FilterConfig{CommandLines: []string{"^/usr/bin/redis"}},
+			port:   6379,
+			pd:     redis,
+			want:   true,
+		},
+		{
+			name:   "user",
+			filter: FilterConfig{Users: []string{"redis"}},
+			port:   6379,
+			pd:     redis,
+			want:   true,
+		},
+		{
+			name:   "port range",
+			filter: FilterConfig{Ports: []string{"22", "6000-7000"}},
+			port:   6379,
+			pd:     redis,
+			want:   true,
+		},
+		{
+			name:   "port mismatch",
+			filter: FilterConfig{Ports: []string{"22"}},
+			port:   6379,
+			pd:     redis,
+			want:   false,
+		},
+		{
+			name: "all criteria must match",
+			filter: FilterConfig{
+				ProcessNames: []string{"redis-server"},
+				Users:        []string{"root"},
+			},
+			port: 6379,
+			pd:   redis,
+			want: false,
+		},
+		{
+			name:   "unknown process never matches process criteria",
+			filter: FilterConfig{ProcessNames: []string{""}},
+			port:   6379,
+			pd:     nil,
+			want:   false,
+		},
+		{
+			name:   "unknown process matches port",
+			filter: FilterConfig{Ports: []string{"6379"}},
+			port:   6379,
+			pd:     nil,
+			want:   true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			f, err := newEndpointFilter(tt.filter)
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, f.matches(tt.port, tt.pd))
+		})
+	}
+}
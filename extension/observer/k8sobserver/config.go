@@ -15,6 +15,9 @@
 package k8sobserver
 
 import (
+	"errors"
+	"time"
+
 	"go.opentelemetry.io/collector/config/configmodels"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
@@ -36,4 +39,25 @@ type Config struct {
 	//
 	// Then set this value to ${K8S_NODE_NAME} in the configuration.
 	Node string `mapstructure:"node"`
+	// ObservePods determines whether to report observer pod and port endpoints. Only pods whose
+	// `spec.nodeName` matches Node are discovered.
+	ObservePods bool `mapstructure:"observe_pods"`
+	// ObserveServices determines whether to report observer service endpoints. Services are discovered
+	// cluster wide regardless of Node.
+	ObserveServices bool `mapstructure:"observe_services"`
+	// ObserveNodes determines whether to report observer k8s.node endpoints. If `true` and Node is specified
+	// it will only discover node endpoints whose `metadata.name` matches the provided node name.
+	ObserveNodes bool `mapstructure:"observe_nodes"`
+	// DebounceInterval is how long endpoint changes are batched after the first one before being
+	// reported, so that bursts of changes, e.g. during a rollout, are reported at once. Changes are
+	// reported right away when it is zero, the default.
+	DebounceInterval time.Duration `mapstructure:"debounce_interval"`
+}
+
+// validate checks that at least one kind of endpoint is observed.
+func (cfg *Config) validate() error {
+	if !cfg.ObservePods && !cfg.ObserveServices && !cfg.ObserveNodes {
+		return errors.New("one of observe_pods, observe_services or observe_nodes must be true")
+	}
+	return nil
 }
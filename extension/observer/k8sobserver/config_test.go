@@ -17,6 +17,7 @@ package k8sobserver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -50,8 +51,12 @@ func TestLoadConfig(t *testing.T) {
 				TypeVal: "k8s_observer",
 				NameVal: "k8s_observer/1",
 			},
-			Node:      "node-1",
-			APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			Node:             "node-1",
+			APIConfig:        k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
+			ObservePods:      true,
+			ObserveServices:  true,
+			ObserveNodes:     true,
+			DebounceInterval: 2 * time.Second,
 		},
 		ext1)
 }
@@ -26,31 +26,52 @@ import (
 )
 
 type k8sObserver struct {
-	logger   *zap.Logger
-	informer cache.SharedInformer
-	stop     chan struct{}
-	config   *Config
+	// EndpointsWatcher is pushed the endpoint changes from the informers.
+	observer.EndpointsWatcher
+	logger    *zap.Logger
+	informers []cache.SharedInformer
+	stop      chan struct{}
+	config    *Config
 }
 
 func (k *k8sObserver) Start(ctx context.Context, host component.Host) error {
-	go k.informer.Run(k.stop)
+	for _, informer := range k.informers {
+		go informer.Run(k.stop)
+	}
 	return nil
 }
 
 func (k *k8sObserver) Shutdown(ctx context.Context) error {
 	close(k.stop)
+	k.StopListAndWatch()
 	return nil
 }
 
 var _ (component.ServiceExtension) = (*k8sObserver)(nil)
 
-// ListAndWatch notifies watcher with the current state and sends subsequent state changes.
-func (k *k8sObserver) ListAndWatch(listener observer.Notify) {
-	k.informer.AddEventHandler(&handler{watcher: listener, idNamespace: k.config.Name()})
-}
-
-// newObserver creates a new k8s observer extension.
-func newObserver(logger *zap.Logger, config *Config, listWatch cache.ListerWatcher) (component.ServiceExtension, error) {
-	informer := cache.NewSharedInformer(listWatch, &v1.Pod{}, 0)
-	return &k8sObserver{logger: logger, informer: informer, stop: make(chan struct{}), config: config}, nil
+// newObserver creates a new k8s observer extension. An informer is created for each
+// non-nil ListerWatcher.
+func newObserver(
+	logger *zap.Logger,
+	config *Config,
+	podListerWatcher cache.ListerWatcher,
+	serviceListerWatcher cache.ListerWatcher,
+	nodeListerWatcher cache.ListerWatcher,
+) (component.ServiceExtension, error) {
+	var informers []cache.SharedInformer
+	if podListerWatcher != nil {
+		informers = append(informers, cache.NewSharedInformer(podListerWatcher, &v1.Pod{}, 0))
+	}
+	if serviceListerWatcher != nil {
+		informers = append(informers, cache.NewSharedInformer(serviceListerWatcher, &v1.Service{}, 0))
+	}
+	if nodeListerWatcher != nil {
+		informers = append(informers, cache.NewSharedInformer(nodeListerWatcher, &v1.Node{}, 0))
+	}
+	k := &k8sObserver{logger: logger, informers: informers, stop: make(chan struct{}), config: config}
+	k.EndpointsWatcher.DebounceInterval = config.DebounceInterval
+	for _, informer := range informers {
+		informer.AddEventHandler(&handler{watcher: &k.EndpointsWatcher, idNamespace: config.Name()})
+	}
+	return k, nil
 }
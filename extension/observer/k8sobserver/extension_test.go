@@ -30,7 +30,7 @@ import (
 func TestNewExtension(t *testing.T) {
 	listWatch := framework.NewFakeControllerSource()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 }
@@ -38,7 +38,7 @@ func TestNewExtension(t *testing.T) {
 func TestExtensionObserve(t *testing.T) {
 	listWatch := framework.NewFakeControllerSource()
 	factory := &Factory{}
-	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch)
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), listWatch, nil, nil)
 	require.NoError(t, err)
 	require.NotNil(t, ext)
 	obs := ext.(*k8sObserver)
@@ -58,7 +58,9 @@ func TestExtensionObserve(t *testing.T) {
 		ID:     "k8s_observer/pod1-UID",
 		Target: "1.2.3.4",
 		Details: observer.Pod{
-			Name: "pod1",
+			Name:      "pod1",
+			UID:       "pod1-UID",
+			Namespace: "default",
 			Labels: map[string]string{
 				"env": "prod",
 			},
@@ -75,7 +77,9 @@ func TestExtensionObserve(t *testing.T) {
 		ID:     "k8s_observer/pod1-UID",
 		Target: "1.2.3.4",
 		Details: observer.Pod{
-			Name: "pod1",
+			Name:      "pod1",
+			UID:       "pod1-UID",
+			Namespace: "default",
 			Labels: map[string]string{
 				"env":         "prod",
 				"pod-version": "2",
@@ -85,3 +89,37 @@ func TestExtensionObserve(t *testing.T) {
 
 	require.NoError(t, ext.Shutdown(context.Background()))
 }
+
+func TestExtensionObserveServicesAndNodes(t *testing.T) {
+	serviceListWatch := framework.NewFakeControllerSource()
+	nodeListWatch := framework.NewFakeControllerSource()
+	factory := &Factory{}
+	ext, err := newObserver(zap.NewNop(), factory.CreateDefaultConfig().(*Config), nil, serviceListWatch, nodeListWatch)
+	require.NoError(t, err)
+	require.NotNil(t, ext)
+	obs := ext.(*k8sObserver)
+	require.Len(t, obs.informers, 2)
+
+	serviceListWatch.Add(service1)
+	nodeListWatch.Add(node1)
+
+	require.NoError(t, ext.Start(context.Background(), componenttest.NewNopHost()))
+
+	sink := &endpointSink{}
+	obs.ListAndWatch(sink)
+
+	assertSink(t, sink, func() bool {
+		return len(sink.added) == 2
+	})
+
+	var ids []observer.EndpointID
+	for _, e := range sink.added {
+		ids = append(ids, e.ID)
+	}
+	assert.ElementsMatch(t, []observer.EndpointID{
+		"k8s_observer/service/service-1-UID/http(8080)",
+		"k8s_observer/node/node-1-UID",
+	}, ids)
+
+	require.NoError(t, ext.Shutdown(context.Background()))
+}
@@ -54,7 +54,8 @@ func (f *Factory) CreateDefaultConfig() configmodels.Extension {
 			TypeVal: typeStr,
 			NameVal: string(typeStr),
 		},
-		APIConfig: k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		APIConfig:   k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+		ObservePods: true,
 	}
 }
 
@@ -65,17 +66,39 @@ func (f *Factory) CreateExtension(
 	cfg configmodels.Extension,
 ) (component.ServiceExtension, error) {
 	config := cfg.(*Config)
+	if err := config.validate(); err != nil {
+		return nil, err
+	}
 
 	clientset, err := f.createK8sClientset(config.APIConfig)
 	if err != nil {
 		return nil, err
 	}
+	restClient := clientset.CoreV1().RESTClient()
+
+	var podListerWatcher, serviceListerWatcher, nodeListerWatcher cache.ListerWatcher
+
+	if config.ObservePods {
+		podListerWatcher = cache.NewListWatchFromClient(
+			restClient, "pods", v1.NamespaceAll,
+			fields.OneTermEqualSelector("spec.nodeName", config.Node))
+	}
+
+	if config.ObserveServices {
+		serviceListerWatcher = cache.NewListWatchFromClient(restClient, "services", v1.NamespaceAll, fields.Everything())
+	}
 
-	listWatch := cache.NewListWatchFromClient(
-		clientset.CoreV1().RESTClient(), "pods", v1.NamespaceAll,
-		fields.OneTermEqualSelector("spec.nodeName", config.Node))
+	if config.ObserveNodes {
+		var nodeSelector fields.Selector
+		if config.Node == "" {
+			nodeSelector = fields.Everything()
+		} else {
+			nodeSelector = fields.OneTermEqualSelector("metadata.name", config.Node)
+		}
+		nodeListerWatcher = cache.NewListWatchFromClient(restClient, "nodes", v1.NamespaceAll, nodeSelector)
+	}
 
-	return newObserver(params.Logger, config, listWatch)
+	return newObserver(params.Logger, config, podListerWatcher, serviceListerWatcher, nodeListerWatcher)
 }
 
 // NewFactory should be called to create a factory with default values.
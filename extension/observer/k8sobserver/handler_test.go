@@ -18,6 +18,9 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/client-go/tools/cache"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
@@ -34,8 +37,10 @@ func TestEndpointsAdded(t *testing.T) {
 			ID:     "test-1/pod-2-UID",
 			Target: "1.2.3.4",
 			Details: observer.Pod{
-				Name:   "pod-2",
-				Labels: map[string]string{"env": "prod"},
+				Name:      "pod-2",
+				UID:       "pod-2-UID",
+				Namespace: "default",
+				Labels:    map[string]string{"env": "prod"},
 			},
 		}, {
 			ID:     "test-1/pod-2-UID/https(443)",
@@ -43,11 +48,15 @@ func TestEndpointsAdded(t *testing.T) {
 			Details: observer.Port{
 				Name: "https",
 				Pod: observer.Pod{
-					Name:   "pod-2",
-					Labels: map[string]string{"env": "prod"},
+					Name:      "pod-2",
+					UID:       "pod-2-UID",
+					Namespace: "default",
+					Labels:    map[string]string{"env": "prod"},
 				},
-				Port:      443,
-				Transport: observer.ProtocolTCP,
+				ContainerName:  "container-2",
+				ContainerImage: "container-image-2",
+				Port:           443,
+				Transport:      observer.ProtocolTCP,
 			},
 		}}, sink.added)
 	assert.Nil(t, sink.removed)
@@ -66,8 +75,10 @@ func TestEndpointsRemoved(t *testing.T) {
 			ID:     "test-1/pod-2-UID",
 			Target: "1.2.3.4",
 			Details: observer.Pod{
-				Name:   "pod-2",
-				Labels: map[string]string{"env": "prod"},
+				Name:      "pod-2",
+				UID:       "pod-2-UID",
+				Namespace: "default",
+				Labels:    map[string]string{"env": "prod"},
 			},
 		}, {
 			ID:     "test-1/pod-2-UID/https(443)",
@@ -75,11 +86,15 @@ func TestEndpointsRemoved(t *testing.T) {
 			Details: observer.Port{
 				Name: "https",
 				Pod: observer.Pod{
-					Name:   "pod-2",
-					Labels: map[string]string{"env": "prod"},
+					Name:      "pod-2",
+					UID:       "pod-2-UID",
+					Namespace: "default",
+					Labels:    map[string]string{"env": "prod"},
 				},
-				Port:      443,
-				Transport: observer.ProtocolTCP,
+				ContainerName:  "container-2",
+				ContainerImage: "container-image-2",
+				Port:           443,
+				Transport:      observer.ProtocolTCP,
 			},
 		}}, sink.removed)
 	assert.Nil(t, sink.added)
@@ -121,16 +136,113 @@ func TestEndpointsChanged(t *testing.T) {
 			ID:     "test-1/pod-2-UID",
 			Target: "1.2.3.4",
 			Details: observer.Pod{
-				Name:   "pod-2",
-				Labels: map[string]string{"env": "prod", "updated-label": "true"}}},
+				Name:      "pod-2",
+				UID:       "pod-2-UID",
+				Namespace: "default",
+				Labels:    map[string]string{"env": "prod", "updated-label": "true"}}},
 		{
 			ID:     "test-1/pod-2-UID/https(443)",
 			Target: "1.2.3.4:443",
 			Details: observer.Port{
 				Name: "https", Pod: observer.Pod{
-					Name:   "pod-2",
-					Labels: map[string]string{"env": "prod", "updated-label": "true"}},
-				Port:      443,
-				Transport: observer.ProtocolTCP}},
+					Name:      "pod-2",
+					UID:       "pod-2-UID",
+					Namespace: "default",
+					Labels:    map[string]string{"env": "prod", "updated-label": "true"}},
+				ContainerName:  "container-2",
+				ContainerImage: "container-image-2",
+				Port:           443,
+				Transport:      observer.ProtocolTCP}},
 	}, sink.changed)
 }
+
+func TestServiceEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(service1)
+	assert.ElementsMatch(t, []observer.Endpoint{
+		{
+			ID:     "test-1/service/service-1-UID/http(8080)",
+			Target: "10.0.0.10:8080",
+			Details: observer.K8sService{
+				Name:        "service-1",
+				UID:         "service-1-UID",
+				Namespace:   "default",
+				Labels:      map[string]string{"env": "prod"},
+				ClusterIP:   "10.0.0.10",
+				ServiceType: "ClusterIP",
+				PortName:    "http",
+				Port:        8080,
+				Transport:   observer.ProtocolTCP,
+			},
+		},
+	}, sink.added)
+
+	// Changing the port removes the old endpoint and adds a new one.
+	sink = endpointSink{}
+	updated := service1.DeepCopy()
+	updated.Spec.Ports[0].Port = 9090
+	h.OnUpdate(service1, updated)
+	assert.Len(t, sink.added, 1)
+	assert.Equal(t, observer.EndpointID("test-1/service/service-1-UID/http(9090)"), sink.added[0].ID)
+	assert.Len(t, sink.removed, 1)
+	assert.Equal(t, observer.EndpointID("test-1/service/service-1-UID/http(8080)"), sink.removed[0].ID)
+	assert.Nil(t, sink.changed)
+
+	sink = endpointSink{}
+	h.OnDelete(cache.DeletedFinalStateUnknown{Key: "default/service-1", Obj: updated})
+	assert.Len(t, sink.removed, 1)
+}
+
+func TestHeadlessServiceEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	headless := service1.DeepCopy()
+	headless.Spec.ClusterIP = v1.ClusterIPNone
+	h.OnAdd(headless)
+	require.Len(t, sink.added, 1)
+	assert.Equal(t, "service-1.default.svc:8080", sink.added[0].Target)
+}
+
+func TestNodeEndpoints(t *testing.T) {
+	sink := endpointSink{}
+	h := handler{
+		idNamespace: "test-1",
+		watcher:     &sink,
+	}
+	h.OnAdd(node1)
+	assert.Equal(t, []observer.Endpoint{
+		{
+			ID:     "test-1/node/node-1-UID",
+			Target: "192.168.1.10",
+			Details: observer.K8sNode{
+				Name:                "node-1",
+				UID:                 "node-1-UID",
+				Labels:              map[string]string{"kubernetes.io/os": "linux"},
+				InternalIP:          "192.168.1.10",
+				Hostname:            "node-1.internal",
+				KubeletEndpointPort: 10250,
+			},
+		},
+	}, sink.added)
+
+	// Label changes are sent as changed endpoints.
+	sink = endpointSink{}
+	updated := node1.DeepCopy()
+	updated.Labels["node-role"] = "worker"
+	h.OnUpdate(node1, updated)
+	assert.Nil(t, sink.added)
+	assert.Nil(t, sink.removed)
+	require.Len(t, sink.changed, 1)
+	assert.Equal(t, "worker", sink.changed[0].Details.(observer.K8sNode).Labels["node-role"])
+
+	sink = endpointSink{}
+	h.OnDelete(updated)
+	assert.Len(t, sink.removed, 1)
+}
@@ -110,3 +110,40 @@ var podWithNamedPorts = func() *v1.Pod {
 	}
 	return pod
 }()
+
+var service1 = &v1.Service{
+	ObjectMeta: metav1.ObjectMeta{
+		Namespace: "default",
+		Name:      "service-1",
+		UID:       types.UID("service-1-UID"),
+		Labels: map[string]string{
+			"env": "prod",
+		},
+	},
+	Spec: v1.ServiceSpec{
+		Type:      v1.ServiceTypeClusterIP,
+		ClusterIP: "10.0.0.10",
+		Ports: []v1.ServicePort{
+			{Name: "http", Port: 8080, Protocol: v1.ProtocolTCP},
+		},
+	},
+}
+
+var node1 = &v1.Node{
+	ObjectMeta: metav1.ObjectMeta{
+		Name: "node-1",
+		UID:  types.UID("node-1-UID"),
+		Labels: map[string]string{
+			"kubernetes.io/os": "linux",
+		},
+	},
+	Status: v1.NodeStatus{
+		Addresses: []v1.NodeAddress{
+			{Type: v1.NodeHostName, Address: "node-1.internal"},
+			{Type: v1.NodeInternalIP, Address: "192.168.1.10"},
+		},
+		DaemonEndpoints: v1.NodeDaemonEndpoints{
+			KubeletEndpoint: v1.DaemonEndpoint{Port: 10250},
+		},
+	},
+}
@@ -36,12 +36,16 @@ const (
 
 // Observable is an interface that provides notification of endpoint changes.
 type Observable interface {
-	// TODO: Stopping.
 	// ListAndWatch provides initial state sync as well as change notification.
 	// notify.OnAdd will be called one or more times if there are endpoints discovered.
 	// (It would not be called if there are no endpoints present.) The endpoint synchronization
 	// happens asynchronously to this call.
 	ListAndWatch(notify Notify)
+
+	// Unsubscribe stops sending notifications to notify, which was previously
+	// passed into ListAndWatch. No notification is being sent to notify once
+	// it returns, so it must not be called from a Notify callback.
+	Unsubscribe(notify Notify)
 }
 
 // Notify is the callback for Observer events.
@@ -0,0 +1,331 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunk
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TenantLabel is the resource attribute recording the tenant of the access
+// token of authenticated requests.
+const TenantLabel = "com.splunk.tenant"
+
+const (
+	defaultIntrospectionTimeout  = 5 * time.Second
+	defaultIntrospectionCacheTTL = time.Minute
+
+	// The tokens file is checked for changes at most once per interval.
+	tokensFileCheckInterval = time.Second
+
+	// The introspection cache is emptied when it reaches this size, so that
+	// requests with random tokens can't grow it without bound.
+	maxIntrospectionCacheSize = 10000
+)
+
+var (
+	// ErrInvalidAccessToken is returned for requests without access token or
+	// with an access token that isn't valid.
+	ErrInvalidAccessToken = errors.New("invalid access token")
+
+	errNoTokenSource = errors.New("auth requires tokens, a tokens_file or an introspection endpoint")
+)
+
+// AuthConfig configures the validation of the access tokens of requests, in
+// the X-Sf-Token header. Tokens are looked up in order in the static tokens,
+// the tokens file and finally the introspection endpoint.
+type AuthConfig struct {
+	// Tokens are the valid access tokens, along with their tenant.
+	Tokens []TokenConfig `mapstructure:"tokens"`
+
+	// TokensFile is the path of a file of "token: tenant" lines, reloaded when
+	// it changes. Empty lines and lines starting with # are ignored.
+	TokensFile string `mapstructure:"tokens_file"`
+
+	// Introspection configures the validation of the tokens that aren't
+	// found locally by an HTTP endpoint.
+	Introspection *IntrospectionConfig `mapstructure:"introspection"`
+}
+
+// TokenConfig is a valid access token and its tenant.
+type TokenConfig struct {
+	Token  string `mapstructure:"token"`
+	Tenant string `mapstructure:"tenant"`
+}
+
+// IntrospectionConfig configures the HTTP endpoint validating access tokens.
+// The token is POSTed in the X-Sf-Token header. The endpoint answers 200 with
+// a {"tenant": "..."} JSON body for valid tokens, and 401 or 403 for invalid
+// ones. Any other answer fails the request without rejecting the token.
+type IntrospectionConfig struct {
+	// Endpoint is the URL of the introspection endpoint.
+	Endpoint string `mapstructure:"endpoint"`
+
+	// Timeout of the introspection requests. The default is 5s.
+	Timeout time.Duration `mapstructure:"timeout"`
+
+	// CacheTTL is how long the answers of the endpoint are cached for. The
+	// default is 1m.
+	CacheTTL time.Duration `mapstructure:"cache_ttl"`
+}
+
+// Authenticator validates access tokens and returns their tenant.
+type Authenticator interface {
+	// Authenticate returns the tenant of the access token, or
+	// ErrInvalidAccessToken if it isn't valid.
+	Authenticate(ctx context.Context, token string) (string, error)
+}
+
+// AuthStatusCode returns the HTTP status code of the response to a request
+// that failed authentication with err.
+func AuthStatusCode(err error) int {
+	if errors.Is(err, ErrInvalidAccessToken) {
+		return http.StatusUnauthorized
+	}
+	return http.StatusServiceUnavailable
+}
+
+type tokenAuthenticator struct {
+	tokens map[string]string
+
+	tokensFile *tokensFile
+
+	introspection *introspector
+}
+
+var _ Authenticator = (*tokenAuthenticator)(nil)
+
+// NewAuthenticator creates the Authenticator of the configuration. The tokens
+// file, if any, must be readable.
+func NewAuthenticator(cfg AuthConfig) (Authenticator, error) {
+	if len(cfg.Tokens) == 0 && cfg.TokensFile == "" && (cfg.Introspection == nil || cfg.Introspection.Endpoint == "") {
+		return nil, errNoTokenSource
+	}
+
+	a := &tokenAuthenticator{
+		tokens: make(map[string]string, len(cfg.Tokens)),
+	}
+	for _, token := range cfg.Tokens {
+		if token.Token == "" {
+			return nil, errors.New("auth tokens must not be empty")
+		}
+		a.tokens[token.Token] = token.Tenant
+	}
+
+	if cfg.TokensFile != "" {
+		a.tokensFile = &tokensFile{path: cfg.TokensFile}
+		if err := a.tokensFile.load(); err != nil {
+			return nil, err
+		}
+	}
+
+	if cfg.Introspection != nil && cfg.Introspection.Endpoint != "" {
+		a.introspection = newIntrospector(*cfg.Introspection)
+	}
+	return a, nil
+}
+
+func (a *tokenAuthenticator) Authenticate(ctx context.Context, token string) (string, error) {
+	if token == "" {
+		return "", ErrInvalidAccessToken
+	}
+	if tenant, ok := a.tokens[token]; ok {
+		return tenant, nil
+	}
+	if a.tokensFile != nil {
+		if tenant, ok := a.tokensFile.lookup(token); ok {
+			return tenant, nil
+		}
+	}
+	if a.introspection != nil {
+		return a.introspection.introspect(ctx, token)
+	}
+	return "", ErrInvalidAccessToken
+}
+
+// tokensFile holds the tokens of a file, reloaded when its modification time
+// or size changes.
+type tokensFile struct {
+	path string
+
+	mu        sync.Mutex
+	tokens    map[string]string
+	modTime   time.Time
+	size      int64
+	lastCheck time.Time
+}
+
+func (f *tokensFile) lookup(token string) (string, bool) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	if now := time.Now(); now.Sub(f.lastCheck) >= tokensFileCheckInterval {
+		f.lastCheck = now
+		// The previous tokens are kept if the file can't be reloaded, e.g.
+		// while it is being replaced.
+		_ = f.reloadIfChanged()
+	}
+	tenant, ok := f.tokens[token]
+	return tenant, ok
+}
+
+func (f *tokensFile) load() error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.lastCheck = time.Now()
+	return f.reloadIfChanged()
+}
+
+func (f *tokensFile) reloadIfChanged() error {
+	info, err := os.Stat(f.path)
+	if err != nil {
+		return err
+	}
+	if f.tokens != nil && info.ModTime().Equal(f.modTime) && info.Size() == f.size {
+		return nil
+	}
+
+	content, err := ioutil.ReadFile(f.path)
+	if err != nil {
+		return err
+	}
+	tokens, err := parseTokensFile(content)
+	if err != nil {
+		return fmt.Errorf("%s: %w", f.path, err)
+	}
+	f.tokens = tokens
+	f.modTime = info.ModTime()
+	f.size = info.Size()
+	return nil
+}
+
+// parseTokensFile parses "token: tenant" lines. The tenant is optional.
+func parseTokensFile(content []byte) (map[string]string, error) {
+	tokens := make(map[string]string)
+	scanner := bufio.NewScanner(bytes.NewReader(content))
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		token, tenant := line, ""
+		if i := strings.LastIndexByte(line, ':'); i != -1 {
+			token, tenant = strings.TrimSpace(line[:i]), strings.TrimSpace(line[i+1:])
+		}
+		if token == "" {
+			return nil, fmt.Errorf("invalid line %d: empty token", lineNum)
+		}
+		tokens[token] = tenant
+	}
+	return tokens, scanner.Err()
+}
+
+// introspector validates tokens with an HTTP endpoint, caching its answers.
+type introspector struct {
+	endpoint string
+	cacheTTL time.Duration
+	client   *http.Client
+
+	mu    sync.Mutex
+	cache map[string]introspection
+}
+
+type introspection struct {
+	tenant  string
+	valid   bool
+	expires time.Time
+}
+
+func newIntrospector(cfg IntrospectionConfig) *introspector {
+	timeout := cfg.Timeout
+	if timeout <= 0 {
+		timeout = defaultIntrospectionTimeout
+	}
+	cacheTTL := cfg.CacheTTL
+	if cacheTTL <= 0 {
+		cacheTTL = defaultIntrospectionCacheTTL
+	}
+	return &introspector{
+		endpoint: cfg.Endpoint,
+		cacheTTL: cacheTTL,
+		client:   &http.Client{Timeout: timeout},
+		cache:    make(map[string]introspection),
+	}
+}
+
+func (i *introspector) introspect(ctx context.Context, token string) (string, error) {
+	now := time.Now()
+	i.mu.Lock()
+	cached, ok := i.cache[token]
+	i.mu.Unlock()
+	if !ok || now.After(cached.expires) {
+		var err error
+		if cached, err = i.request(ctx, token); err != nil {
+			return "", err
+		}
+		cached.expires = now.Add(i.cacheTTL)
+
+		i.mu.Lock()
+		if len(i.cache) >= maxIntrospectionCacheSize {
+			i.cache = make(map[string]introspection)
+		}
+		i.cache[token] = cached
+		i.mu.Unlock()
+	}
+
+	if !cached.valid {
+		return "", ErrInvalidAccessToken
+	}
+	return cached.tenant, nil
+}
+
+func (i *introspector) request(ctx context.Context, token string) (introspection, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, i.endpoint, nil)
+	if err != nil {
+		return introspection{}, err
+	}
+	req.Header.Set(SFxAccessTokenHeader, token)
+
+	resp, err := i.client.Do(req)
+	if err != nil {
+		return introspection{}, fmt.Errorf("token introspection failed: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		var body struct {
+			Tenant string `json:"tenant"`
+		}
+		if err := json.NewDecoder(resp.Body).Decode(&body); err != nil {
+			return introspection{}, fmt.Errorf("invalid token introspection response: %w", err)
+		}
+		return introspection{tenant: body.Tenant, valid: true}, nil
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return introspection{}, nil
+	}
+	return introspection{}, fmt.Errorf("token introspection failed with status %d", resp.StatusCode)
+}
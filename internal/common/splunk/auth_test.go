@@ -0,0 +1,173 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package splunk
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewAuthenticatorErrors(t *testing.T) {
+	_, err := NewAuthenticator(AuthConfig{})
+	assert.Equal(t, errNoTokenSource, err)
+
+	_, err = NewAuthenticator(AuthConfig{Tokens: []TokenConfig{{Tenant: "acme"}}})
+	assert.Error(t, err)
+
+	_, err = NewAuthenticator(AuthConfig{TokensFile: filepath.Join("testdata", "missing")})
+	assert.Error(t, err)
+}
+
+func TestAuthenticateStaticTokens(t *testing.T) {
+	a, err := NewAuthenticator(AuthConfig{
+		Tokens: []TokenConfig{
+			{Token: "token0", Tenant: "acme"},
+			{Token: "token1"},
+		},
+	})
+	require.NoError(t, err)
+
+	tenant, err := a.Authenticate(context.Background(), "token0")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	tenant, err = a.Authenticate(context.Background(), "token1")
+	require.NoError(t, err)
+	assert.Equal(t, "", tenant)
+
+	_, err = a.Authenticate(context.Background(), "other")
+	assert.Equal(t, ErrInvalidAccessToken, err)
+
+	_, err = a.Authenticate(context.Background(), "")
+	assert.Equal(t, ErrInvalidAccessToken, err)
+}
+
+func TestAuthenticateTokensFile(t *testing.T) {
+	dir, err := ioutil.TempDir("", "tokens")
+	require.NoError(t, err)
+	defer os.RemoveAll(dir)
+	path := filepath.Join(dir, "tokens")
+	require.NoError(t, ioutil.WriteFile(path, []byte("# tenants\ntoken0: acme\n\ntoken1\n"), 0600))
+
+	a, err := NewAuthenticator(AuthConfig{TokensFile: path})
+	require.NoError(t, err)
+
+	tenant, err := a.Authenticate(context.Background(), "token0")
+	require.NoError(t, err)
+	assert.Equal(t, "acme", tenant)
+
+	tenant, err = a.Authenticate(context.Background(), "token1")
+	require.NoError(t, err)
+	assert.Equal(t, "", tenant)
+
+	require.NoError(t, ioutil.WriteFile(path, []byte("token2: globex\n"), 0600))
+	// Makes the change visible regardless of the resolution of modification
+	// times and of the check interval.
+	require.NoError(t, os.Chtimes(path, time.Now(), time.Now().Add(time.Minute)))
+	a.(*tokenAuthenticator).tokensFile.lastCheck = time.Time{}
+
+	tenant, err = a.Authenticate(context.Background(), "token2")
+	require.NoError(t, err)
+	assert.Equal(t, "globex", tenant)
+
+	_, err = a.Authenticate(context.Background(), "token0")
+	assert.Equal(t, ErrInvalidAccessToken, err)
+
+	// The previous tokens are kept when the file is removed.
+	require.NoError(t, os.Remove(path))
+	a.(*tokenAuthenticator).tokensFile.lastCheck = time.Time{}
+	tenant, err = a.Authenticate(context.Background(), "token2")
+	require.NoError(t, err)
+	assert.Equal(t, "globex", tenant)
+}
+
+func TestParseTokensFile(t *testing.T) {
+	tokens, err := parseTokensFile([]byte("  token0 :  acme  \n#comment\ntoken1"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"token0": "acme", "token1": ""}, tokens)
+
+	_, err = parseTokensFile([]byte("token0: acme\n: globex\n"))
+	assert.EqualError(t, err, "invalid line 2: empty token")
+}
+
+func TestAuthenticateIntrospection(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		assert.Equal(t, http.MethodPost, r.Method)
+		switch r.Header.Get(SFxAccessTokenHeader) {
+		case "valid":
+			w.Write([]byte(`{"tenant": "acme"}`))
+		case "invalid":
+			w.WriteHeader(http.StatusUnauthorized)
+		case "garbage":
+			w.Write([]byte(`not json`))
+		default:
+			w.WriteHeader(http.StatusInternalServerError)
+		}
+	}))
+	defer server.Close()
+
+	a, err := NewAuthenticator(AuthConfig{
+		Tokens:        []TokenConfig{{Token: "static", Tenant: "local"}},
+		Introspection: &IntrospectionConfig{Endpoint: server.URL},
+	})
+	require.NoError(t, err)
+
+	tenant, err := a.Authenticate(context.Background(), "static")
+	require.NoError(t, err)
+	assert.Equal(t, "local", tenant)
+	assert.EqualValues(t, 0, atomic.LoadInt32(&requests))
+
+	for i := 0; i < 2; i++ {
+		tenant, err = a.Authenticate(context.Background(), "valid")
+		require.NoError(t, err)
+		assert.Equal(t, "acme", tenant)
+
+		_, err = a.Authenticate(context.Background(), "invalid")
+		assert.Equal(t, ErrInvalidAccessToken, err)
+	}
+	// The answers are cached.
+	assert.EqualValues(t, 2, atomic.LoadInt32(&requests))
+
+	_, err = a.Authenticate(context.Background(), "garbage")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, AuthStatusCode(err))
+
+	_, err = a.Authenticate(context.Background(), "unavailable")
+	assert.Error(t, err)
+	assert.Equal(t, http.StatusServiceUnavailable, AuthStatusCode(err))
+
+	// Failures aren't cached.
+	_, err = a.Authenticate(context.Background(), "unavailable")
+	assert.Error(t, err)
+	assert.EqualValues(t, 5, atomic.LoadInt32(&requests))
+}
+
+func TestAuthStatusCode(t *testing.T) {
+	assert.Equal(t, http.StatusUnauthorized, AuthStatusCode(ErrInvalidAccessToken))
+	assert.Equal(t, http.StatusServiceUnavailable, AuthStatusCode(errors.New("unavailable")))
+}
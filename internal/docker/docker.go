@@ -12,48 +12,106 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package dockerstatsreceiver
+// Package docker provides a Docker API client that keeps track of the running
+// containers of interest. It is shared by components monitoring Docker containers.
+package docker
 
 import (
 	"context"
+	"crypto/tls"
 	"encoding/json"
 	"fmt"
 	"io"
+	"net/http"
 	"strings"
 	"sync"
 	"time"
 
 	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
 	dfilters "github.com/docker/docker/api/types/filters"
 	docker "github.com/docker/docker/client"
-	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.uber.org/zap"
 )
 
 const (
 	dockerAPIVersion = "v1.22"
-	userAgent        = "OpenTelemetry-Collector Docker Stats Receiver/v0.0.1"
+	defaultUserAgent = "OpenTelemetry-Collector Docker Client/v0.0.1"
+
+	// NegotiateAPIVersion is the APIVersion negotiating the highest version
+	// supported by both the client and the daemon.
+	NegotiateAPIVersion = "auto"
 )
 
-// dockerClient provides the core metric gathering functionality from the Docker Daemon.
-// It retrieves container information in two forms to produce metric data: dtypes.ContainerJSON
-// from client.ContainerInspect() for container information (id, name, hostname, labels, and env)
-// and dtypes.StatsJSON from client.ContainerStats() for metric values.
-type dockerClient struct {
+// Config contains the settings used to connect to the Docker daemon.
+type Config struct {
+	// The URL of the docker server.
+	Endpoint string
+	// The maximum amount of time to wait for docker API responses.
+	Timeout time.Duration
+	// A list of filters whose matching images are to be excluded.  Supports literals, globs, and regex.
+	ExcludedImages []string
+	// UserAgent sent to the docker server.  Defaults to a generic collector user agent.
+	UserAgent string
+	// The Docker API version to use, or NegotiateAPIVersion.  Defaults to 1.22.
+	APIVersion string
+	// The TLS configuration used to connect to the daemon, if any.
+	TLSConfig *tls.Config
+}
+
+// Container is a client.ContainerInspect() response container
+// and translated environment string map for potential labels.
+type Container struct {
+	*dtypes.ContainerJSON
+	EnvMap map[string]string
+}
+
+// EventHandler is called with the events of the containers of interest received
+// by ContainerEventLoop, along with the container as inspected after the event.
+type EventHandler func(event devents.Message, container *Container)
+
+// Client provides the core container tracking functionality from the Docker Daemon.
+// It retrieves container information in two forms: dtypes.ContainerJSON from
+// client.ContainerInspect() for container information (id, name, hostname, labels, and env)
+// and dtypes.StatsJSON from client.ContainerStats() for stat values.
+type Client struct {
 	client               *docker.Client
 	config               *Config
-	containers           map[string]DockerContainer
+	containers           map[string]Container
 	containersLock       sync.Mutex
 	excludedImageMatcher *StringMatcher
 	logger               *zap.Logger
+	eventHandler         EventHandler
 }
 
-func newDockerClient(config *Config, logger *zap.Logger) (*dockerClient, error) {
-	client, err := docker.NewClientWithOpts(
+// NewDockerClient creates a Client for the daemon configured in config.
+func NewDockerClient(config *Config, logger *zap.Logger) (*Client, error) {
+	userAgent := config.UserAgent
+	if userAgent == "" {
+		userAgent = defaultUserAgent
+	}
+	var opts []docker.Opt
+	if config.TLSConfig != nil {
+		// The transport must be set before the host, which configures it for the
+		// endpoint protocol.
+		opts = append(opts, docker.WithHTTPClient(&http.Client{
+			Transport: &http.Transport{TLSClientConfig: config.TLSConfig},
+		}))
+	}
+	opts = append(opts,
 		docker.WithHost(config.Endpoint),
-		docker.WithVersion(dockerAPIVersion),
 		docker.WithHTTPHeaders(map[string]string{"User-Agent": userAgent}),
 	)
+	switch config.APIVersion {
+	case "":
+		opts = append(opts, docker.WithVersion(dockerAPIVersion))
+	case NegotiateAPIVersion:
+		opts = append(opts, docker.WithAPIVersionNegotiation())
+	default:
+		opts = append(opts, docker.WithVersion(config.APIVersion))
+	}
+
+	client, err := docker.NewClientWithOpts(opts...)
 	if err != nil {
 		return nil, fmt.Errorf("could not create docker client: %w", err)
 	}
@@ -63,11 +121,11 @@ func newDockerClient(config *Config, logger *zap.Logger) (*dockerClient, error)
 		return nil, fmt.Errorf("could not determine docker client excluded images: %w", err)
 	}
 
-	dc := &dockerClient{
+	dc := &Client{
 		client:               client,
 		config:               config,
 		logger:               logger,
-		containers:           make(map[string]DockerContainer),
+		containers:           make(map[string]Container),
 		containersLock:       sync.Mutex{},
 		excludedImageMatcher: excludedImageMatcher,
 	}
@@ -75,11 +133,17 @@ func newDockerClient(config *Config, logger *zap.Logger) (*dockerClient, error)
 	return dc, nil
 }
 
-// Provides a slice of DockerContainers to use for individual FetchContainerStats calls.
-func (dc *dockerClient) Containers() []DockerContainer {
+// SetEventHandler sets the handler of the container events received by
+// ContainerEventLoop.  It must be called before the loop is started.
+func (dc *Client) SetEventHandler(handler EventHandler) {
+	dc.eventHandler = handler
+}
+
+// Containers provides a slice of Containers to use for individual FetchContainerStatsAsJSON calls.
+func (dc *Client) Containers() []Container {
 	dc.containersLock.Lock()
 	defer dc.containersLock.Unlock()
-	containers := make([]DockerContainer, 0, len(dc.containers))
+	containers := make([]Container, 0, len(dc.containers))
 	for _, container := range dc.containers {
 		containers = append(containers, container)
 	}
@@ -89,7 +153,7 @@ func (dc *dockerClient) Containers() []DockerContainer {
 // LoadContainerList will load the initial running container maps for
 // inspection and establishing which containers warrant stat gathering calls
 // by the receiver.
-func (dc *dockerClient) LoadContainerList(ctx context.Context) error {
+func (dc *Client) LoadContainerList(ctx context.Context) error {
 	// Build initial container maps before starting loop
 	filters := dfilters.NewArgs()
 	filters.Add("status", "running")
@@ -126,12 +190,12 @@ func (dc *dockerClient) LoadContainerList(ctx context.Context) error {
 	return nil
 }
 
-// FetchContainerStatsAndConvertToMetrics will query the desired container stats and send
-// converted metrics to the results channel, since this is intended to be run in a goroutine.
-func (dc *dockerClient) FetchContainerStatsAndConvertToMetrics(
+// FetchContainerStatsAsJSON will query the desired container stats and return them
+// as StatsJSON.  Containers the daemon reports as no longer existing are removed.
+func (dc *Client) FetchContainerStatsAsJSON(
 	ctx context.Context,
-	container DockerContainer,
-) (*consumerdata.MetricsData, error) {
+	container Container,
+) (*dtypes.StatsJSON, error) {
 	dc.logger.Debug("Fetching container stats.", zap.String("id", container.ID))
 	statsCtx, cancel := context.WithTimeout(ctx, dc.config.Timeout)
 	containerStats, err := dc.client.ContainerStats(statsCtx, container.ID, false)
@@ -154,26 +218,12 @@ func (dc *dockerClient) FetchContainerStatsAndConvertToMetrics(
 		return nil, err
 	}
 
-	statsJSON, err := dc.toStatsJSON(containerStats, &container)
-	if err != nil {
-		return nil, err
-	}
-
-	md, err := ContainerStatsToMetrics(statsJSON, &container, dc.config)
-	if err != nil {
-		dc.logger.Error(
-			"Could not convert docker containerStats for container id",
-			zap.String("id", container.ID),
-			zap.Error(err),
-		)
-		return nil, err
-	}
-	return md, nil
+	return dc.toStatsJSON(containerStats, &container)
 }
 
-func (dc *dockerClient) toStatsJSON(
+func (dc *Client) toStatsJSON(
 	containerStats dtypes.ContainerStats,
-	container *DockerContainer,
+	container *Container,
 ) (*dtypes.StatsJSON, error) {
 	var statsJSON dtypes.StatsJSON
 	err := json.NewDecoder(containerStats.Body).Decode(&statsJSON)
@@ -194,11 +244,15 @@ func (dc *dockerClient) toStatsJSON(
 	return &statsJSON, nil
 }
 
-func (dc *dockerClient) ContainerEventLoop(ctx context.Context) {
+// ContainerEventLoop watches container events to keep the tracked containers up to date
+// until ctx is done.
+func (dc *Client) ContainerEventLoop(ctx context.Context) {
 	filters := dfilters.NewArgs([]dfilters.KeyValuePair{
 		{Key: "type", Value: "container"},
 		{Key: "event", Value: "destroy"},
 		{Key: "event", Value: "die"},
+		{Key: "event", Value: "health_status"},
+		{Key: "event", Value: "oom"},
 		{Key: "event", Value: "pause"},
 		{Key: "event", Value: "stop"},
 		{Key: "event", Value: "start"},
@@ -233,6 +287,12 @@ EVENT_LOOP:
 
 					if container, ok := dc.inspectedContainerIsOfInterest(ctx, event.ID); ok {
 						dc.persistContainer(container)
+						if dc.eventHandler != nil {
+							dc.eventHandler(event, &Container{
+								ContainerJSON: container,
+								EnvMap:        ContainerEnvToMap(container.Config.Env),
+							})
+						}
 					}
 				}
 
@@ -262,7 +322,7 @@ EVENT_LOOP:
 
 // Queries inspect api and returns *ContainerJSON and true when container should be queried for stats,
 // nil and false otherwise.
-func (dc *dockerClient) inspectedContainerIsOfInterest(ctx context.Context, cid string) (*dtypes.ContainerJSON, bool) {
+func (dc *Client) inspectedContainerIsOfInterest(ctx context.Context, cid string) (*dtypes.ContainerJSON, bool) {
 	inspectCtx, cancel := context.WithTimeout(ctx, dc.config.Timeout)
 	container, err := dc.client.ContainerInspect(inspectCtx, cid)
 	defer cancel()
@@ -278,7 +338,22 @@ func (dc *dockerClient) inspectedContainerIsOfInterest(ctx context.Context, cid
 	return nil, false
 }
 
-func (dc *dockerClient) persistContainer(containerJSON *dtypes.ContainerJSON) {
+// InspectAndPersistContainer queries inspect api and returns *Container and true when container
+// should be queried for stats, nil and false otherwise. Persists the container in the cache if
+// successful.
+func (dc *Client) InspectAndPersistContainer(ctx context.Context, cid string) (*Container, bool) {
+	if containerJSON, ok := dc.inspectedContainerIsOfInterest(ctx, cid); ok {
+		dc.persistContainer(containerJSON)
+		dc.containersLock.Lock()
+		defer dc.containersLock.Unlock()
+		if container, ok := dc.containers[cid]; ok {
+			return &container, true
+		}
+	}
+	return nil, false
+}
+
+func (dc *Client) persistContainer(containerJSON *dtypes.ContainerJSON) {
 	if containerJSON == nil {
 		return
 	}
@@ -293,24 +368,26 @@ func (dc *dockerClient) persistContainer(containerJSON *dtypes.ContainerJSON) {
 	dc.logger.Debug("Monitoring Docker container", zap.String("id", cid))
 	dc.containersLock.Lock()
 	defer dc.containersLock.Unlock()
-	dc.containers[cid] = DockerContainer{
+	dc.containers[cid] = Container{
 		ContainerJSON: containerJSON,
-		EnvMap:        containerEnvToMap(containerJSON.Config.Env),
+		EnvMap:        ContainerEnvToMap(containerJSON.Config.Env),
 	}
 }
 
-func (dc *dockerClient) removeContainer(cid string) {
+func (dc *Client) removeContainer(cid string) {
 	dc.containersLock.Lock()
 	defer dc.containersLock.Unlock()
 	delete(dc.containers, cid)
 	dc.logger.Debug("Removed container from stores.", zap.String("id", cid))
 }
 
-func (dc *dockerClient) shouldBeExcluded(image string) bool {
+func (dc *Client) shouldBeExcluded(image string) bool {
 	return dc.excludedImageMatcher != nil && dc.excludedImageMatcher.Matches(image)
 }
 
-func containerEnvToMap(env []string) map[string]string {
+// ContainerEnvToMap converts a container's KEY=VALUE environment variables into a map.
+// Variables with empty values are omitted.
+func ContainerEnvToMap(env []string) map[string]string {
 	out := make(map[string]string, len(env))
 	for _, v := range env {
 		parts := strings.Split(v, "=")
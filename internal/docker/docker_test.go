@@ -12,10 +12,11 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-package dockerstatsreceiver
+package docker
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
 	"io/ioutil"
@@ -29,6 +30,7 @@ import (
 	"time"
 
 	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.uber.org/zap"
@@ -40,16 +42,18 @@ func TestInvalidEndpoint(t *testing.T) {
 	config := &Config{
 		Endpoint: "$notavalidendpoint*",
 	}
-	cli, err := newDockerClient(config, zap.NewNop())
+	cli, err := NewDockerClient(config, zap.NewNop())
 	assert.Nil(t, cli)
 	require.Error(t, err)
 	assert.Equal(t, "could not create docker client: unable to parse docker host `$notavalidendpoint*`", err.Error())
 }
 
 func TestInvalidExclude(t *testing.T) {
-	config := NewFactory().CreateDefaultConfig().(*Config)
-	config.ExcludedImages = []string{"["}
-	cli, err := newDockerClient(config, zap.NewNop())
+	config := &Config{
+		Endpoint:       "unix:///var/run/docker.sock",
+		ExcludedImages: []string{"["},
+	}
+	cli, err := NewDockerClient(config, zap.NewNop())
 	assert.Nil(t, cli)
 	require.Error(t, err)
 	assert.Equal(t, "could not determine docker client excluded images: invalid glob item: unexpected end of input", err.Error())
@@ -85,7 +89,7 @@ func TestWatchingTimeouts(t *testing.T) {
 		Timeout:  50 * time.Millisecond,
 	}
 
-	cli, err := newDockerClient(config, zap.NewNop())
+	cli, err := NewDockerClient(config, zap.NewNop())
 	assert.NotNil(t, cli)
 	assert.Nil(t, err)
 
@@ -98,7 +102,7 @@ func TestWatchingTimeouts(t *testing.T) {
 	assert.Contains(t, err.Error(), expectedError)
 
 	observed, logs := observer.New(zapcore.WarnLevel)
-	cli, err = newDockerClient(config, zap.New(observed))
+	cli, err = NewDockerClient(config, zap.New(observed))
 	assert.NotNil(t, cli)
 	assert.Nil(t, err)
 
@@ -126,7 +130,7 @@ func TestFetchingTimeouts(t *testing.T) {
 		Timeout:  50 * time.Millisecond,
 	}
 
-	cli, err := newDockerClient(config, zap.NewNop())
+	cli, err := NewDockerClient(config, zap.NewNop())
 	assert.NotNil(t, cli)
 	assert.Nil(t, err)
 
@@ -135,13 +139,13 @@ func TestFetchingTimeouts(t *testing.T) {
 	shouldHaveTaken := time.Now().Add(50 * time.Millisecond).UnixNano()
 
 	observed, logs := observer.New(zapcore.WarnLevel)
-	cli, err = newDockerClient(config, zap.New(observed))
+	cli, err = NewDockerClient(config, zap.New(observed))
 	assert.NotNil(t, cli)
 	assert.Nil(t, err)
 
-	md, err := cli.FetchContainerStatsAndConvertToMetrics(
+	statsJSON, err := cli.FetchContainerStatsAsJSON(
 		context.Background(),
-		DockerContainer{
+		Container{
 			ContainerJSON: &dtypes.ContainerJSON{
 				ContainerJSONBase: &dtypes.ContainerJSONBase{
 					ID: "notARealContainerId",
@@ -150,7 +154,7 @@ func TestFetchingTimeouts(t *testing.T) {
 		},
 	)
 
-	assert.Nil(t, md)
+	assert.Nil(t, statsJSON)
 	require.Error(t, err)
 
 	assert.Contains(t, err.Error(), expectedError)
@@ -177,11 +181,11 @@ func TestToStatsJSONErrorHandling(t *testing.T) {
 		Timeout:  50 * time.Millisecond,
 	}
 
-	cli, err := newDockerClient(config, zap.NewNop())
+	cli, err := NewDockerClient(config, zap.NewNop())
 	assert.NotNil(t, cli)
 	assert.Nil(t, err)
 
-	dc := &DockerContainer{
+	dc := &Container{
 		ContainerJSON: &dtypes.ContainerJSON{
 			ContainerJSONBase: &dtypes.ContainerJSONBase{
 				ID: "notARealContainerId",
@@ -223,7 +227,7 @@ func TestEventLoopHandlesError(t *testing.T) {
 		Timeout:  50 * time.Millisecond,
 	}
 
-	cli, err := newDockerClient(config, zap.New(observed))
+	cli, err := NewDockerClient(config, zap.New(observed))
 	assert.NotNil(t, cli)
 	assert.Nil(t, err)
 
@@ -249,3 +253,106 @@ func TestEventLoopHandlesError(t *testing.T) {
 		return
 	}
 }
+
+func TestEventLoopCallsEventHandler(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch {
+		case strings.HasSuffix(r.URL.Path, "/events"):
+			w.Write([]byte(`{"Type":"container","Action":"die","id":"abc","Actor":{"ID":"abc","Attributes":{"exitCode":"1"}},"timeNano":1}`))
+			w.(http.Flusher).Flush()
+			<-r.Context().Done()
+		case strings.HasSuffix(r.URL.Path, "/containers/abc/json"):
+			w.Header().Set("Content-Type", "application/json")
+			w.Write([]byte(`{"Id":"abc","State":{"Running":false},"Config":{"Image":"nginx","Env":["A=b"]}}`))
+		}
+	}))
+	defer srv.Close()
+
+	cli, err := NewDockerClient(&Config{
+		Endpoint: srv.URL,
+		Timeout:  time.Second,
+	}, zap.NewNop())
+	require.NoError(t, err)
+
+	events := make(chan *Container, 1)
+	cli.SetEventHandler(func(event devents.Message, container *Container) {
+		assert.Equal(t, "die", event.Action)
+		assert.Equal(t, "1", event.Actor.Attributes["exitCode"])
+		events <- container
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	go cli.ContainerEventLoop(ctx)
+
+	select {
+	case container := <-events:
+		assert.Equal(t, "abc", container.ID)
+		assert.Equal(t, map[string]string{"A": "b"}, container.EnvMap)
+		// Containers that are no longer running aren't monitored.
+		assert.Empty(t, cli.Containers())
+	case <-time.After(5 * time.Second):
+		t.Fatal("event handler wasn't called")
+	}
+}
+
+func TestAPIVersion(t *testing.T) {
+	tests := []struct {
+		name       string
+		apiVersion string
+		path       string
+	}{
+		{name: "default", apiVersion: "", path: "/v1.22/containers/json"},
+		{name: "explicit", apiVersion: "1.40", path: "/v1.40/containers/json"},
+		{name: "negotiated", apiVersion: NegotiateAPIVersion, path: "/v1.30/containers/json"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var paths []string
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if r.URL.Path == "/_ping" {
+					w.Header().Set("API-Version", "1.30")
+					return
+				}
+				paths = append(paths, r.URL.Path)
+				w.Write([]byte("[]"))
+			}))
+			defer srv.Close()
+
+			cli, err := NewDockerClient(&Config{
+				Endpoint:   srv.URL,
+				Timeout:    time.Second,
+				APIVersion: tt.apiVersion,
+			}, zap.NewNop())
+			require.NoError(t, err)
+			require.NoError(t, cli.LoadContainerList(context.Background()))
+			assert.Equal(t, []string{tt.path}, paths)
+		})
+	}
+}
+
+func TestTLSEndpoint(t *testing.T) {
+	var requests int
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte("[]"))
+	}))
+	defer srv.Close()
+
+	cli, err := NewDockerClient(&Config{
+		Endpoint:  "tcp://" + srv.Listener.Addr().String(),
+		Timeout:   time.Second,
+		TLSConfig: &tls.Config{InsecureSkipVerify: true},
+	}, zap.NewNop())
+	require.NoError(t, err)
+	require.NoError(t, cli.LoadContainerList(context.Background()))
+	assert.Equal(t, 1, requests)
+
+	// The daemon can't be reached without TLS.
+	cli, err = NewDockerClient(&Config{
+		Endpoint: "tcp://" + srv.Listener.Addr().String(),
+		Timeout:  time.Second,
+	}, zap.NewNop())
+	require.NoError(t, err)
+	require.Error(t, cli.LoadContainerList(context.Background()))
+}
@@ -0,0 +1,10 @@
+module github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker
+
+go 1.14
+
+require (
+	github.com/docker/docker v17.12.0-ce-rc1.0.20200706150819-a40b877fbb9e+incompatible
+	github.com/gobwas/glob v0.2.3
+	github.com/stretchr/testify v1.6.1
+	go.uber.org/zap v1.16.0
+)
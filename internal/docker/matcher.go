@@ -12,12 +12,7 @@
 // See the License for the specific language governing permissions and
 // limitations under the License.
 
-// Package items contains common filtering logic that can be used to items
-// datapoints or various resources within other agent components, such as
-// monitors.  Filter instances have a Matches function which takes an instance
-// of the type that they items and return whether that instance matches the
-// items.
-package dockerstatsreceiver
+package docker
 
 import (
 	"fmt"
@@ -0,0 +1,225 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// AggregationConfig holds the settings, common to the plaintext and regex
+// parsers, that infer the type of the metrics from the way Graphite aggregates
+// them.
+type AggregationConfig struct {
+	// StorageAggregationFile is the path of a file in the format of Carbon's
+	// storage-aggregation.conf, see
+	// https://graphite.readthedocs.io/en/latest/config-carbon.html#storage-aggregation-conf.
+	// Metrics matching a rule with the "sum" aggregation method are generated
+	// as cumulative metrics and the ones matching any other rule as gauges.
+	// Metric types set by the rules of the regex parser take precedence.
+	StorageAggregationFile string `mapstructure:"storage_aggregation_file"`
+
+	// SummaryFlushInterval, if set, is the interval during which the
+	// ".p50", ".p90", ".p99", ".count" and ".sum" siblings of a metric, with
+	// the same labels, are grouped into a single summary metric.
+	SummaryFlushInterval time.Duration `mapstructure:"summary_flush_interval"`
+}
+
+// AggregationParserConfig is implemented by the ParserConfig that support the
+// settings of AggregationConfig.
+type AggregationParserConfig interface {
+	ParserConfig
+
+	// Aggregation returns the aggregation settings of the parser.
+	Aggregation() *AggregationConfig
+}
+
+// Aggregation returns the aggregation settings of the parser.
+func (ac *AggregationConfig) Aggregation() *AggregationConfig {
+	return ac
+}
+
+// buildPathParser wraps the given PathParser to infer the metric types from
+// the storage aggregation file, if one was configured.
+func (ac *AggregationConfig) buildPathParser(pathParser PathParser) (PathParser, error) {
+	if ac.SummaryFlushInterval < 0 {
+		return nil, fmt.Errorf("invalid summary_flush_interval %v", ac.SummaryFlushInterval)
+	}
+	if ac.StorageAggregationFile == "" {
+		return pathParser, nil
+	}
+
+	rules, err := loadStorageAggregationFile(ac.StorageAggregationFile)
+	if err != nil {
+		return nil, err
+	}
+	return &aggregationPathParser{
+		pathParser: pathParser,
+		rules:      rules,
+	}, nil
+}
+
+// The aggregation methods supported by Carbon, see
+// https://graphite.readthedocs.io/en/latest/config-carbon.html#storage-aggregation-conf.
+var storageAggregationMethods = map[string]TargetMetricType{
+	"average":  GaugeMetricType,
+	"sum":      CumulativeMetricType,
+	"min":      GaugeMetricType,
+	"max":      GaugeMetricType,
+	"last":     GaugeMetricType,
+	"avg_zero": GaugeMetricType,
+	"absmax":   GaugeMetricType,
+	"absmin":   GaugeMetricType,
+}
+
+// storageAggregationRule is a section of the storage aggregation file.
+type storageAggregationRule struct {
+	name       string
+	pattern    *regexp.Regexp
+	metricType TargetMetricType
+}
+
+func loadStorageAggregationFile(path string) ([]*storageAggregationRule, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open storage aggregation file: %w", err)
+	}
+	defer f.Close()
+
+	rules, err := parseStorageAggregation(f)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage aggregation file %q: %w", path, err)
+	}
+	return rules, nil
+}
+
+// parseStorageAggregation parses the rules of a storage aggregation file, e.g.:
+//
+// 	[sum]
+// 	pattern = \.count$
+// 	xFilesFactor = 0
+// 	aggregationMethod = sum
+//
+// As done by Carbon, the aggregation method defaults to "average" and the
+// rules are applied in the order of the file.
+func parseStorageAggregation(r io.Reader) ([]*storageAggregationRule, error) {
+	var rules []*storageAggregationRule
+	var rule *storageAggregationRule
+	var pattern string
+	endRule := func() error {
+		if rule == nil {
+			return nil
+		}
+		if pattern == "" {
+			return fmt.Errorf("rule [%s] has no pattern", rule.name)
+		}
+		compiled, err := regexp.Compile(pattern)
+		if err != nil {
+			return fmt.Errorf("rule [%s] has an invalid pattern: %v", rule.name, err)
+		}
+		rule.pattern = compiled
+		rules = append(rules, rule)
+		return nil
+	}
+
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || line[0] == '#' || line[0] == ';' {
+			continue
+		}
+
+		if line[0] == '[' {
+			if line[len(line)-1] != ']' {
+				return nil, fmt.Errorf("invalid line %d", lineNum)
+			}
+			if err := endRule(); err != nil {
+				return nil, err
+			}
+			rule = &storageAggregationRule{
+				name:       strings.TrimSpace(line[1 : len(line)-1]),
+				metricType: storageAggregationMethods["average"],
+			}
+			pattern = ""
+			continue
+		}
+
+		idx := strings.IndexAny(line, "=:")
+		if idx < 1 || rule == nil {
+			return nil, fmt.Errorf("invalid line %d", lineNum)
+		}
+		// Keys are case insensitive, as parsed by Carbon.
+		key := strings.TrimSpace(line[:idx])
+		value := strings.TrimSpace(line[idx+1:])
+		switch strings.ToLower(key) {
+		case "pattern":
+			pattern = value
+		case "aggregationmethod":
+			metricType, ok := storageAggregationMethods[value]
+			if !ok {
+				return nil, fmt.Errorf("unknown aggregation method %q on line %d", value, lineNum)
+			}
+			rule.metricType = metricType
+		case "xfilesfactor":
+			// Only meaningful for the storage of Carbon.
+		default:
+			return nil, fmt.Errorf("unknown key %q on line %d", key, lineNum)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if err := endRule(); err != nil {
+		return nil, err
+	}
+
+	if len(rules) == 0 {
+		return nil, errors.New("no rule was specified")
+	}
+	return rules, nil
+}
+
+// aggregationPathParser sets the type of the metrics parsed by another
+// PathParser according to the first storage aggregation rule matching their
+// path.
+type aggregationPathParser struct {
+	pathParser PathParser
+	rules      []*storageAggregationRule
+}
+
+var _ (PathParser) = (*aggregationPathParser)(nil)
+
+func (app *aggregationPathParser) ParsePath(path string, parsedPath *ParsedPath) error {
+	if err := app.pathParser.ParsePath(path, parsedPath); err != nil {
+		return err
+	}
+	if parsedPath.MetricType != DefaultMetricType {
+		return nil
+	}
+
+	for _, rule := range app.rules {
+		if rule.pattern.MatchString(path) {
+			parsedPath.MetricType = rule.metricType
+			return nil
+		}
+	}
+	return nil
+}
@@ -0,0 +1,274 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"path"
+	"strings"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregationConfigBuildParser(t *testing.T) {
+	tests := []struct {
+		name    string
+		config  ParserConfig
+		wantErr bool
+	}{
+		{
+			name: "plaintext",
+			config: &PlaintextConfig{
+				AggregationConfig: AggregationConfig{
+					StorageAggregationFile: path.Join(".", "testdata", "storage-aggregation.conf"),
+				},
+			},
+		},
+		{
+			name: "regex",
+			config: &RegexParserConfig{
+				Rules: []*RegexRule{
+					{Regexp: "(?P<key_svc>[^.]+)"},
+				},
+				AggregationConfig: AggregationConfig{
+					StorageAggregationFile: path.Join(".", "testdata", "storage-aggregation.conf"),
+				},
+			},
+		},
+		{
+			name: "missing_file",
+			config: &PlaintextConfig{
+				AggregationConfig: AggregationConfig{
+					StorageAggregationFile: path.Join(".", "testdata", "missing.conf"),
+				},
+			},
+			wantErr: true,
+		},
+		{
+			name: "negative_flush_interval",
+			config: &PlaintextConfig{
+				AggregationConfig: AggregationConfig{
+					SummaryFlushInterval: -1,
+				},
+			},
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.config.BuildParser()
+			if tt.wantErr {
+				assert.Error(t, err)
+				assert.Nil(t, got)
+				return
+			}
+
+			assert.NoError(t, err)
+			require.NotNil(t, got)
+		})
+	}
+}
+
+func TestParseStorageAggregation(t *testing.T) {
+	tests := []struct {
+		name      string
+		file      string
+		wantRules []*storageAggregationRule
+		wantErr   string
+	}{
+		{
+			name: "valid",
+			file: `
+; comment
+[sum]
+pattern = \.count$
+AGGREGATIONMETHOD = sum
+
+[default]
+pattern: .*
+xFilesFactor = 0.5
+`,
+			wantRules: []*storageAggregationRule{
+				{name: "sum", metricType: CumulativeMetricType},
+				{name: "default", metricType: GaugeMetricType},
+			},
+		},
+		{
+			name:    "no_rules",
+			file:    "# comment\n",
+			wantErr: "no rule was specified",
+		},
+		{
+			name:    "no_pattern",
+			file:    "[sum]\naggregationMethod = sum\n",
+			wantErr: "rule [sum] has no pattern",
+		},
+		{
+			name:    "invalid_pattern",
+			file:    "[sum]\npattern = (\n",
+			wantErr: "rule [sum] has an invalid pattern",
+		},
+		{
+			name:    "unknown_method",
+			file:    "[sum]\npattern = .*\naggregationMethod = total\n",
+			wantErr: `unknown aggregation method "total" on line 3`,
+		},
+		{
+			name:    "unknown_key",
+			file:    "[sum]\npattern = .*\nretentions = 60:90d\n",
+			wantErr: `unknown key "retentions" on line 3`,
+		},
+		{
+			name:    "key_outside_rule",
+			file:    "pattern = .*\n",
+			wantErr: "invalid line 1",
+		},
+		{
+			name:    "invalid_section",
+			file:    "[sum\n",
+			wantErr: "invalid line 1",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := parseStorageAggregation(strings.NewReader(tt.file))
+			if tt.wantErr != "" {
+				require.Error(t, err)
+				assert.Contains(t, err.Error(), tt.wantErr)
+				return
+			}
+
+			require.NoError(t, err)
+			require.Len(t, got, len(tt.wantRules))
+			for i, rule := range got {
+				assert.Equal(t, tt.wantRules[i].name, rule.name)
+				assert.Equal(t, tt.wantRules[i].metricType, rule.metricType)
+				assert.NotNil(t, rule.pattern)
+			}
+		})
+	}
+}
+
+func Test_aggregationPathParser_parsePath(t *testing.T) {
+	rules, err := loadStorageAggregationFile(path.Join(".", "testdata", "storage-aggregation.conf"))
+	require.NoError(t, err)
+
+	regexRules := []*RegexRule{
+		{
+			Regexp:     `^(?P<key_svc>[^.]+)\.requests\.count$`,
+			NamePrefix: "requests",
+			MetricType: string(GaugeMetricType),
+		},
+		{
+			Regexp:     `^(?P<key_svc>[^.]+)\.errors\.count$`,
+			NamePrefix: "errors",
+		},
+	}
+	require.NoError(t, compileRegexRules(regexRules))
+
+	tests := []struct {
+		name           string
+		pathParser     PathParser
+		path           string
+		wantName       string
+		wantKeys       []*metricspb.LabelKey
+		wantMetricType TargetMetricType
+		wantErr        bool
+	}{
+		{
+			name:           "sum_rule",
+			pathParser:     &PlaintextPathParser{},
+			path:           "stats.timers.api.count",
+			wantName:       "stats.timers.api.count",
+			wantMetricType: CumulativeMetricType,
+		},
+		{
+			name:           "tagged_sum_rule",
+			pathParser:     &PlaintextPathParser{},
+			path:           "stats_counts.api;env=prod",
+			wantName:       "stats_counts.api",
+			wantKeys:       []*metricspb.LabelKey{{Key: "env"}},
+			wantMetricType: CumulativeMetricType,
+		},
+		{
+			name:           "max_rule",
+			pathParser:     &PlaintextPathParser{},
+			path:           "stats.timers.api.upper_90",
+			wantName:       "stats.timers.api.upper_90",
+			wantMetricType: GaugeMetricType,
+		},
+		{
+			name:           "regex_type_takes_precedence",
+			pathParser:     &regexPathParser{rules: regexRules},
+			path:           "api.requests.count",
+			wantName:       "requests",
+			wantKeys:       []*metricspb.LabelKey{{Key: "svc"}},
+			wantMetricType: GaugeMetricType,
+		},
+		{
+			name:           "regex_without_type",
+			pathParser:     &regexPathParser{rules: regexRules},
+			path:           "api.errors.count",
+			wantName:       "errors",
+			wantKeys:       []*metricspb.LabelKey{{Key: "svc"}},
+			wantMetricType: CumulativeMetricType,
+		},
+		{
+			name:       "invalid_path",
+			pathParser: &PlaintextPathParser{},
+			path:       ";k=v",
+			wantErr:    true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			app := &aggregationPathParser{
+				pathParser: tt.pathParser,
+				rules:      rules,
+			}
+			got := ParsedPath{}
+			err := app.ParsePath(tt.path, &got)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.wantName, got.MetricName)
+			assert.Equal(t, tt.wantKeys, got.LabelKeys)
+			assert.Equal(t, tt.wantMetricType, got.MetricType)
+		})
+	}
+}
+
+func TestAggregationParser(t *testing.T) {
+	config := &PlaintextConfig{
+		AggregationConfig: AggregationConfig{
+			StorageAggregationFile: path.Join(".", "testdata", "storage-aggregation.conf"),
+		},
+	}
+	p, err := config.BuildParser()
+	require.NoError(t, err)
+
+	got, err := p.Parse("stats.timers.api.count 42 1582230020")
+	require.NoError(t, err)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, got.MetricDescriptor.Type)
+
+	got, err = p.Parse("stats.timers.api.mean 4.2 1582230020")
+	require.NoError(t, err)
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, got.MetricDescriptor.Type)
+}
@@ -0,0 +1,595 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"bytes"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+)
+
+// ParsePickle decodes a message of Carbon's pickle protocol, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol,
+// into the plaintext lines of its datapoints, so that they can be handled by
+// any Parser. The message is the pickled list of datapoints, without the
+// length header, in the following format:
+//
+// 	[(<metric_path>, (<metric_timestamp>, <metric_value>)), ...]
+//
+// Datapoints that can't be converted are skipped and reported in invalid,
+// while err reports messages that can't be decoded at all.
+func ParsePickle(message []byte) (lines []string, invalid []error, err error) {
+	obj, err := unpickle(message)
+	if err != nil {
+		return nil, nil, fmt.Errorf("invalid pickle message: %v", err)
+	}
+	datapoints, ok := sequenceItems(obj)
+	if !ok {
+		return nil, nil, fmt.Errorf("invalid pickle message: expected a list of datapoints, got %T", obj)
+	}
+
+	lines = make([]string, 0, len(datapoints))
+	for _, datapoint := range datapoints {
+		line, err := pickledDatapointToLine(datapoint)
+		if err != nil {
+			invalid = append(invalid, err)
+			continue
+		}
+		lines = append(lines, line)
+	}
+	return lines, invalid, nil
+}
+
+func pickledDatapointToLine(datapoint interface{}) (string, error) {
+	items, ok := sequenceItems(datapoint)
+	if !ok || len(items) != 2 {
+		return "", fmt.Errorf("invalid pickle datapoint %v", datapoint)
+	}
+	path, ok := pickleString(items[0])
+	if !ok || path == "" || strings.ContainsAny(path, " \n") {
+		return "", fmt.Errorf("invalid pickle datapoint path %v", items[0])
+	}
+	point, ok := sequenceItems(items[1])
+	if !ok || len(point) != 2 {
+		return "", fmt.Errorf("invalid pickle datapoint %v for [%s]", items[1], path)
+	}
+
+	var timestamp int64
+	switch ts := point[0].(type) {
+	case int64:
+		timestamp = ts
+	case float64:
+		// Carbon only keeps the seconds.
+		timestamp = int64(ts)
+	default:
+		return "", fmt.Errorf("invalid pickle datapoint timestamp %v for [%s]", point[0], path)
+	}
+
+	var value string
+	switch v := point[1].(type) {
+	case int64:
+		value = strconv.FormatInt(v, 10)
+	case *big.Int:
+		value = v.String()
+	case float64:
+		value = formatPickledFloat(v)
+	case bool:
+		value = "0"
+		if v {
+			value = "1"
+		}
+	default:
+		return "", fmt.Errorf("invalid pickle datapoint value %v for [%s]", point[1], path)
+	}
+
+	return path + " " + value + " " + strconv.FormatInt(timestamp, 10), nil
+}
+
+// formatPickledFloat formats Python floats so that they are parsed as
+// doubles, e.g. 1.0 as "1.0" rather than "1".
+func formatPickledFloat(f float64) string {
+	s := strconv.FormatFloat(f, 'g', -1, 64)
+	if math.IsInf(f, 0) || math.IsNaN(f) || strings.ContainsAny(s, ".e") {
+		return s
+	}
+	return s + ".0"
+}
+
+// The Python types without a direct Go equivalent. Lists are pointers since
+// they can be referenced from the memo and modified afterwards.
+type (
+	pickleList  struct{ items []interface{} }
+	pickleTuple []interface{}
+	pickleMark  struct{}
+)
+
+func sequenceItems(obj interface{}) ([]interface{}, bool) {
+	switch seq := obj.(type) {
+	case *pickleList:
+		return seq.items, true
+	case pickleTuple:
+		return seq, true
+	}
+	return nil, false
+}
+
+func pickleString(obj interface{}) (string, bool) {
+	switch s := obj.(type) {
+	case string:
+		return s, true
+	case []byte:
+		return string(s), true
+	}
+	return "", false
+}
+
+// Opcodes of the pickle protocols 0 to 4 needed to decode lists and tuples of
+// strings and numbers, see Python's pickletools. Opcodes that build arbitrary
+// objects, e.g. GLOBAL and REDUCE, are deliberately not supported.
+const (
+	opMark           = '('
+	opStop           = '.'
+	opPop            = '0'
+	opPopMark        = '1'
+	opDup            = '2'
+	opFloat          = 'F'
+	opInt            = 'I'
+	opBinInt         = 'J'
+	opBinInt1        = 'K'
+	opLong           = 'L'
+	opBinInt2        = 'M'
+	opNone           = 'N'
+	opString         = 'S'
+	opBinString      = 'T'
+	opShortBinString = 'U'
+	opUnicode        = 'V'
+	opBinUnicode     = 'X'
+	opAppend         = 'a'
+	opAppends        = 'e'
+	opGet            = 'g'
+	opBinGet         = 'h'
+	opLongBinGet     = 'j'
+	opList           = 'l'
+	opEmptyList      = ']'
+	opPut            = 'p'
+	opBinPut         = 'q'
+	opLongBinPut     = 'r'
+	opTuple          = 't'
+	opEmptyTuple     = ')'
+	opBinFloat       = 'G'
+	opBinBytes       = 'B'
+	opShortBinBytes  = 'C'
+	opProto          = 0x80
+	opTuple1         = 0x85
+	opTuple2         = 0x86
+	opTuple3         = 0x87
+	opNewTrue        = 0x88
+	opNewFalse       = 0x89
+	opLong1          = 0x8a
+	opLong4          = 0x8b
+	opShortBinUni    = 0x8c
+	opBinUnicode8    = 0x8d
+	opBinBytes8      = 0x8e
+	opMemoize        = 0x94
+	opFrame          = 0x95
+)
+
+var errPickleStack = errors.New("stack underflow")
+
+type unpickler struct {
+	r     *bytes.Reader
+	stack []interface{}
+	memo  map[int64]interface{}
+}
+
+// unpickle decodes the pickled object in data.
+func unpickle(data []byte) (interface{}, error) {
+	u := &unpickler{
+		r:    bytes.NewReader(data),
+		memo: map[int64]interface{}{},
+	}
+	for {
+		op, err := u.r.ReadByte()
+		if err != nil {
+			return nil, unexpectedEOF(err)
+		}
+		if op == opStop {
+			return u.pop()
+		}
+		if err := u.execute(op); err != nil {
+			return nil, err
+		}
+	}
+}
+
+func (u *unpickler) execute(op byte) error {
+	switch op {
+	case opProto:
+		_, err := u.r.ReadByte()
+		return unexpectedEOF(err)
+	case opFrame:
+		// Frames only help buffering, the data is already in memory.
+		_, err := u.readN(8)
+		return err
+	case opMark:
+		u.push(pickleMark{})
+	case opPop:
+		_, err := u.pop()
+		return err
+	case opPopMark:
+		_, err := u.popMark()
+		return err
+	case opDup:
+		top, err := u.top()
+		if err != nil {
+			return err
+		}
+		u.push(top)
+	case opNone:
+		u.push(nil)
+	case opNewTrue:
+		u.push(true)
+	case opNewFalse:
+		u.push(false)
+	case opInt:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		// Protocol 0 encodes booleans as INT.
+		switch line {
+		case "00":
+			u.push(false)
+		case "01":
+			u.push(true)
+		default:
+			return u.pushIntString(line)
+		}
+	case opLong:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		return u.pushIntString(strings.TrimSuffix(line, "L"))
+	case opBinInt:
+		b, err := u.readN(4)
+		if err != nil {
+			return err
+		}
+		u.push(int64(int32(binary.LittleEndian.Uint32(b))))
+	case opBinInt1:
+		b, err := u.readN(1)
+		if err != nil {
+			return err
+		}
+		u.push(int64(b[0]))
+	case opBinInt2:
+		b, err := u.readN(2)
+		if err != nil {
+			return err
+		}
+		u.push(int64(binary.LittleEndian.Uint16(b)))
+	case opLong1, opLong4:
+		n, err := u.readLength(op == opLong1, false)
+		if err != nil {
+			return err
+		}
+		b, err := u.readN(n)
+		if err != nil {
+			return err
+		}
+		u.push(decodeLong(b))
+	case opFloat:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		f, err := strconv.ParseFloat(line, 64)
+		if err != nil {
+			return err
+		}
+		u.push(f)
+	case opBinFloat:
+		b, err := u.readN(8)
+		if err != nil {
+			return err
+		}
+		u.push(math.Float64frombits(binary.BigEndian.Uint64(b)))
+	case opString:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		s, err := unquotePythonString(line)
+		if err != nil {
+			return err
+		}
+		u.push(s)
+	case opUnicode:
+		line, err := u.readLine()
+		if err != nil {
+			return err
+		}
+		u.push(line)
+	case opShortBinString, opBinString, opShortBinUni, opBinUnicode, opBinUnicode8:
+		s, err := u.readString(op == opShortBinString || op == opShortBinUni, op == opBinUnicode8)
+		if err != nil {
+			return err
+		}
+		u.push(s)
+	case opShortBinBytes, opBinBytes, opBinBytes8:
+		s, err := u.readString(op == opShortBinBytes, op == opBinBytes8)
+		if err != nil {
+			return err
+		}
+		u.push([]byte(s))
+	case opEmptyList:
+		u.push(&pickleList{})
+	case opList:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		u.push(&pickleList{items: items})
+	case opAppend:
+		item, err := u.pop()
+		if err != nil {
+			return err
+		}
+		return u.appendToList(item)
+	case opAppends:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		return u.appendToList(items...)
+	case opEmptyTuple:
+		u.push(pickleTuple{})
+	case opTuple:
+		items, err := u.popMark()
+		if err != nil {
+			return err
+		}
+		u.push(pickleTuple(items))
+	case opTuple1, opTuple2, opTuple3:
+		n := int(op-opTuple1) + 1
+		if len(u.stack) < n {
+			return errPickleStack
+		}
+		items := make(pickleTuple, n)
+		copy(items, u.stack[len(u.stack)-n:])
+		u.stack = u.stack[:len(u.stack)-n]
+		u.push(items)
+	case opPut, opBinPut, opLongBinPut, opMemoize:
+		var key int64
+		switch op {
+		case opMemoize:
+			key = int64(len(u.memo))
+		default:
+			var err error
+			if key, err = u.readMemoKey(op == opPut, op == opBinPut); err != nil {
+				return err
+			}
+		}
+		top, err := u.top()
+		if err != nil {
+			return err
+		}
+		u.memo[key] = top
+	case opGet, opBinGet, opLongBinGet:
+		key, err := u.readMemoKey(op == opGet, op == opBinGet)
+		if err != nil {
+			return err
+		}
+		obj, ok := u.memo[key]
+		if !ok {
+			return fmt.Errorf("memo key %d not found", key)
+		}
+		u.push(obj)
+	default:
+		return fmt.Errorf("unsupported opcode 0x%02x", op)
+	}
+	return nil
+}
+
+func (u *unpickler) push(obj interface{}) {
+	u.stack = append(u.stack, obj)
+}
+
+func (u *unpickler) pop() (interface{}, error) {
+	obj, err := u.top()
+	if err != nil {
+		return nil, err
+	}
+	u.stack = u.stack[:len(u.stack)-1]
+	return obj, nil
+}
+
+func (u *unpickler) top() (interface{}, error) {
+	if len(u.stack) == 0 {
+		return nil, errPickleStack
+	}
+	return u.stack[len(u.stack)-1], nil
+}
+
+// popMark pops the objects up to the topmost mark, and the mark.
+func (u *unpickler) popMark() ([]interface{}, error) {
+	for i := len(u.stack) - 1; i >= 0; i-- {
+		if _, ok := u.stack[i].(pickleMark); ok {
+			items := make([]interface{}, len(u.stack)-i-1)
+			copy(items, u.stack[i+1:])
+			u.stack = u.stack[:i]
+			return items, nil
+		}
+	}
+	return nil, errors.New("mark not found")
+}
+
+func (u *unpickler) appendToList(items ...interface{}) error {
+	top, err := u.top()
+	if err != nil {
+		return err
+	}
+	list, ok := top.(*pickleList)
+	if !ok {
+		return fmt.Errorf("cannot append to %T", top)
+	}
+	list.items = append(list.items, items...)
+	return nil
+}
+
+func (u *unpickler) readN(n int) ([]byte, error) {
+	// Checking the length first avoids allocating for invalid lengths.
+	if n < 0 || n > u.r.Len() {
+		return nil, fmt.Errorf("invalid length %d", n)
+	}
+	b := make([]byte, n)
+	_, err := io.ReadFull(u.r, b)
+	return b, unexpectedEOF(err)
+}
+
+func (u *unpickler) readLine() (string, error) {
+	var sb strings.Builder
+	for {
+		c, err := u.r.ReadByte()
+		if err != nil {
+			return "", unexpectedEOF(err)
+		}
+		if c == '\n' {
+			return strings.TrimSuffix(sb.String(), "\r"), nil
+		}
+		sb.WriteByte(c)
+	}
+}
+
+// readLength reads the length of strings, bytes and longs, encoded in 1, 4
+// or 8 bytes.
+func (u *unpickler) readLength(short, long bool) (int, error) {
+	switch {
+	case short:
+		b, err := u.readN(1)
+		if err != nil {
+			return 0, err
+		}
+		return int(b[0]), nil
+	case long:
+		b, err := u.readN(8)
+		if err != nil {
+			return 0, err
+		}
+		n := binary.LittleEndian.Uint64(b)
+		if n > math.MaxInt32 {
+			return 0, fmt.Errorf("invalid length %d", n)
+		}
+		return int(n), nil
+	}
+	b, err := u.readN(4)
+	if err != nil {
+		return 0, err
+	}
+	return int(int32(binary.LittleEndian.Uint32(b))), nil
+}
+
+func (u *unpickler) readString(short, long bool) (string, error) {
+	n, err := u.readLength(short, long)
+	if err != nil {
+		return "", err
+	}
+	b, err := u.readN(n)
+	return string(b), err
+}
+
+func (u *unpickler) readMemoKey(text, short bool) (int64, error) {
+	if text {
+		line, err := u.readLine()
+		if err != nil {
+			return 0, err
+		}
+		return strconv.ParseInt(line, 10, 64)
+	}
+	if short {
+		b, err := u.readN(1)
+		if err != nil {
+			return 0, err
+		}
+		return int64(b[0]), nil
+	}
+	b, err := u.readN(4)
+	if err != nil {
+		return 0, err
+	}
+	return int64(binary.LittleEndian.Uint32(b)), nil
+}
+
+// pushIntString pushes the integer of a text INT or LONG, which may not fit in
+// an int64.
+func (u *unpickler) pushIntString(s string) error {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		u.push(i)
+		return nil
+	}
+	i, ok := new(big.Int).SetString(s, 10)
+	if !ok {
+		return fmt.Errorf("invalid integer %q", s)
+	}
+	u.push(i)
+	return nil
+}
+
+// decodeLong decodes the little endian two's complement integer of LONG1 and
+// LONG4, returning an int64 when it fits.
+func decodeLong(b []byte) interface{} {
+	if len(b) == 0 {
+		return int64(0)
+	}
+	be := make([]byte, len(b))
+	for i := range b {
+		be[len(b)-1-i] = b[i]
+	}
+	i := new(big.Int).SetBytes(be)
+	if b[len(b)-1]&0x80 != 0 {
+		i.Sub(i, new(big.Int).Lsh(big.NewInt(1), uint(8*len(b))))
+	}
+	if i.IsInt64() {
+		return i.Int64()
+	}
+	return i
+}
+
+// unquotePythonString unquotes the repr of a Python 2 str, as written by
+// protocol 0.
+func unquotePythonString(s string) (string, error) {
+	if len(s) < 2 || s[0] != s[len(s)-1] || (s[0] != '\'' && s[0] != '"') {
+		return "", fmt.Errorf("invalid string %q", s)
+	}
+	inner := s[1 : len(s)-1]
+	if s[0] == '\'' {
+		inner = strings.ReplaceAll(strings.ReplaceAll(inner, `\'`, "'"), `"`, `\"`)
+	}
+	return strconv.Unquote(`"` + inner + `"`)
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
@@ -0,0 +1,106 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package protocol
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The pickles below were generated with Python's pickle.dumps of:
+//
+// 	[("test.metric", (1582230020, 1.5)),
+// 	 ("test.tagged;k0=v0", (1582230020.7, 2)),
+// 	 ("test.big", (1582230020, 2**70))]
+const (
+	pickleProtocol0 = "(lp0\n(Vtest.metric\np1\n(I1582230020\nF1.5\ntp2\ntp3\na(Vtest.tagged;k0=v0\np4\n(F1582230020.7\nI2\ntp5\ntp6\na(Vtest.big\np7\n(I1582230020\nL1180591620717411303424L\ntp8\ntp9\na."
+	pickleProtocol2 = "\x80\x02]q\x00(X\x0b\x00\x00\x00test.metricq\x01J\x04\xeaN^G?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\x11\x00\x00\x00test.tagged;k0=v0q\x04GA\xd7\x93\xba\x81,\xcc\xcdK\x02\x86q\x05\x86q\x06X\x08\x00\x00\x00test.bigq\x07J\x04\xeaN^\x8a\x09\x00\x00\x00\x00\x00\x00\x00\x00@\x86q\x08\x86q\x09e."
+	pickleProtocol4 = "\x80\x04\x95g\x00\x00\x00\x00\x00\x00\x00]\x94(\x8c\x0btest.metric\x94J\x04\xeaN^G?\xf8\x00\x00\x00\x00\x00\x00\x86\x94\x86\x94\x8c\x11test.tagged;k0=v0\x94GA\xd7\x93\xba\x81,\xcc\xcdK\x02\x86\x94\x86\x94\x8c\x08test.big\x94J\x04\xeaN^\x8a\x09\x00\x00\x00\x00\x00\x00\x00\x00@\x86\x94\x86\x94e."
+)
+
+func TestParsePickle(t *testing.T) {
+	want := []string{
+		"test.metric 1.5 1582230020",
+		"test.tagged;k0=v0 2 1582230020",
+		"test.big 1180591620717411303424 1582230020",
+	}
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "protocol_0", message: pickleProtocol0},
+		{name: "protocol_2", message: pickleProtocol2},
+		{name: "protocol_4", message: pickleProtocol4},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			lines, invalid, err := ParsePickle([]byte(tt.message))
+			require.NoError(t, err)
+			assert.Empty(t, invalid)
+			assert.Equal(t, want, lines)
+		})
+	}
+}
+
+func TestParsePickleMemo(t *testing.T) {
+	// [("a", t), ("b", t)] with t = (1582230020, 1.0), the second tuple is
+	// a reference to the first one.
+	lines, invalid, err := ParsePickle([]byte("\x80\x02]q\x00(X\x01\x00\x00\x00aq\x01J\x04\xeaN^G?\xf0\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\x01\x00\x00\x00bq\x04h\x02\x86q\x05e."))
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	assert.Equal(t, []string{"a 1.0 1582230020", "b 1.0 1582230020"}, lines)
+}
+
+func TestParsePickleNegative(t *testing.T) {
+	// [("n", (-5, -2**40))]
+	lines, invalid, err := ParsePickle([]byte("\x80\x02]q\x00X\x01\x00\x00\x00nq\x01J\xfb\xff\xff\xff\x8a\x06\x00\x00\x00\x00\x00\xff\x86q\x02\x86q\x03a."))
+	require.NoError(t, err)
+	assert.Empty(t, invalid)
+	assert.Equal(t, []string{"n -1099511627776 -5"}, lines)
+}
+
+func TestParsePickleInvalidDatapoint(t *testing.T) {
+	// [("a", (1, None)), ("b", (2, 3))]
+	lines, invalid, err := ParsePickle([]byte("\x80\x02]q\x00(X\x01\x00\x00\x00aq\x01K\x01N\x86q\x02\x86q\x03X\x01\x00\x00\x00bq\x04K\x02K\x03\x86q\x05\x86q\x06e."))
+	require.NoError(t, err)
+	assert.Len(t, invalid, 1)
+	assert.Equal(t, []string{"b 3 2"}, lines)
+}
+
+func TestParsePickleInvalid(t *testing.T) {
+	tests := []struct {
+		name    string
+		message string
+	}{
+		{name: "empty", message: ""},
+		// {"a": 1}
+		{name: "dict", message: "\x80\x02}q\x00X\x01\x00\x00\x00aq\x01K\x01s."},
+		{name: "not_a_list", message: "\x80\x02K\x01."},
+		{name: "truncated", message: pickleProtocol2[:20]},
+		{name: "no_stop", message: "\x80\x02]"},
+		{name: "invalid_length", message: "\x80\x02X\xff\xff\xff\x7f."},
+		{name: "global", message: "cos\nsystem\n."},
+		{name: "missing_mark", message: "\x80\x02t."},
+		{name: "missing_memo", message: "\x80\x02h\x01."},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, _, err := ParsePickle([]byte(tt.message))
+			assert.Error(t, err)
+		})
+	}
+}
@@ -16,20 +16,26 @@ package protocol
 
 import (
 	"fmt"
+	"sort"
 	"strings"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 )
 
 // PlaintextConfig holds the configuration for the plaintext parser.
-type PlaintextConfig struct{}
+type PlaintextConfig struct {
+	AggregationConfig `mapstructure:",squash"`
+}
 
-var _ (ParserConfig) = (*PlaintextConfig)(nil)
+var _ (AggregationParserConfig) = (*PlaintextConfig)(nil)
 
 // BuildParser creates a new Parser instance that receives plaintext
 // Carbon data.
 func (p *PlaintextConfig) BuildParser() (Parser, error) {
-	pathParser := &PlaintextPathParser{}
+	pathParser, err := p.buildPathParser(&PlaintextPathParser{})
+	if err != nil {
+		return nil, err
+	}
 	return NewParser(pathParser)
 }
 
@@ -47,7 +53,11 @@ type PlaintextPathParser struct{}
 // or at the end of the path.
 //
 // tag is of the form "key=val", where key can contain any char except ";!^=" and
-// val can contain any char except ";~".
+// val can contain any char except ";" and can't start with "~". As done by
+// Graphite, tags are sorted by key and the last value of a repeated key wins.
+//
+// The values "<empty>" and "<null>", which the Carbon exporter uses for empty
+// and unset label values, are converted back to the respective label values.
 func (p *PlaintextPathParser) ParsePath(path string, parsedPath *ParsedPath) error {
 	parts := strings.SplitN(path, ";", 2)
 	if len(parts) < 1 || parts[0] == "" {
@@ -66,8 +76,7 @@ func (p *PlaintextPathParser) ParsePath(path string, parsedPath *ParsedPath) err
 	}
 
 	tags := strings.Split(parts[1], ";")
-	keys := make([]*metricspb.LabelKey, 0, len(tags))
-	values := make([]*metricspb.LabelValue, 0, len(tags))
+	tagValues := make(map[string]*metricspb.LabelValue, len(tags))
 	for _, tag := range tags {
 		idx := strings.IndexByte(tag, '=')
 		if idx < 1 {
@@ -75,13 +84,27 @@ func (p *PlaintextPathParser) ParsePath(path string, parsedPath *ParsedPath) err
 		}
 
 		key := tag[:idx]
-		keys = append(keys, &metricspb.LabelKey{Key: key})
+		if strings.ContainsAny(key, tagKeyInvalidChars) {
+			return fmt.Errorf("cannot parse metric path [%s]: invalid tag key [%s]", path, key)
+		}
 
 		value := tag[idx+1:] // If value is empty, ie.: tag == "k=", this will return "".
-		values = append(values, &metricspb.LabelValue{
-			Value:    value,
-			HasValue: true,
-		})
+		if strings.HasPrefix(value, "~") {
+			return fmt.Errorf("cannot parse metric path [%s]: invalid tag value [%s]", path, value)
+		}
+		tagValues[key] = parseTagValue(value)
+	}
+
+	keys := make([]*metricspb.LabelKey, 0, len(tagValues))
+	for key := range tagValues {
+		keys = append(keys, &metricspb.LabelKey{Key: key})
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Key < keys[j].Key
+	})
+	values := make([]*metricspb.LabelValue, 0, len(keys))
+	for _, key := range keys {
+		values = append(values, tagValues[key.Key])
 	}
 
 	parsedPath.LabelKeys = keys
@@ -89,6 +112,27 @@ func (p *PlaintextPathParser) ParsePath(path string, parsedPath *ParsedPath) err
 	return nil
 }
 
+const (
+	// tagKeyInvalidChars are the chars that Graphite doesn't allow in tag
+	// keys, besides ';' and '=' that can't appear after splitting the path.
+	tagKeyInvalidChars = "!^"
+
+	// Placeholders of the Carbon exporter for label values that Graphite
+	// doesn't allow.
+	tagValueEmptyPlaceholder  = "<empty>"
+	tagValueNotSetPlaceholder = "<null>"
+)
+
+func parseTagValue(value string) *metricspb.LabelValue {
+	switch value {
+	case tagValueEmptyPlaceholder:
+		return &metricspb.LabelValue{Value: "", HasValue: true}
+	case tagValueNotSetPlaceholder:
+		return &metricspb.LabelValue{}
+	}
+	return &metricspb.LabelValue{Value: value, HasValue: true}
+}
+
 func plaintextDefaultConfig() ParserConfig {
 	return &PlaintextConfig{}
 }
@@ -155,6 +155,46 @@ func TestPlaintextParser_parsePath(t *testing.T) {
 				{Value: "v1", HasValue: true},
 			},
 		},
+		{
+			name:     "tags_sorted_by_key",
+			path:     "sorted.tags;k1=v1;k0=v0;k1=v2",
+			wantName: "sorted.tags",
+			wantKeys: []*metricspb.LabelKey{{Key: "k0"}, {Key: "k1"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "v0", HasValue: true},
+				{Value: "v2", HasValue: true},
+			},
+		},
+		{
+			name:     "tag_value_with_separator",
+			path:     "tag.value.separator;k0=a=b;k1=not~first",
+			wantName: "tag.value.separator",
+			wantKeys: []*metricspb.LabelKey{{Key: "k0"}, {Key: "k1"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "a=b", HasValue: true},
+				{Value: "not~first", HasValue: true},
+			},
+		},
+		{
+			name:     "tag_value_placeholders",
+			path:     "tag.value.placeholders;k0=<empty>;k1=<null>",
+			wantName: "tag.value.placeholders",
+			wantKeys: []*metricspb.LabelKey{{Key: "k0"}, {Key: "k1"}},
+			wantValues: []*metricspb.LabelValue{
+				{Value: "", HasValue: true},
+				{},
+			},
+		},
+		{
+			name:    "invalid_tag_key",
+			path:    "invalid.tag.key;k!0=v0",
+			wantErr: true,
+		},
+		{
+			name:    "invalid_tag_value",
+			path:    "invalid.tag.value;k0=~v0",
+			wantErr: true,
+		},
 		{
 			name:     "empty_tag_value_end",
 			path:     "empty.tag.value.end;k0=v0;k1=",
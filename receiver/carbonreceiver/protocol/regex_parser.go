@@ -68,6 +68,8 @@ type RegexParserConfig struct {
 	// rule and the respective named captures that start with the prefix
 	// "name_" (see RegexRule for more information).
 	MetricNameSeparator string `mapstructure:"name_separator"`
+
+	AggregationConfig `mapstructure:",squash"`
 }
 
 // RegexRule describes how parts of the name of metric are going to be mapped
@@ -95,7 +97,7 @@ type RegexRule struct {
 	metricNameParts []string
 }
 
-var _ (ParserConfig) = (*RegexParserConfig)(nil)
+var _ (AggregationParserConfig) = (*RegexParserConfig)(nil)
 
 // BuildParser builds the respective parser of the configuration instance.
 func (rpc *RegexParserConfig) BuildParser() (Parser, error) {
@@ -112,7 +114,11 @@ func (rpc *RegexParserConfig) BuildParser() (Parser, error) {
 		metricNameSeparator: rpc.MetricNameSeparator,
 	}
 
-	return NewParser(rpp)
+	pathParser, err := rpc.buildPathParser(rpp)
+	if err != nil {
+		return nil, err
+	}
+	return NewParser(pathParser)
 }
 
 func compileRegexRules(rules []*RegexRule) error {
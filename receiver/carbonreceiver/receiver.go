@@ -36,16 +36,18 @@ var (
 )
 
 // carbonreceiver implements a component.MetricsReceiver for Carbon plaintext, aka "line", protocol.
-// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol.
+// see https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-plaintext-protocol,
+// and for Carbon pickle protocol.
 type carbonReceiver struct {
 	sync.Mutex
 	logger *zap.Logger
 	config *Config
 
-	server       transport.Server
-	reporter     transport.Reporter
-	parser       protocol.Parser
-	nextConsumer consumer.MetricsConsumer
+	server         transport.Server
+	reporter       transport.Reporter
+	parser         protocol.Parser
+	nextConsumer   consumer.MetricsConsumer
+	summaryGrouper *summaryGrouper
 
 	startOnce sync.Once
 	stopOnce  sync.Once
@@ -97,6 +99,13 @@ func New(
 		parser:       parser,
 	}
 
+	if aggCfg, ok := config.Parser.Config.(protocol.AggregationParserConfig); ok {
+		if flushInterval := aggCfg.Aggregation().SummaryFlushInterval; flushInterval > 0 {
+			r.summaryGrouper = newSummaryGrouper(nextConsumer, flushInterval, r.reporter, logger)
+			r.nextConsumer = r.summaryGrouper
+		}
+	}
+
 	return &r, nil
 }
 
@@ -106,6 +115,8 @@ func buildTransportServer(config Config, logger *zap.Logger) (transport.Server,
 		return transport.NewTCPServer(config.Endpoint, config.TCPIdleTimeout)
 	case "udp":
 		return transport.NewUDPServer(config.Endpoint)
+	case "pickle":
+		return transport.NewPickleServer(config.Endpoint, config.TCPIdleTimeout)
 	}
 
 	return nil, fmt.Errorf("unsupported transport %q for receiver %q", config.Transport, config.Name())
@@ -121,6 +132,9 @@ func (r *carbonReceiver) Start(_ context.Context, host component.Host) error {
 	err := componenterror.ErrAlreadyStarted
 	r.startOnce.Do(func() {
 		err = nil
+		if r.summaryGrouper != nil {
+			r.summaryGrouper.start()
+		}
 		go func() {
 			err = r.server.ListenAndServe(r.parser, r.nextConsumer, r.reporter)
 			if err != nil {
@@ -141,6 +155,9 @@ func (r *carbonReceiver) Shutdown(context.Context) error {
 	err := componenterror.ErrAlreadyStopped
 	r.stopOnce.Do(func() {
 		err = r.server.Close()
+		if r.summaryGrouper != nil {
+			r.summaryGrouper.stop()
+		}
 	})
 	return err
 }
@@ -0,0 +1,351 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+const (
+	summaryCountSuffix = ".count"
+	summarySumSuffix   = ".sum"
+
+	// summaryExpiryIntervals is the number of flush intervals after which a
+	// metric whose percentiles are no longer received is forgotten, and its
+	// ".count" and ".sum" siblings are passed unchanged again.
+	summaryExpiryIntervals = 10
+)
+
+// summaryPercentileSuffixes maps the suffixes of the percentile siblings of a
+// metric to their percentile.
+var summaryPercentileSuffixes = map[string]float64{
+	".p50": 50,
+	".p90": 90,
+	".p99": 99,
+}
+
+// summaryGrouper is a consumer.MetricsConsumer that holds the ".p50", ".p90",
+// ".p99", ".count" and ".sum" siblings of a metric, commonly sent by StatsD
+// for timers, until the end of the flush interval, and then passes them to
+// the next consumer as a single summary metric. The ".count" and ".sum"
+// siblings are only held for the metrics already received with a percentile,
+// the others are passed right away, since a metric named "*.count" or "*.sum"
+// doesn't need to be part of a summary.
+type summaryGrouper struct {
+	nextConsumer  consumer.MetricsConsumer
+	flushInterval time.Duration
+	reporter      transport.Reporter
+	logger        *zap.Logger
+
+	mu     sync.Mutex
+	groups map[string]*summaryGroup
+	// summaries holds the last time a percentile was received for the keys
+	// of the groups.
+	summaries map[string]time.Time
+
+	done chan struct{}
+	wg   sync.WaitGroup
+}
+
+var _ consumer.MetricsConsumer = (*summaryGrouper)(nil)
+
+// summaryGroup holds the siblings of a metric with the same labels.
+type summaryGroup struct {
+	name        string
+	labelKeys   []*metricspb.LabelKey
+	labelValues []*metricspb.LabelValue
+
+	// siblings by suffix, if a sibling is received more than once during the
+	// flush interval only the last one is kept.
+	siblings map[string]*metricspb.Metric
+}
+
+func newSummaryGrouper(
+	nextConsumer consumer.MetricsConsumer,
+	flushInterval time.Duration,
+	reporter transport.Reporter,
+	logger *zap.Logger,
+) *summaryGrouper {
+	return &summaryGrouper{
+		nextConsumer:  nextConsumer,
+		flushInterval: flushInterval,
+		reporter:      reporter,
+		logger:        logger,
+		groups:        make(map[string]*summaryGroup),
+		summaries:     make(map[string]time.Time),
+		done:          make(chan struct{}),
+	}
+}
+
+// start flushes the groups at every flush interval, until stop is called.
+func (sg *summaryGrouper) start() {
+	sg.wg.Add(1)
+	go func() {
+		defer sg.wg.Done()
+		ticker := time.NewTicker(sg.flushInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ticker.C:
+				sg.flush()
+			case <-sg.done:
+				return
+			}
+		}
+	}()
+}
+
+// stop stops the periodic flushes and flushes the groups held.
+func (sg *summaryGrouper) stop() {
+	close(sg.done)
+	sg.wg.Wait()
+	sg.flush()
+}
+
+// ConsumeMetrics passes the metrics that aren't siblings of a summary to the
+// next consumer, and holds the others until the next flush.
+func (sg *summaryGrouper) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	ocmds := internaldata.MetricsToOC(md)
+	held := false
+	remaining := make([]consumerdata.MetricsData, 0, len(ocmds))
+	for _, ocmd := range ocmds {
+		metrics := make([]*metricspb.Metric, 0, len(ocmd.Metrics))
+		for _, metric := range ocmd.Metrics {
+			if sg.add(metric) {
+				held = true
+				continue
+			}
+			metrics = append(metrics, metric)
+		}
+		if len(metrics) > 0 {
+			ocmd.Metrics = metrics
+			remaining = append(remaining, ocmd)
+		}
+	}
+	if !held {
+		return sg.nextConsumer.ConsumeMetrics(ctx, md)
+	}
+	if len(remaining) == 0 {
+		return nil
+	}
+	return sg.nextConsumer.ConsumeMetrics(ctx, internaldata.OCSliceToMetrics(remaining))
+}
+
+// add holds the metric if it is a single point percentile of a summary, or
+// its count or sum once a percentile was received, and reports if it did so.
+func (sg *summaryGrouper) add(metric *metricspb.Metric) bool {
+	descriptor := metric.GetMetricDescriptor()
+	if len(metric.GetTimeseries()) != 1 || len(metric.Timeseries[0].GetPoints()) != 1 {
+		return false
+	}
+	if _, ok := pointValue(metric.Timeseries[0].Points[0]); !ok {
+		return false
+	}
+	name, suffix := splitSummarySuffix(descriptor.GetName())
+	if suffix == "" {
+		return false
+	}
+
+	labelValues := metric.Timeseries[0].GetLabelValues()
+	key := summaryGroupKey(name, descriptor.GetLabelKeys(), labelValues)
+
+	sg.mu.Lock()
+	defer sg.mu.Unlock()
+	if _, ok := summaryPercentileSuffixes[suffix]; ok {
+		sg.summaries[key] = time.Now()
+	} else if _, ok := sg.summaries[key]; !ok {
+		return false
+	}
+	group, ok := sg.groups[key]
+	if !ok {
+		group = &summaryGroup{
+			name:        name,
+			labelKeys:   descriptor.GetLabelKeys(),
+			labelValues: labelValues,
+			siblings:    make(map[string]*metricspb.Metric),
+		}
+		sg.groups[key] = group
+	}
+	group.siblings[suffix] = metric
+	return true
+}
+
+// flush passes the groups held to the next consumer.
+func (sg *summaryGrouper) flush() {
+	sg.mu.Lock()
+	groups := sg.groups
+	sg.groups = make(map[string]*summaryGroup)
+	expiry := time.Now().Add(-summaryExpiryIntervals * sg.flushInterval)
+	for key, lastSeen := range sg.summaries {
+		if lastSeen.Before(expiry) {
+			delete(sg.summaries, key)
+		}
+	}
+	sg.mu.Unlock()
+
+	if len(groups) == 0 {
+		return
+	}
+
+	// Sort the groups so the metrics are passed in a consistent order.
+	keys := make([]string, 0, len(groups))
+	for key := range groups {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	metrics := make([]*metricspb.Metric, 0, len(groups))
+	for _, key := range keys {
+		metrics = append(metrics, groups[key].metrics()...)
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: metrics,
+	}
+	ctx := sg.reporter.OnDataReceived(context.Background())
+	err := sg.nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	if err != nil {
+		sg.logger.Error(
+			"Carbon receiver failed to push summaries into pipeline",
+			zap.Int("numMetrics", len(metrics)),
+			zap.Error(err))
+	}
+	sg.reporter.OnMetricsProcessed(ctx, len(metrics), 0, err)
+}
+
+// metrics returns the summary built from the siblings of the group, or the
+// siblings themselves if no percentile was received during the interval.
+func (g *summaryGroup) metrics() []*metricspb.Metric {
+	var percentiles []*metricspb.SummaryValue_Snapshot_ValueAtPercentile
+	var latest *metricspb.Point
+	for suffix, metric := range g.siblings {
+		point := metric.Timeseries[0].Points[0]
+		if latest == nil || point.GetTimestamp().AsTime().After(latest.GetTimestamp().AsTime()) {
+			latest = point
+		}
+		if percentile, ok := summaryPercentileSuffixes[suffix]; ok {
+			value, _ := pointValue(point)
+			percentiles = append(percentiles, &metricspb.SummaryValue_Snapshot_ValueAtPercentile{
+				Percentile: percentile,
+				Value:      value,
+			})
+		}
+	}
+
+	if len(percentiles) == 0 {
+		siblings := make([]*metricspb.Metric, 0, len(g.siblings))
+		for _, suffix := range []string{summaryCountSuffix, summarySumSuffix} {
+			if metric, ok := g.siblings[suffix]; ok {
+				siblings = append(siblings, metric)
+			}
+		}
+		return siblings
+	}
+
+	sort.Slice(percentiles, func(i, j int) bool {
+		return percentiles[i].Percentile < percentiles[j].Percentile
+	})
+	summary := &metricspb.SummaryValue{
+		Snapshot: &metricspb.SummaryValue_Snapshot{
+			PercentileValues: percentiles,
+		},
+	}
+	if metric, ok := g.siblings[summaryCountSuffix]; ok {
+		count, _ := pointValue(metric.Timeseries[0].Points[0])
+		summary.Count = &wrapperspb.Int64Value{Value: int64(count)}
+	}
+	if metric, ok := g.siblings[summarySumSuffix]; ok {
+		sum, _ := pointValue(metric.Timeseries[0].Points[0])
+		summary.Sum = &wrapperspb.DoubleValue{Value: sum}
+	}
+
+	return []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      g.name,
+				Type:      metricspb.MetricDescriptor_SUMMARY,
+				LabelKeys: g.labelKeys,
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					LabelValues: g.labelValues,
+					Points: []*metricspb.Point{
+						{
+							Timestamp: latest.GetTimestamp(),
+							Value:     &metricspb.Point_SummaryValue{SummaryValue: summary},
+						},
+					},
+				},
+			},
+		},
+	}
+}
+
+// splitSummarySuffix splits the name of a summary sibling into the name of
+// the summary and the suffix, which is empty if the name isn't the one of a
+// sibling.
+func splitSummarySuffix(name string) (string, string) {
+	idx := strings.LastIndexByte(name, '.')
+	if idx < 1 {
+		return name, ""
+	}
+	suffix := name[idx:]
+	if _, ok := summaryPercentileSuffixes[suffix]; !ok && suffix != summaryCountSuffix && suffix != summarySumSuffix {
+		return name, ""
+	}
+	return name[:idx], suffix
+}
+
+// summaryGroupKey returns the key of the group of a sibling. The labels are
+// sorted since the regex parser doesn't generate them in a consistent order.
+func summaryGroupKey(
+	name string,
+	labelKeys []*metricspb.LabelKey,
+	labelValues []*metricspb.LabelValue,
+) string {
+	labels := make([]string, 0, len(labelKeys))
+	for i, key := range labelKeys {
+		label := key.GetKey() + "\x00"
+		if i < len(labelValues) && labelValues[i].GetHasValue() {
+			label += "\x01" + labelValues[i].GetValue()
+		}
+		labels = append(labels, label)
+	}
+	sort.Strings(labels)
+	return name + "\x00" + strings.Join(labels, "\x00")
+}
+
+func pointValue(point *metricspb.Point) (float64, bool) {
+	switch v := point.GetValue().(type) {
+	case *metricspb.Point_Int64Value:
+		return float64(v.Int64Value), true
+	case *metricspb.Point_DoubleValue:
+		return v.DoubleValue, true
+	}
+	return 0, false
+}
@@ -0,0 +1,216 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package carbonreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+func parseLines(t *testing.T, lines ...string) []*metricspb.Metric {
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	metrics := make([]*metricspb.Metric, 0, len(lines))
+	for _, line := range lines {
+		metric, err := p.Parse(line)
+		require.NoError(t, err)
+		metrics = append(metrics, metric)
+	}
+	return metrics
+}
+
+func Test_summaryGrouper_ConsumeMetrics(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	sg := newSummaryGrouper(sink, time.Minute, transport.NewMockReporter(0), zap.NewNop())
+
+	metrics := parseLines(t,
+		"api.latency.p50 10 1582230020",
+		"api.latency.count 42 1582230020",
+		"api.requests 5 1582230020",
+	)
+	md := consumerdata.MetricsData{Metrics: metrics}
+	require.NoError(t, sg.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(md)))
+
+	// Only the metric that isn't a sibling is passed right away.
+	mdd := sink.AllMetrics()
+	require.Len(t, mdd, 1)
+	ocmd := internaldata.MetricsToOC(mdd[0])
+	require.Len(t, ocmd, 1)
+	require.Len(t, ocmd[0].Metrics, 1)
+	assert.Equal(t, "api.requests", ocmd[0].Metrics[0].GetMetricDescriptor().GetName())
+
+	// Nothing is passed if all metrics are held.
+	md = consumerdata.MetricsData{Metrics: parseLines(t, "api.latency.p99 20 1582230020")}
+	require.NoError(t, sg.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(md)))
+	assert.Len(t, sink.AllMetrics(), 1)
+	assert.Len(t, sg.groups, 1)
+
+	// Counts and sums of metrics never received with a percentile are passed
+	// right away, along with the errors of the next consumer.
+	md = consumerdata.MetricsData{Metrics: parseLines(t, "queue.count 3 1582230020")}
+	require.NoError(t, sg.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(md)))
+	assert.Len(t, sink.AllMetrics(), 2)
+	assert.Len(t, sg.groups, 1)
+
+	sink.SetConsumeMetricsError(errors.New("backpressure"))
+	md = consumerdata.MetricsData{Metrics: parseLines(t, "queue.sum 7.5 1582230020")}
+	assert.Error(t, sg.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(md)))
+}
+
+func Test_summaryGrouper_flush(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	sg := newSummaryGrouper(sink, time.Minute, transport.NewMockReporter(2), zap.NewNop())
+
+	// Counts and sums of a summary received without any percentile during the
+	// interval are passed unchanged at the flush.
+	require.True(t, sg.add(parseLines(t, "queue.p50 1 1582230010")[0]))
+	sg.flush()
+	sink.Reset()
+	for _, metric := range parseLines(t,
+		"queue.count 3 1582230020",
+		"queue.sum 7.5 1582230020",
+	) {
+		require.True(t, sg.add(metric))
+	}
+
+	sg.stop()
+	assert.Empty(t, sg.groups)
+	mdd := sink.AllMetrics()
+	require.Len(t, mdd, 1)
+	ocmd := internaldata.MetricsToOC(mdd[0])
+	require.Len(t, ocmd, 1)
+	require.Len(t, ocmd[0].Metrics, 2)
+	assert.Equal(t, "queue.count", ocmd[0].Metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, "queue.sum", ocmd[0].Metrics[1].GetMetricDescriptor().GetName())
+}
+
+func Test_summaryGroup_metrics(t *testing.T) {
+	sg := newSummaryGrouper(exportertest.NewNopMetricsExporter(), time.Minute, transport.NewMockReporter(0), zap.NewNop())
+	for _, metric := range parseLines(t,
+		"api.latency.p99;host=h0 30 1582230030",
+		"api.latency.p50;host=h0 10 1582230020",
+		"api.latency.p90;host=h0 25.5 1582230020",
+		"api.latency.p90;host=h0 20 1582230020",
+		"api.latency.count;host=h0 42 1582230020",
+		"api.latency.sum;host=h0 840.5 1582230020",
+		"api.latency.p50;host=h1 11 1582230020",
+	) {
+		require.True(t, sg.add(metric))
+	}
+	require.Len(t, sg.groups, 2)
+
+	group := sg.groups[summaryGroupKey(
+		"api.latency",
+		[]*metricspb.LabelKey{{Key: "host"}},
+		[]*metricspb.LabelValue{{Value: "h0", HasValue: true}})]
+	require.NotNil(t, group)
+	want := []*metricspb.Metric{
+		{
+			MetricDescriptor: &metricspb.MetricDescriptor{
+				Name:      "api.latency",
+				Type:      metricspb.MetricDescriptor_SUMMARY,
+				LabelKeys: []*metricspb.LabelKey{{Key: "host"}},
+			},
+			Timeseries: []*metricspb.TimeSeries{
+				{
+					LabelValues: []*metricspb.LabelValue{{Value: "h0", HasValue: true}},
+					Points: []*metricspb.Point{
+						{
+							Timestamp: &timestamppb.Timestamp{Seconds: 1582230030},
+							Value: &metricspb.Point_SummaryValue{
+								SummaryValue: &metricspb.SummaryValue{
+									Count: &wrapperspb.Int64Value{Value: 42},
+									Sum:   &wrapperspb.DoubleValue{Value: 840.5},
+									Snapshot: &metricspb.SummaryValue_Snapshot{
+										PercentileValues: []*metricspb.SummaryValue_Snapshot_ValueAtPercentile{
+											{Percentile: 50, Value: 10},
+											{Percentile: 90, Value: 20},
+											{Percentile: 99, Value: 30},
+										},
+									},
+								},
+							},
+						},
+					},
+				},
+			},
+		},
+	}
+	assert.Equal(t, want, group.metrics())
+}
+
+func Test_splitSummarySuffix(t *testing.T) {
+	tests := []struct {
+		name       string
+		wantName   string
+		wantSuffix string
+	}{
+		{name: "api.latency.p50", wantName: "api.latency", wantSuffix: ".p50"},
+		{name: "api.latency.count", wantName: "api.latency", wantSuffix: ".count"},
+		{name: "api.latency.sum", wantName: "api.latency", wantSuffix: ".sum"},
+		{name: "api.latency.p75", wantName: "api.latency.p75"},
+		{name: "api.latency", wantName: "api.latency"},
+		{name: ".count", wantName: ".count"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			name, suffix := splitSummarySuffix(tt.name)
+			assert.Equal(t, tt.wantName, name)
+			assert.Equal(t, tt.wantSuffix, suffix)
+		})
+	}
+}
+
+func Test_summaryGrouper_flushError(t *testing.T) {
+	sink := new(exportertest.SinkMetricsExporter)
+	sink.SetConsumeMetricsError(errors.New("backpressure"))
+	reporter := transport.NewMockReporter(1)
+	sg := newSummaryGrouper(sink, time.Minute, reporter, zap.NewNop())
+
+	require.True(t, sg.add(parseLines(t, "api.latency.p50 10 1582230020")[0]))
+	sg.flush()
+	// The failure is reported like the ones of the metrics passed right away.
+	reporter.WaitAllOnMetricsProcessedCalls()
+}
+
+func Test_summaryGrouper_expiry(t *testing.T) {
+	sg := newSummaryGrouper(exportertest.NewNopMetricsExporter(), time.Minute, transport.NewMockReporter(2), zap.NewNop())
+
+	require.True(t, sg.add(parseLines(t, "api.latency.p50 10 1582230020")[0]))
+	sg.flush()
+	require.True(t, sg.add(parseLines(t, "api.latency.count 42 1582230030")[0]))
+
+	// Summaries are forgotten when no percentile is received for a while.
+	key := summaryGroupKey("api.latency", nil, nil)
+	sg.summaries[key] = time.Now().Add(-summaryExpiryIntervals * time.Minute).Add(-time.Second)
+	sg.flush()
+	assert.Empty(t, sg.summaries)
+	assert.False(t, sg.add(parseLines(t, "api.latency.count 42 1582230040")[0]))
+}
@@ -0,0 +1,144 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+const (
+	// pickleMaxMessageSize is the maximum size of a pickle message, the same
+	// limit as Carbon.
+	pickleMaxMessageSize = 1 << 20
+)
+
+// NewPickleServer creates a transport.Server receiving Carbon's pickle
+// protocol over TCP, see
+// https://graphite.readthedocs.io/en/latest/feeding-carbon.html#the-pickle-protocol.
+// Each message is a 4 bytes big endian length followed by the pickled list of
+// datapoints, which are converted to plaintext lines handled by the Parser.
+func NewPickleServer(
+	addr string,
+	idleTimeout time.Duration,
+) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	t.handleConn = t.handlePickleConnection
+	return t, nil
+}
+
+func (t *tcpServer) handlePickleConnection(
+	p protocol.Parser,
+	nextConsumer consumer.MetricsConsumer,
+	conn net.Conn,
+) {
+	defer conn.Close()
+	reader := bufio.NewReader(conn)
+	header := make([]byte, 4)
+	for {
+		if err := conn.SetDeadline(time.Now().Add(t.idleTimeout)); err != nil {
+			t.reporter.OnDebugf(
+				"Pickle Transport (%s) - conn.SetDeadLine error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		// Idle connections are purged by the deadline, and connections with
+		// an invalid length are closed since the following data can't be
+		// framed.
+		if _, err := io.ReadFull(reader, header); err != nil {
+			t.reporter.OnDebugf(
+				"Pickle Transport (%s) - error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+		size := binary.BigEndian.Uint32(header)
+		if size > pickleMaxMessageSize {
+			t.reporter.OnDebugf(
+				"Pickle Transport (%s) - message of %d bytes exceeds the maximum size",
+				t.ln.Addr(),
+				size)
+			return
+		}
+		message := make([]byte, size)
+		if _, err := io.ReadFull(reader, message); err != nil {
+			t.reporter.OnDebugf(
+				"Pickle Transport (%s) - error: %v",
+				t.ln.Addr(),
+				err)
+			return
+		}
+
+		if err := t.handlePickleMessage(p, nextConsumer, message); err != nil {
+			// As with plaintext, close the connection to report the error of
+			// the next consumer to the client.
+			return
+		}
+	}
+}
+
+func (t *tcpServer) handlePickleMessage(
+	p protocol.Parser,
+	nextConsumer consumer.MetricsConsumer,
+	message []byte,
+) error {
+	ctx := t.reporter.OnDataReceived(context.Background())
+	lines, invalid, err := protocol.ParsePickle(message)
+	if err != nil {
+		t.reporter.OnTranslationError(ctx, err)
+		t.reporter.OnMetricsProcessed(ctx, 0, 0, nil)
+		return nil
+	}
+
+	numReceivedTimeSeries := len(lines) + len(invalid)
+	numInvalidTimeSeries := len(invalid)
+	for _, err := range invalid {
+		t.reporter.OnTranslationError(ctx, err)
+	}
+
+	metrics := make([]*metricspb.Metric, 0, len(lines))
+	for _, line := range lines {
+		metric, err := p.Parse(line)
+		if err != nil {
+			numInvalidTimeSeries++
+			t.reporter.OnTranslationError(ctx, err)
+			continue
+		}
+		metrics = append(metrics, metric)
+	}
+
+	md := consumerdata.MetricsData{
+		Metrics: metrics,
+	}
+	err = nextConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	t.reporter.OnMetricsProcessed(ctx, numReceivedTimeSeries, numInvalidTimeSeries, err)
+	return err
+}
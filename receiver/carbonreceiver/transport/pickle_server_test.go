@@ -0,0 +1,120 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"encoding/binary"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/internaldata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+// pickledDatapoints is the pickle protocol 2 of:
+//
+// 	[("test.metric", (1582230020, 1.5)),
+// 	 ("test.tagged;k0=v0", (1582230020.7, 2)),
+// 	 ("test.big", (1582230020, 2**70))]
+const pickledDatapoints = "\x80\x02]q\x00(X\x0b\x00\x00\x00test.metricq\x01J\x04\xeaN^G?\xf8\x00\x00\x00\x00\x00\x00\x86q\x02\x86q\x03X\x11\x00\x00\x00test.tagged;k0=v0q\x04GA\xd7\x93\xba\x81,\xcc\xcdK\x02\x86q\x05\x86q\x06X\x08\x00\x00\x00test.bigq\x07J\x04\xeaN^\x8a\x09\x00\x00\x00\x00\x00\x00\x00\x00@\x86q\x08\x86q\x09e."
+
+func Test_PickleServer_ListenAndServe(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second)
+	require.NoError(t, err)
+	require.NotNil(t, svr)
+
+	mc := new(exportertest.SinkMetricsExporter)
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	// One call for the invalid message and one for the datapoints.
+	mr := NewMockReporter(2)
+
+	wgListenAndServe := sync.WaitGroup{}
+	wgListenAndServe.Add(1)
+	go func() {
+		defer wgListenAndServe.Done()
+		assert.Error(t, svr.ListenAndServe(p, mc, mr))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	for _, message := range []string{"not a pickle", pickledDatapoints} {
+		header := make([]byte, 4)
+		binary.BigEndian.PutUint32(header, uint32(len(message)))
+		_, err = conn.Write(append(header, message...))
+		require.NoError(t, err)
+	}
+	require.NoError(t, conn.Close())
+
+	mr.WaitAllOnMetricsProcessedCalls()
+
+	err = svr.Close()
+	assert.NoError(t, err)
+
+	wgListenAndServe.Wait()
+
+	mdd := mc.AllMetrics()
+	require.Len(t, mdd, 1)
+	ocmd := internaldata.MetricsToOC(mdd[0])
+	require.Len(t, ocmd, 1)
+	require.Len(t, ocmd[0].Metrics, 3)
+	assert.Equal(t, "test.metric", ocmd[0].Metrics[0].GetMetricDescriptor().GetName())
+	assert.Equal(t, 1.5, ocmd[0].Metrics[0].Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, "test.tagged", ocmd[0].Metrics[1].GetMetricDescriptor().GetName())
+	assert.Equal(t, "k0", ocmd[0].Metrics[1].GetMetricDescriptor().LabelKeys[0].Key)
+}
+
+func Test_PickleServer_MessageTooLarge(t *testing.T) {
+	addr := testutil.GetAvailableLocalAddress(t)
+	svr, err := NewPickleServer(addr, 1*time.Second)
+	require.NoError(t, err)
+
+	mc := new(exportertest.SinkMetricsExporter)
+	p, err := (&protocol.PlaintextConfig{}).BuildParser()
+	require.NoError(t, err)
+	go func() {
+		_ = svr.ListenAndServe(p, mc, NewMockReporter(0))
+	}()
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	defer conn.Close()
+	header := make([]byte, 4)
+	binary.BigEndian.PutUint32(header, pickleMaxMessageSize+1)
+	_, err = conn.Write(header)
+	require.NoError(t, err)
+
+	// The server closes the connection since the message can't be framed.
+	require.NoError(t, conn.SetReadDeadline(time.Now().Add(5*time.Second)))
+	_, err = conn.Read(make([]byte, 1))
+	assert.Error(t, err)
+	assert.False(t, isTimeout(err))
+
+	assert.NoError(t, svr.Close())
+	assert.Empty(t, mc.AllMetrics())
+}
+
+func isTimeout(err error) bool {
+	netErr, ok := err.(net.Error)
+	return ok && netErr.Timeout()
+}
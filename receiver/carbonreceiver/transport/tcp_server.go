@@ -43,6 +43,9 @@ type tcpServer struct {
 	wg          sync.WaitGroup
 	idleTimeout time.Duration
 	reporter    Reporter
+	// handleConn reads the data of an accepted connection, it is either
+	// handleConnection for lines or handlePickleConnection for pickles.
+	handleConn func(protocol.Parser, consumer.MetricsConsumer, net.Conn)
 }
 
 var _ (Server) = (*tcpServer)(nil)
@@ -52,6 +55,18 @@ func NewTCPServer(
 	addr string,
 	idleTimeout time.Duration,
 ) (Server, error) {
+	t, err := newTCPServer(addr, idleTimeout)
+	if err != nil {
+		return nil, err
+	}
+	t.handleConn = t.handleConnection
+	return t, nil
+}
+
+func newTCPServer(
+	addr string,
+	idleTimeout time.Duration,
+) (*tcpServer, error) {
 	if idleTimeout < 0 {
 		return nil, fmt.Errorf("invalid idle timeout: %v", idleTimeout)
 	}
@@ -94,7 +109,7 @@ func (t *tcpServer) ListenAndServe(
 			connMapMtx.Unlock()
 			t.wg.Add(1)
 			go func(c net.Conn) {
-				t.handleConnection(parser, nextConsumer, c)
+				t.handleConn(parser, nextConsumer, c)
 				connMapMtx.Lock()
 				delete(acceptedConnMap, c)
 				connMapMtx.Unlock()
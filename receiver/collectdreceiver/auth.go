@@ -0,0 +1,57 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// loadAuthFile loads the passwords of the users allowed to sign and encrypt
+// packets from a file in the format of the AuthFile option of collectd's
+// network plugin, i.e. lines like "user: password".
+func loadAuthFile(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	passwords, err := parseAuthFile(f)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return passwords, nil
+}
+
+func parseAuthFile(r io.Reader) (map[string]string, error) {
+	passwords := map[string]string{}
+	scanner := bufio.NewScanner(r)
+	for lineNum := 1; scanner.Scan(); lineNum++ {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		pair := strings.SplitN(line, ":", 2)
+		if len(pair) != 2 || strings.TrimSpace(pair[0]) == "" {
+			// The line isn't quoted, it may contain a password.
+			return nil, fmt.Errorf("invalid line %d", lineNum)
+		}
+		passwords[strings.TrimSpace(pair[0])] = strings.TrimSpace(pair[1])
+	}
+	return passwords, scanner.Err()
+}
@@ -0,0 +1,370 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec collectd uses SHA-1 to checksum encrypted parts
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+)
+
+// Part types of the collectd binary network protocol, see
+// https://collectd.org/wiki/index.php/Binary_protocol.
+const (
+	partHost           = 0x0000
+	partTime           = 0x0001
+	partPlugin         = 0x0002
+	partPluginInstance = 0x0003
+	partType           = 0x0004
+	partTypeInstance   = 0x0005
+	partValues         = 0x0006
+	partInterval       = 0x0007
+	partTimeHR         = 0x0008
+	partIntervalHR     = 0x0009
+	partMessage        = 0x0100
+	partSeverity       = 0x0101
+	partSignature      = 0x0200
+	partEncryption     = 0x0210
+)
+
+// Data source types of the values part.
+const (
+	dsTypeCounter  = 0
+	dsTypeGauge    = 1
+	dsTypeDerive   = 2
+	dsTypeAbsolute = 3
+)
+
+const (
+	partHeaderLen = 4
+	signatureLen  = sha256.Size
+	checksumLen   = sha1.Size
+	ivLen         = aes.BlockSize
+)
+
+// Security levels of the binary protocol, with the same meaning as the
+// SecurityLevel option of collectd's network plugin.
+const (
+	securityLevelNone    = "none"
+	securityLevelSign    = "sign"
+	securityLevelEncrypt = "encrypt"
+)
+
+type securityLevel int
+
+const (
+	levelNone securityLevel = iota
+	levelSign
+	levelEncrypt
+)
+
+var securityLevels = map[string]securityLevel{
+	"":                   levelNone,
+	securityLevelNone:    levelNone,
+	securityLevelSign:    levelSign,
+	securityLevelEncrypt: levelEncrypt,
+}
+
+var (
+	errInsecureData     = errors.New("data is not signed or encrypted as required by the security level")
+	errInvalidSignature = errors.New("invalid signature")
+	errInvalidChecksum  = errors.New("invalid checksum of decrypted data")
+)
+
+// binaryDecoder decodes the packets of collectd's network plugin into the
+// same records as the JSON of the write_http plugin.
+type binaryDecoder struct {
+	securityLevel securityLevel
+	// The passwords of the users allowed to sign and encrypt packets, may be
+	// nil when the security level is none.
+	passwords map[string]string
+	typesDB   typesDB
+}
+
+// The state of a value list, which parts other than values and messages
+// update for the following parts of the packet.
+type binaryState struct {
+	host           string
+	plugin         string
+	pluginInstance string
+	typeS          string
+	typeInstance   string
+	time           float64
+	interval       float64
+	severity       string
+}
+
+func (d *binaryDecoder) decode(packet []byte) ([]collectDRecord, error) {
+	return d.parse(packet, levelNone, nil)
+}
+
+// parse decodes the parts of buf, which have been authenticated up to the
+// given level, appending the value lists and notifications to records.
+func (d *binaryDecoder) parse(buf []byte, level securityLevel, records []collectDRecord) ([]collectDRecord, error) {
+	var state binaryState
+	for len(buf) > 0 {
+		if len(buf) < partHeaderLen {
+			return records, fmt.Errorf("truncated part header of %d bytes", len(buf))
+		}
+		partID := binary.BigEndian.Uint16(buf)
+		partLen := int(binary.BigEndian.Uint16(buf[2:]))
+		if partLen < partHeaderLen || partLen > len(buf) {
+			return records, fmt.Errorf("invalid length %d of part 0x%04x", partLen, partID)
+		}
+		payload := buf[partHeaderLen:partLen]
+		rest := buf[partLen:]
+
+		var err error
+		switch partID {
+		case partSignature:
+			// The signature covers all the following parts.
+			return d.parseSigned(payload, rest, level, records)
+		case partEncryption:
+			records, err = d.parseEncrypted(payload, records)
+		case partValues:
+			if level < d.securityLevel {
+				return records, errInsecureData
+			}
+			var record collectDRecord
+			if record, err = d.valuesRecord(&state, payload); err == nil {
+				records = append(records, record)
+			}
+		case partMessage:
+			if level < d.securityLevel {
+				return records, errInsecureData
+			}
+			records = append(records, state.eventRecord(parseString(payload)))
+		default:
+			err = state.update(partID, payload)
+		}
+		if err != nil {
+			return records, err
+		}
+		buf = rest
+	}
+	return records, nil
+}
+
+// parseSigned verifies the signature part and parses the signed parts.
+// Packets signed by unknown users are still parsed as unsigned data, as
+// collectd does.
+func (d *binaryDecoder) parseSigned(payload, signed []byte, level securityLevel, records []collectDRecord) ([]collectDRecord, error) {
+	if len(payload) < signatureLen {
+		return records, fmt.Errorf("truncated signature part of %d bytes", len(payload))
+	}
+	signature, username := payload[:signatureLen], payload[signatureLen:]
+	password, ok := d.passwords[string(username)]
+	if !ok {
+		if d.securityLevel > levelNone {
+			return records, fmt.Errorf("unknown user %q", username)
+		}
+		return d.parse(signed, level, records)
+	}
+
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write(username)
+	mac.Write(signed)
+	if !hmac.Equal(signature, mac.Sum(nil)) {
+		return records, errInvalidSignature
+	}
+	if level < levelSign {
+		level = levelSign
+	}
+	return d.parse(signed, level, records)
+}
+
+// parseEncrypted decrypts the encryption part and parses the decrypted
+// parts.
+func (d *binaryDecoder) parseEncrypted(payload []byte, records []collectDRecord) ([]collectDRecord, error) {
+	if len(payload) < 2 {
+		return records, fmt.Errorf("truncated encryption part of %d bytes", len(payload))
+	}
+	usernameLen := int(binary.BigEndian.Uint16(payload))
+	payload = payload[2:]
+	if len(payload) < usernameLen+ivLen+checksumLen {
+		return records, fmt.Errorf("truncated encryption part of %d bytes", len(payload)+2)
+	}
+	username := string(payload[:usernameLen])
+	iv := payload[usernameLen : usernameLen+ivLen]
+	encrypted := payload[usernameLen+ivLen:]
+
+	password, ok := d.passwords[username]
+	if !ok {
+		return records, fmt.Errorf("unknown user %q", username)
+	}
+	key := sha256.Sum256([]byte(password))
+	block, err := aes.NewCipher(key[:])
+	if err != nil {
+		return records, err
+	}
+	decrypted := make([]byte, len(encrypted))
+	cipher.NewOFB(block, iv).XORKeyStream(decrypted, encrypted)
+
+	checksum, data := decrypted[:checksumLen], decrypted[checksumLen:]
+	// #nosec collectd uses SHA-1 to checksum encrypted parts
+	expected := sha1.Sum(data)
+	if !bytes.Equal(checksum, expected[:]) {
+		return records, errInvalidChecksum
+	}
+	return d.parse(data, levelEncrypt, records)
+}
+
+func (s *binaryState) update(partID uint16, payload []byte) error {
+	switch partID {
+	case partHost:
+		s.host = parseString(payload)
+	case partPlugin:
+		s.plugin = parseString(payload)
+	case partPluginInstance:
+		s.pluginInstance = parseString(payload)
+	case partType:
+		s.typeS = parseString(payload)
+	case partTypeInstance:
+		s.typeInstance = parseString(payload)
+	case partTime, partInterval, partTimeHR, partIntervalHR, partSeverity:
+		if len(payload) != 8 {
+			return fmt.Errorf("invalid length %d of numeric part 0x%04x", len(payload), partID)
+		}
+		n := binary.BigEndian.Uint64(payload)
+		switch partID {
+		case partTime:
+			s.time = float64(n)
+		case partInterval:
+			s.interval = float64(n)
+		case partTimeHR:
+			s.time = hrTimeToSeconds(n)
+		case partIntervalHR:
+			s.interval = hrTimeToSeconds(n)
+		case partSeverity:
+			s.severity = severityName(n)
+		}
+	}
+	// Unknown parts are skipped, as collectd does.
+	return nil
+}
+
+// valuesRecord builds the record of the value list of a values part.
+func (d *binaryDecoder) valuesRecord(s *binaryState, payload []byte) (collectDRecord, error) {
+	if len(payload) < 2 {
+		return collectDRecord{}, fmt.Errorf("truncated values part of %d bytes", len(payload))
+	}
+	count := int(binary.BigEndian.Uint16(payload))
+	payload = payload[2:]
+	if len(payload) != count*9 {
+		return collectDRecord{}, fmt.Errorf("invalid length %d of values part with %d values", len(payload), count)
+	}
+	types, values := payload[:count], payload[count:]
+
+	dsnames := d.typesDB.dsnames(s.typeS, count)
+	record := s.record()
+	record.Dsnames = make([]*string, count)
+	record.Dstypes = make([]*string, count)
+	record.Values = make([]*json.Number, count)
+	for i := 0; i < count; i++ {
+		dsname := dsnames[i]
+		record.Dsnames[i] = &dsname
+		dstype, value, err := parseValue(types[i], values[i*8:(i+1)*8])
+		if err != nil {
+			return collectDRecord{}, err
+		}
+		record.Dstypes[i] = &dstype
+		record.Values[i] = value
+	}
+	return record, nil
+}
+
+// parseValue returns the data source type and the value of a value of a
+// values part. NaN gauges have a nil value, as in the JSON of write_http.
+func parseValue(dsType byte, b []byte) (string, *json.Number, error) {
+	var value json.Number
+	switch dsType {
+	case dsTypeCounter:
+		value = json.Number(strconv.FormatUint(binary.BigEndian.Uint64(b), 10))
+		return collectDMetricCounter, &value, nil
+	case dsTypeGauge:
+		// Gauges are the only little endian values.
+		f := math.Float64frombits(binary.LittleEndian.Uint64(b))
+		if math.IsNaN(f) || math.IsInf(f, 0) {
+			return collectDMetricGauge, nil, nil
+		}
+		value = json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+		return collectDMetricGauge, &value, nil
+	case dsTypeDerive:
+		value = json.Number(strconv.FormatInt(int64(binary.BigEndian.Uint64(b)), 10))
+		return collectDMetricDerive, &value, nil
+	case dsTypeAbsolute:
+		value = json.Number(strconv.FormatUint(binary.BigEndian.Uint64(b), 10))
+		return collectDMetricAbsolute, &value, nil
+	}
+	return "", nil, fmt.Errorf("unknown data source type %d", dsType)
+}
+
+// record returns a record with the fields of the state shared by value lists
+// and notifications.
+func (s *binaryState) record() collectDRecord {
+	state := *s
+	return collectDRecord{
+		Host:           &state.host,
+		Plugin:         &state.plugin,
+		PluginInstance: &state.pluginInstance,
+		TypeS:          &state.typeS,
+		TypeInstance:   &state.typeInstance,
+		Time:           &state.time,
+		Interval:       &state.interval,
+	}
+}
+
+func (s *binaryState) eventRecord(message string) collectDRecord {
+	record := s.record()
+	severity := s.severity
+	record.Severity = &severity
+	record.Message = &message
+	return record
+}
+
+// parseString returns the value of a string part, which is null terminated.
+func parseString(payload []byte) string {
+	if i := bytes.IndexByte(payload, 0); i >= 0 {
+		payload = payload[:i]
+	}
+	return string(payload)
+}
+
+// hrTimeToSeconds converts a high resolution time, in 2^-30 seconds, to
+// seconds.
+func hrTimeToSeconds(n uint64) float64 {
+	return float64(n) / (1 << 30)
+}
+
+func severityName(n uint64) string {
+	switch n {
+	case 1:
+		return "FAILURE"
+	case 2:
+		return "WARNING"
+	case 4:
+		return "OKAY"
+	}
+	return "UNKNOWN"
+}
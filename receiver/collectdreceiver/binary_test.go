@@ -0,0 +1,308 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bytes"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/hmac"
+	"crypto/sha1" // #nosec collectd uses SHA-1 to checksum encrypted parts
+	"crypto/sha256"
+	"encoding/binary"
+	"math"
+	"strings"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// Helpers to build packets of the binary protocol.
+
+func stringPart(partID uint16, s string) []byte {
+	return part(partID, append([]byte(s), 0))
+}
+
+func numericPart(partID uint16, n uint64) []byte {
+	b := make([]byte, 8)
+	binary.BigEndian.PutUint64(b, n)
+	return part(partID, b)
+}
+
+type testValue struct {
+	dsType byte
+	value  float64
+}
+
+func valuesPart(values ...testValue) []byte {
+	b := make([]byte, 2+9*len(values))
+	binary.BigEndian.PutUint16(b, uint16(len(values)))
+	for i, v := range values {
+		b[2+i] = v.dsType
+		data := b[2+len(values)+8*i:]
+		switch v.dsType {
+		case dsTypeGauge:
+			binary.LittleEndian.PutUint64(data, math.Float64bits(v.value))
+		case dsTypeDerive:
+			binary.BigEndian.PutUint64(data, uint64(int64(v.value)))
+		default:
+			binary.BigEndian.PutUint64(data, uint64(v.value))
+		}
+	}
+	return part(partValues, b)
+}
+
+func part(partID uint16, payload []byte) []byte {
+	b := make([]byte, 4, 4+len(payload))
+	binary.BigEndian.PutUint16(b, partID)
+	binary.BigEndian.PutUint16(b[2:], uint16(4+len(payload)))
+	return append(b, payload...)
+}
+
+func sign(data []byte, username, password string) []byte {
+	mac := hmac.New(sha256.New, []byte(password))
+	mac.Write([]byte(username))
+	mac.Write(data)
+	payload := append(mac.Sum(nil), username...)
+	return append(part(partSignature, payload), data...)
+}
+
+func encrypt(data []byte, username, password string) []byte {
+	key := sha256.Sum256([]byte(password))
+	block, _ := aes.NewCipher(key[:])
+	iv := bytes.Repeat([]byte{7}, aes.BlockSize)
+	// #nosec collectd uses SHA-1 to checksum encrypted parts
+	checksum := sha1.Sum(data)
+	plain := append(checksum[:], data...)
+	encrypted := make([]byte, len(plain))
+	cipher.NewOFB(block, iv).XORKeyStream(encrypted, plain)
+
+	payload := make([]byte, 2, 2+len(username)+len(iv)+len(encrypted))
+	binary.BigEndian.PutUint16(payload, uint16(len(username)))
+	payload = append(payload, username...)
+	payload = append(payload, iv...)
+	return part(partEncryption, append(payload, encrypted...))
+}
+
+func testPacket() []byte {
+	var packet []byte
+	for _, p := range [][]byte{
+		stringPart(partHost, "i-b13d1e5f"),
+		numericPart(partTime, 1415062577),
+		numericPart(partInterval, 10),
+		stringPart(partPlugin, "memory"),
+		stringPart(partType, "memory"),
+		stringPart(partTypeInstance, "free"),
+		valuesPart(testValue{dsTypeGauge, 2.1474}),
+		stringPart(partPlugin, "interface"),
+		stringPart(partPluginInstance, "eth0"),
+		stringPart(partType, "if_octets"),
+		stringPart(partTypeInstance, ""),
+		valuesPart(testValue{dsTypeDerive, 100}, testValue{dsTypeDerive, 200}),
+	} {
+		packet = append(packet, p...)
+	}
+	return packet
+}
+
+func testDecoder() *binaryDecoder {
+	return &binaryDecoder{
+		passwords: map[string]string{"otel": "secret"},
+		typesDB:   typesDB{"if_octets": {"rx", "tx"}},
+	}
+}
+
+func decodeMetrics(t *testing.T, d *binaryDecoder, packet []byte) []*metricspb.Metric {
+	records, err := d.decode(packet)
+	require.NoError(t, err)
+	var metrics []*metricspb.Metric
+	for _, record := range records {
+		metrics, err = record.appendToMetrics(metrics, nil)
+		require.NoError(t, err)
+	}
+	return metrics
+}
+
+func TestDecodeBinary(t *testing.T) {
+	metrics := decodeMetrics(t, testDecoder(), testPacket())
+	require.Len(t, metrics, 3)
+
+	// The same metric as the JSON of write_http for a gauge.
+	assertMetricsAreEqual(t, metrics[:1], []*metricspb.Metric{{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name: "memory.free",
+			Type: metricspb.MetricDescriptor_GAUGE_DOUBLE,
+			LabelKeys: []*metricspb.LabelKey{
+				{Key: "plugin"},
+				{Key: "host"},
+				{Key: "dsname"},
+			},
+		},
+		Timeseries: []*metricspb.TimeSeries{{
+			LabelValues: []*metricspb.LabelValue{
+				{Value: "memory", HasValue: true},
+				{Value: "i-b13d1e5f", HasValue: true},
+				{Value: "value", HasValue: true},
+			},
+			Points: []*metricspb.Point{{
+				Timestamp: metrics[0].Timeseries[0].Points[0].Timestamp,
+				Value:     &metricspb.Point_DoubleValue{DoubleValue: 2.1474},
+			}},
+		}},
+	}})
+	assert.Equal(t, int64(1415062577), metrics[0].Timeseries[0].Points[0].Timestamp.Seconds)
+
+	// Multi-value types are named after the data sources of types.db.
+	assert.Equal(t, "if_octets.rx", metrics[1].MetricDescriptor.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metrics[1].MetricDescriptor.Type)
+	assert.Equal(t, int64(100), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, "if_octets.tx", metrics[2].MetricDescriptor.Name)
+	assert.Equal(t, int64(200), metrics[2].Timeseries[0].Points[0].GetInt64Value())
+}
+
+func TestDecodeBinaryValueTypes(t *testing.T) {
+	d := &binaryDecoder{}
+	packet := append(stringPart(partType, "test"), valuesPart(
+		testValue{dsTypeCounter, 1},
+		testValue{dsTypeGauge, 2.5},
+		testValue{dsTypeDerive, -3},
+		testValue{dsTypeAbsolute, 4},
+		testValue{dsTypeGauge, math.NaN()},
+	)...)
+	metrics := decodeMetrics(t, d, packet)
+	// The NaN gauge is skipped, as a null value in JSON.
+	require.Len(t, metrics, 4)
+
+	assert.Equal(t, "test.0", metrics[0].MetricDescriptor.Name)
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metrics[0].MetricDescriptor.Type)
+	assert.Equal(t, int64(1), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_DOUBLE, metrics[1].MetricDescriptor.Type)
+	assert.Equal(t, 2.5, metrics[1].Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metrics[2].MetricDescriptor.Type)
+	assert.Equal(t, int64(-3), metrics[2].Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, metricspb.MetricDescriptor_GAUGE_INT64, metrics[3].MetricDescriptor.Type)
+	assert.Equal(t, int64(4), metrics[3].Timeseries[0].Points[0].GetInt64Value())
+}
+
+func TestDecodeBinaryNotification(t *testing.T) {
+	d := &binaryDecoder{}
+	packet := bytes.Join([][]byte{
+		stringPart(partHost, "host"),
+		numericPart(partTime, 1415062577),
+		numericPart(partSeverity, 2),
+		stringPart(partMessage, "disk is almost full"),
+	}, nil)
+	records, err := d.decode(packet)
+	require.NoError(t, err)
+	require.Len(t, records, 1)
+	assert.True(t, records[0].isEvent())
+	assert.Equal(t, "WARNING", *records[0].Severity)
+	assert.Equal(t, "disk is almost full", *records[0].Message)
+}
+
+func TestDecodeBinaryInvalid(t *testing.T) {
+	d := &binaryDecoder{}
+	tests := []struct {
+		name   string
+		packet []byte
+	}{
+		{"truncated header", []byte{0, 1}},
+		{"invalid length", []byte{0, 1, 0, 2}},
+		{"length past end", []byte{0, 1, 0, 12, 0, 0}},
+		{"invalid time", part(partTime, []byte{1, 2})},
+		{"invalid values", part(partValues, []byte{0, 2, 1})},
+		{"unknown data source type", valuesPart(testValue{dsType: 9})},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := d.decode(tt.packet)
+			assert.Error(t, err)
+		})
+	}
+}
+
+func TestDecodeBinarySigned(t *testing.T) {
+	d := testDecoder()
+	d.securityLevel = levelSign
+
+	_, err := d.decode(testPacket())
+	assert.Equal(t, errInsecureData, err)
+
+	metrics := decodeMetrics(t, d, sign(testPacket(), "otel", "secret"))
+	assert.Len(t, metrics, 3)
+
+	_, err = d.decode(sign(testPacket(), "otel", "wrong"))
+	assert.Equal(t, errInvalidSignature, err)
+	_, err = d.decode(sign(testPacket(), "unknown", "secret"))
+	assert.Error(t, err)
+
+	// Encrypted packets satisfy the sign security level.
+	metrics = decodeMetrics(t, d, encrypt(testPacket(), "otel", "secret"))
+	assert.Len(t, metrics, 3)
+}
+
+func TestDecodeBinarySignedUnknownUserWithoutSecurity(t *testing.T) {
+	d := testDecoder()
+	metrics := decodeMetrics(t, d, sign(testPacket(), "unknown", "secret"))
+	assert.Len(t, metrics, 3)
+}
+
+func TestDecodeBinaryEncrypted(t *testing.T) {
+	d := testDecoder()
+	d.securityLevel = levelEncrypt
+
+	_, err := d.decode(sign(testPacket(), "otel", "secret"))
+	assert.Equal(t, errInsecureData, err)
+
+	metrics := decodeMetrics(t, d, encrypt(testPacket(), "otel", "secret"))
+	assert.Len(t, metrics, 3)
+
+	_, err = d.decode(encrypt(testPacket(), "otel", "wrong"))
+	assert.Equal(t, errInvalidChecksum, err)
+	_, err = d.decode(encrypt(testPacket(), "unknown", "secret"))
+	assert.Error(t, err)
+}
+
+func TestParseTypesDB(t *testing.T) {
+	db := typesDB{}
+	require.NoError(t, db.parse(strings.NewReader(`
+# comment
+load      shortterm:GAUGE:0:5000, midterm:GAUGE:0:5000, longterm:GAUGE:0:5000
+if_octets rx:DERIVE:0:U,tx:DERIVE:0:U
+memory    value:GAUGE:0:281474976710656
+`)))
+	assert.Equal(t, typesDB{
+		"load":      {"shortterm", "midterm", "longterm"},
+		"if_octets": {"rx", "tx"},
+		"memory":    {"value"},
+	}, db)
+
+	assert.Equal(t, []string{"rx", "tx"}, db.dsnames("if_octets", 2))
+	assert.Equal(t, []string{"value"}, db.dsnames("unknown", 1))
+	assert.Equal(t, []string{"0", "1"}, db.dsnames("memory", 2))
+
+	assert.Error(t, db.parse(strings.NewReader("invalid")))
+}
+
+func TestParseAuthFile(t *testing.T) {
+	passwords, err := parseAuthFile(strings.NewReader("# comment\notel: secret\nother:  pass:word \n"))
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"otel": "secret", "other": "pass:word"}, passwords)
+
+	_, err = parseAuthFile(strings.NewReader("otel secret"))
+	assert.EqualError(t, err, "invalid line 1")
+}
@@ -29,4 +29,15 @@ type Config struct {
 	Timeout          time.Duration `mapstructure:"timeout"`
 	AttributesPrefix string        `mapstructure:"attributes_prefix"`
 	Encoding         string        `mapstructure:"encoding"`
+
+	// The settings below only apply to the binary encoding.
+
+	// Paths of types.db files, used to name the values of multi-value types.
+	TypesDB []string `mapstructure:"types_db"`
+	// Path of a file of "user: password" lines, for signed and encrypted
+	// packets.
+	AuthFile string `mapstructure:"auth_file"`
+	// Either "none", "sign" or "encrypt", the minimum security of the
+	// accepted packets.
+	SecurityLevel string `mapstructure:"security_level"`
 }
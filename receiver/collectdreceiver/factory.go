@@ -34,6 +34,7 @@ const (
 	defaultBindEndpoint   = "localhost:8081"
 	defaultTimeout        = time.Duration(time.Second * 30)
 	defaultEncodingFormat = "json"
+	binaryEncodingFormat  = "binary"
 )
 
 // NewFactory creates a factory for collectd receiver.
@@ -65,13 +66,47 @@ func createMetricsReceiver(
 ) (component.MetricsReceiver, error) {
 	c := cfg.(*Config)
 	c.Encoding = strings.ToLower(c.Encoding)
-	// CollectD receiver only supports JSON encoding. We expose a config option
-	// to make it explicit and obvious to the users.
-	if c.Encoding != defaultEncodingFormat {
+	// CollectD receiver supports the JSON encoding of the write_http plugin
+	// over HTTP, and the binary encoding of the network plugin over UDP.
+	switch c.Encoding {
+	case defaultEncodingFormat:
+		return newCollectdReceiver(params.Logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	case binaryEncodingFormat:
+		decoder, err := newBinaryDecoder(c)
+		if err != nil {
+			return nil, err
+		}
+		return newCollectdUDPReceiver(params.Logger, c.Endpoint, decoder, nextConsumer)
+	}
+	return nil, fmt.Errorf(
+		"CollectD only support JSON and binary encoding formats. %s is not supported",
+		c.Encoding,
+	)
+}
+
+// newBinaryDecoder creates the decoder of the binary encoding, loading the
+// types.db and auth files of the config.
+func newBinaryDecoder(c *Config) (*binaryDecoder, error) {
+	level, ok := securityLevels[strings.ToLower(c.SecurityLevel)]
+	if !ok {
 		return nil, fmt.Errorf(
-			"CollectD only support JSON encoding format. %s is not supported",
-			c.Encoding,
+			"security_level must be %q, %q or %q, got %q",
+			securityLevelNone, securityLevelSign, securityLevelEncrypt, c.SecurityLevel,
 		)
 	}
-	return newCollectdReceiver(params.Logger, c.Endpoint, c.Timeout, c.AttributesPrefix, nextConsumer)
+	if level > levelNone && c.AuthFile == "" {
+		return nil, fmt.Errorf("auth_file must be set with security_level %q", c.SecurityLevel)
+	}
+
+	decoder := &binaryDecoder{securityLevel: level}
+	var err error
+	if c.AuthFile != "" {
+		if decoder.passwords, err = loadAuthFile(c.AuthFile); err != nil {
+			return nil, fmt.Errorf("failed to load auth_file: %w", err)
+		}
+	}
+	if decoder.typesDB, err = loadTypesDB(c.TypesDB); err != nil {
+		return nil, fmt.Errorf("failed to load types_db: %w", err)
+	}
+	return decoder, nil
 }
@@ -41,3 +41,37 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 }
+
+func TestCreateBinaryReceiver(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Encoding = "binary"
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	tReceiver, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.NoError(t, err)
+	assert.IsType(t, &collectdUDPReceiver{}, tReceiver)
+
+	cfg.SecurityLevel = "encrypt"
+	_, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.EqualError(t, err, `auth_file must be set with security_level "encrypt"`)
+
+	cfg.SecurityLevel = "paranoid"
+	_, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Error(t, err)
+
+	cfg.SecurityLevel = "sign"
+	cfg.AuthFile = "testdata/nonexistent"
+	_, err = factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Error(t, err)
+}
+
+func TestCreateReceiverUnsupportedEncoding(t *testing.T) {
+	factory := NewFactory()
+	cfg := factory.CreateDefaultConfig().(*Config)
+	cfg.Encoding = "command"
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := factory.CreateMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	assert.Error(t, err)
+}
@@ -0,0 +1,89 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// typesDB maps the types of collectd's types.db files to the names of their
+// data sources, which the binary protocol doesn't carry.
+type typesDB map[string][]string
+
+// loadTypesDB loads the given types.db files, e.g.
+// /usr/share/collectd/types.db.
+func loadTypesDB(paths []string) (typesDB, error) {
+	db := typesDB{}
+	for _, path := range paths {
+		f, err := os.Open(path)
+		if err != nil {
+			return nil, err
+		}
+		err = db.parse(f)
+		f.Close()
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+	return db, nil
+}
+
+// parse adds the types of lines like "if_octets rx:DERIVE:0:U, tx:DERIVE:0:U".
+func (db typesDB) parse(r io.Reader) error {
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		fields := strings.Fields(line)
+		if len(fields) < 2 {
+			return fmt.Errorf("invalid type %q", line)
+		}
+		var dsnames []string
+		for _, ds := range strings.Split(strings.Join(fields[1:], ""), ",") {
+			if ds == "" {
+				continue
+			}
+			dsnames = append(dsnames, strings.SplitN(ds, ":", 2)[0])
+		}
+		db[fields[0]] = dsnames
+	}
+	return scanner.Err()
+}
+
+// dsnames returns the data source names of the values of the given type.
+// Types that are unknown or have a different number of values get the name
+// "value" for a single value, as most types of collectd, and the index of the
+// values otherwise.
+func (db typesDB) dsnames(typeS string, count int) []string {
+	if dsnames, ok := db[typeS]; ok && len(dsnames) == count {
+		return dsnames
+	}
+	dsnames := make([]string, count)
+	for i := range dsnames {
+		if count == 1 {
+			dsnames[i] = "value"
+		} else {
+			dsnames[i] = strconv.Itoa(i)
+		}
+	}
+	return dsnames
+}
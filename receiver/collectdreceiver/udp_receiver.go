@@ -0,0 +1,166 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"sync"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+// The maximum size of a UDP packet.
+const maxPacketSize = 65535
+
+var _ component.MetricsReceiver = (*collectdUDPReceiver)(nil)
+
+// collectdUDPReceiver implements the component.MetricsReceiver for the
+// binary protocol of collectd's network plugin.
+type collectdUDPReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	addr         string
+	conn         net.PacketConn
+	decoder      *binaryDecoder
+	nextConsumer consumer.MetricsConsumer
+	wg           sync.WaitGroup
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+// newCollectdUDPReceiver creates the collectd binary protocol receiver with
+// the given parameters.
+func newCollectdUDPReceiver(
+	logger *zap.Logger,
+	addr string,
+	decoder *binaryDecoder,
+	nextConsumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
+	if nextConsumer == nil {
+		return nil, errNilNextConsumer
+	}
+
+	return &collectdUDPReceiver{
+		logger:       logger,
+		addr:         addr,
+		decoder:      decoder,
+		nextConsumer: nextConsumer,
+	}, nil
+}
+
+// Start starts listening for collectd packets on UDP.
+func (cdr *collectdUDPReceiver) Start(_ context.Context, host component.Host) error {
+	cdr.Lock()
+	defer cdr.Unlock()
+
+	err := errAlreadyStarted
+	cdr.startOnce.Do(func() {
+		cdr.conn, err = net.ListenPacket("udp", cdr.addr)
+		if err != nil {
+			err = fmt.Errorf("error starting collectd receiver: %w", err)
+			return
+		}
+		cdr.wg.Add(1)
+		go func() {
+			defer cdr.wg.Done()
+			if err := cdr.serve(); err != nil {
+				host.ReportFatalError(fmt.Errorf("error reading collectd packets: %w", err))
+			}
+		}()
+	})
+
+	return err
+}
+
+// Shutdown stops the collectd receiver.
+func (cdr *collectdUDPReceiver) Shutdown(context.Context) error {
+	cdr.Lock()
+	defer cdr.Unlock()
+
+	var err = errAlreadyStopped
+	cdr.stopOnce.Do(func() {
+		err = nil
+		if cdr.conn != nil {
+			err = cdr.conn.Close()
+			cdr.wg.Wait()
+		}
+	})
+	return err
+}
+
+// serve reads packets until the connection is closed.
+func (cdr *collectdUDPReceiver) serve() error {
+	buf := make([]byte, maxPacketSize)
+	for {
+		n, _, err := cdr.conn.ReadFrom(buf)
+		if n > 0 {
+			cdr.handlePacket(buf[:n])
+		}
+		if err != nil {
+			var netErr net.Error
+			if errors.As(err, &netErr) && netErr.Temporary() {
+				continue
+			}
+			if isClosedConnErr(err) {
+				return nil
+			}
+			return err
+		}
+	}
+}
+
+func (cdr *collectdUDPReceiver) handlePacket(packet []byte) {
+	recordRequestReceived()
+
+	records, err := cdr.decoder.decode(packet)
+	if err != nil {
+		recordRequestErrors()
+		cdr.logger.Debug("unable to decode collectd packet", zap.Error(err))
+		return
+	}
+
+	md := consumerdata.MetricsData{}
+	for _, record := range records {
+		md.Metrics, err = record.appendToMetrics(md.Metrics, nil)
+		if err != nil {
+			recordRequestErrors()
+			cdr.logger.Error("unable to process metrics", zap.Error(err))
+			return
+		}
+	}
+	if len(md.Metrics) == 0 {
+		return
+	}
+
+	err = cdr.nextConsumer.ConsumeMetrics(context.Background(), internaldata.OCToMetrics(md))
+	if err != nil {
+		recordRequestErrors()
+		cdr.logger.Error("unable to process metrics", zap.Error(err))
+	}
+}
+
+// isClosedConnErr reports whether err is returned by reading a closed
+// connection, which net doesn't export before Go 1.16.
+func isClosedConnErr(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Err.Error() == "use of closed network connection"
+}
@@ -0,0 +1,75 @@
+// Copyright 2019, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collectdreceiver
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.uber.org/zap"
+)
+
+func TestNewUDPReceiver(t *testing.T) {
+	_, err := newCollectdUDPReceiver(zap.NewNop(), ":0", testDecoder(), nil)
+	assert.Equal(t, errNilNextConsumer, err)
+}
+
+func TestCollectDUDPServer(t *testing.T) {
+	endpoint := testutil.GetAvailableLocalAddress(t)
+	sink := new(exportertest.SinkMetricsExporter)
+	decoder := testDecoder()
+	decoder.securityLevel = levelSign
+	cdr, err := newCollectdUDPReceiver(zap.NewNop(), endpoint, decoder, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, cdr.Start(context.Background(), componenttest.NewNopHost()))
+	defer func() {
+		require.NoError(t, cdr.Shutdown(context.Background()))
+	}()
+
+	conn, err := net.Dial("udp", endpoint)
+	require.NoError(t, err)
+	defer conn.Close()
+
+	// The unsigned packet is dropped, only the signed one is consumed.
+	_, err = conn.Write(testPacket())
+	require.NoError(t, err)
+	_, err = conn.Write(sign(testPacket(), "otel", "secret"))
+	require.NoError(t, err)
+
+	testutil.WaitFor(t, func() bool {
+		return len(sink.AllMetrics()) == 1
+	})
+	mds := sink.AllMetrics()
+	require.Len(t, mds, 1)
+	md := internaldata.MetricsToOC(mds[0])
+	require.Len(t, md, 1)
+	assert.Len(t, md[0].Metrics, 3)
+	assert.Equal(t, "memory.free", md[0].Metrics[0].MetricDescriptor.Name)
+}
+
+func TestCollectDUDPServerShutdownWithoutStart(t *testing.T) {
+	cdr, err := newCollectdUDPReceiver(zap.NewNop(), ":0", testDecoder(), exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	assert.NoError(t, cdr.Shutdown(context.Background()))
+	assert.Equal(t, errAlreadyStopped, cdr.Shutdown(context.Background()))
+}
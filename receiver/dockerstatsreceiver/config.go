@@ -16,11 +16,26 @@ package dockerstatsreceiver
 
 import (
 	"errors"
+	"fmt"
+	"regexp"
 	"time"
 
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
+)
+
+const (
+	// dockerCompatibilityMode expects the stats payloads of the Docker daemon.
+	dockerCompatibilityMode = "docker"
+	// podmanCompatibilityMode tolerates the stats payloads of Podman's
+	// Docker-compatible API.
+	podmanCompatibilityMode = "podman"
 )
 
+var apiVersionRegexp = regexp.MustCompile(`^v?[0-9]+\.[0-9]+$`)
+
 var _ configmodels.Receiver = (*Config)(nil)
 
 type Config struct {
@@ -53,6 +68,18 @@ type Config struct {
 
 	// Whether to report all CPU metrics.  Default is false
 	ProvidePerCoreCPUMetrics bool `mapstructure:"provide_per_core_cpu_metrics"`
+
+	// The TLS settings used to connect to a daemon listening on TCP with TLS.
+	// No TLS is used when unset.
+	TLS *configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// The Docker API version to use, e.g. "1.40", or "auto" to negotiate the
+	// highest version supported by the daemon.  Default is 1.22.
+	APIVersion string `mapstructure:"api_version"`
+
+	// Either "docker" or "podman", to tolerate the stats payloads of Podman's
+	// Docker-compatible API.  Default is "docker".
+	CompatibilityMode string `mapstructure:"compatibility_mode"`
 }
 
 func (config Config) Validate() error {
@@ -62,5 +89,14 @@ func (config Config) Validate() error {
 	if config.CollectionInterval == 0 {
 		return errors.New("config.CollectionInterval must be specified")
 	}
+	if config.APIVersion != "" && config.APIVersion != docker.NegotiateAPIVersion && !apiVersionRegexp.MatchString(config.APIVersion) {
+		return fmt.Errorf("config.APIVersion must be %q or a version such as \"1.40\", got %q", docker.NegotiateAPIVersion, config.APIVersion)
+	}
+	switch config.CompatibilityMode {
+	case "", dockerCompatibilityMode, podmanCompatibilityMode:
+	default:
+		return fmt.Errorf("config.CompatibilityMode must be %q or %q, got %q",
+			dockerCompatibilityMode, podmanCompatibilityMode, config.CompatibilityMode)
+	}
 	return nil
 }
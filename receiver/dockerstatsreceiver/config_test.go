@@ -24,6 +24,7 @@ import (
 	"go.opentelemetry.io/collector/component/componenttest"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/config/configtest"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 func TestLoadConfig(t *testing.T) {
@@ -38,7 +39,7 @@ func TestLoadConfig(t *testing.T) {
 
 	require.NoError(t, err)
 	require.NotNil(t, config)
-	assert.Equal(t, 2, len(config.Receivers))
+	assert.Equal(t, 4, len(config.Receivers))
 
 	defaultConfig := config.Receivers["docker_stats"]
 	assert.Equal(t, factory.CreateDefaultConfig(), defaultConfig)
@@ -77,4 +78,59 @@ func TestLoadConfig(t *testing.T) {
 	}, ascfg.EnvVarsToMetricLabels)
 
 	assert.True(t, ascfg.ProvidePerCoreCPUMetrics)
+	assert.Nil(t, ascfg.TLS)
+	assert.Equal(t, "", ascfg.APIVersion)
+	assert.Equal(t, "", ascfg.CompatibilityMode)
+
+	tlscfg := config.Receivers["docker_stats/tls"].(*Config)
+	assert.Equal(t, "tcp://build-host:2376", tlscfg.Endpoint)
+	assert.Equal(t, "auto", tlscfg.APIVersion)
+	assert.Equal(t, &configtls.TLSClientSetting{
+		TLSSetting: configtls.TLSSetting{
+			CAFile:   "/etc/docker/ca.pem",
+			CertFile: "/etc/docker/cert.pem",
+			KeyFile:  "/etc/docker/key.pem",
+		},
+	}, tlscfg.TLS)
+
+	podmancfg := config.Receivers["docker_stats/podman"].(*Config)
+	assert.Equal(t, "unix:///run/podman/podman.sock", podmancfg.Endpoint)
+	assert.Equal(t, "1.40", podmancfg.APIVersion)
+	assert.Equal(t, "podman", podmancfg.CompatibilityMode)
+	assert.NoError(t, podmancfg.Validate())
+}
+
+func TestValidateConfig(t *testing.T) {
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		err    string
+	}{
+		{name: "default", modify: func(*Config) {}},
+		{name: "api version", modify: func(c *Config) { c.APIVersion = "v1.41" }},
+		{name: "negotiated api version", modify: func(c *Config) { c.APIVersion = "auto" }},
+		{
+			name:   "invalid api version",
+			modify: func(c *Config) { c.APIVersion = "latest" },
+			err:    `config.APIVersion must be "auto" or a version such as "1.40", got "latest"`,
+		},
+		{name: "docker compatibility mode", modify: func(c *Config) { c.CompatibilityMode = "docker" }},
+		{
+			name:   "invalid compatibility mode",
+			modify: func(c *Config) { c.CompatibilityMode = "containerd" },
+			err:    `config.CompatibilityMode must be "docker" or "podman", got "containerd"`,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := NewFactory().CreateDefaultConfig().(*Config)
+			tt.modify(config)
+			err := config.Validate()
+			if tt.err == "" {
+				assert.NoError(t, err)
+			} else {
+				assert.EqualError(t, err, tt.err)
+			}
+		})
+	}
 }
@@ -0,0 +1,107 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"strconv"
+	"strings"
+
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
+)
+
+const (
+	// eventLogName is the name of the log records of container lifecycle events.
+	eventLogName = "container.event"
+
+	// Attribute keys of event log records.
+	eventKeyAction       = "container.event.action"
+	eventKeyExitCode     = "container.exit_code"
+	eventKeyHealthStatus = "container.health.status"
+)
+
+// reportedEventActions are the container event actions reported as logs.
+var reportedEventActions = map[string]bool{
+	"start":         true,
+	"die":           true,
+	"oom":           true,
+	"health_status": true,
+}
+
+// containerEventToLogs converts a container lifecycle event to logs with a single
+// log record, and returns false if the event isn't reported. The container labels
+// are set as resource attributes, like the ones of the container metrics.
+func containerEventToLogs(event devents.Message, container *docker.Container, config *Config) (pdata.Logs, bool) {
+	// Health status events have the status as a suffix of their action,
+	// e.g. "health_status: healthy".
+	action := event.Action
+	var healthStatus string
+	if i := strings.Index(action, ":"); i >= 0 {
+		action, healthStatus = action[:i], strings.TrimSpace(action[i+1:])
+	}
+	if !reportedEventActions[action] {
+		return pdata.Logs{}, false
+	}
+
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+
+	resource := rl.Resource()
+	resource.InitEmpty()
+	for k, v := range containerResourceLabels(container, config) {
+		resource.Attributes().InsertString(k, v)
+	}
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	lrs := ills.At(0).Logs()
+	lrs.Resize(1)
+
+	lr := lrs.At(0)
+	lr.InitEmpty()
+	lr.SetName(eventLogName)
+	lr.SetTimestamp(pdata.TimestampUnixNano(event.TimeNano))
+	lr.Body().SetStringVal(event.Action)
+
+	// Containers killed for running out of memory, failing or becoming
+	// unhealthy are reported as warnings.
+	severity := pdata.SeverityNumberINFO
+	attrs := lr.Attributes()
+	attrs.InsertString(eventKeyAction, action)
+	switch action {
+	case "die":
+		if exitCode, err := strconv.ParseInt(event.Actor.Attributes["exitCode"], 10, 64); err == nil {
+			attrs.InsertInt(eventKeyExitCode, exitCode)
+			if exitCode != 0 {
+				severity = pdata.SeverityNumberWARN
+			}
+		}
+	case "oom":
+		severity = pdata.SeverityNumberWARN
+	case "health_status":
+		attrs.InsertString(eventKeyHealthStatus, healthStatus)
+		if healthStatus == dtypes.Unhealthy {
+			severity = pdata.SeverityNumberWARN
+		}
+	}
+	lr.SetSeverityNumber(severity)
+
+	return ld, true
+}
@@ -0,0 +1,150 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	devents "github.com/docker/docker/api/types/events"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+func TestContainerEventToLogs(t *testing.T) {
+	container := containerJSON(t)
+	config := &Config{
+		EnvVarsToMetricLabels: map[string]string{"MY_ENV_VAR": "my.env.to.metric.label"},
+	}
+
+	tests := []struct {
+		name     string
+		action   string
+		attrs    map[string]string
+		expected map[string]interface{}
+		severity pdata.SeverityNumber
+	}{
+		{
+			name:   "start",
+			action: "start",
+			expected: map[string]interface{}{
+				"container.event.action": "start",
+			},
+			severity: pdata.SeverityNumberINFO,
+		},
+		{
+			name:   "die",
+			action: "die",
+			attrs:  map[string]string{"exitCode": "137"},
+			expected: map[string]interface{}{
+				"container.event.action": "die",
+				"container.exit_code":    int64(137),
+			},
+			severity: pdata.SeverityNumberWARN,
+		},
+		{
+			name:   "successful exit",
+			action: "die",
+			attrs:  map[string]string{"exitCode": "0"},
+			expected: map[string]interface{}{
+				"container.event.action": "die",
+				"container.exit_code":    int64(0),
+			},
+			severity: pdata.SeverityNumberINFO,
+		},
+		{
+			name:   "oom",
+			action: "oom",
+			expected: map[string]interface{}{
+				"container.event.action": "oom",
+			},
+			severity: pdata.SeverityNumberWARN,
+		},
+		{
+			name:   "health status",
+			action: "health_status: unhealthy",
+			expected: map[string]interface{}{
+				"container.event.action":  "health_status",
+				"container.health.status": "unhealthy",
+			},
+			severity: pdata.SeverityNumberWARN,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			event := devents.Message{
+				Type:     devents.ContainerEventType,
+				Action:   tt.action,
+				Actor:    devents.Actor{ID: container.ID, Attributes: tt.attrs},
+				TimeNano: 1600000000000000000,
+			}
+			ld, ok := containerEventToLogs(event, container, config)
+			require.True(t, ok)
+			require.Equal(t, 1, ld.LogRecordCount())
+
+			rl := ld.ResourceLogs().At(0)
+			resourceAttrs := rl.Resource().Attributes()
+			for k, v := range mergeMaps(defaultLabels(), map[string]string{"my.env.to.metric.label": "my_env_var_value"}) {
+				attr, ok := resourceAttrs.Get(k)
+				require.True(t, ok, k)
+				assert.Equal(t, v, attr.StringVal())
+			}
+
+			lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+			assert.Equal(t, "container.event", lr.Name())
+			assert.Equal(t, pdata.TimestampUnixNano(1600000000000000000), lr.Timestamp())
+			assert.Equal(t, tt.action, lr.Body().StringVal())
+			assert.Equal(t, tt.severity, lr.SeverityNumber())
+			assert.Equal(t, len(tt.expected), lr.Attributes().Len())
+			for k, v := range tt.expected {
+				attr, ok := lr.Attributes().Get(k)
+				require.True(t, ok, k)
+				switch attr.Type() {
+				case pdata.AttributeValueINT:
+					assert.Equal(t, v, attr.IntVal(), k)
+				default:
+					assert.Equal(t, v, attr.StringVal(), k)
+				}
+			}
+		})
+	}
+}
+
+func TestContainerEventToLogsIgnoredActions(t *testing.T) {
+	for _, action := range []string{"pause", "unpause", "stop", "update"} {
+		_, ok := containerEventToLogs(devents.Message{Action: action}, containerJSON(t), &Config{})
+		assert.False(t, ok, action)
+	}
+}
+
+func TestConsumeContainerEvent(t *testing.T) {
+	sink := &exportertest.SinkLogsExporter{}
+	r := &Receiver{
+		config:       &Config{},
+		logger:       zap.NewNop(),
+		logsConsumer: sink,
+		runnerCtx:    context.Background(),
+	}
+
+	r.consumeContainerEvent(devents.Message{Action: "update"}, containerJSON(t))
+	assert.Equal(t, 0, sink.LogRecordsCount())
+
+	r.consumeContainerEvent(devents.Message{Action: "start", TimeNano: time.Now().UnixNano()}, containerJSON(t))
+	assert.Equal(t, 1, sink.LogRecordsCount())
+}
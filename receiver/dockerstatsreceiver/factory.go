@@ -16,6 +16,7 @@ package dockerstatsreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -28,11 +29,17 @@ const (
 	typeStr = "docker_stats"
 )
 
+// receivers holds the receiver created for each config so that the same
+// instance is used by all data types.
+var receivers = map[*Config]*Receiver{}
+var receiversLock sync.Mutex
+
 func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -53,12 +60,47 @@ func createMetricsReceiver(
 	config configmodels.Receiver,
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
-	dockerConfig := config.(*Config)
+	dsr, err := getOrCreateReceiver(ctx, params, config.(*Config))
+	if err != nil {
+		return nil, err
+	}
 
-	dsr, err := NewReceiver(ctx, params.Logger, dockerConfig, consumer)
+	dsr.nextConsumer = consumer
+	return dsr, nil
+}
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	config configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	dsr, err := getOrCreateReceiver(ctx, params, config.(*Config))
 	if err != nil {
 		return nil, err
 	}
 
+	dsr.logsConsumer = consumer
 	return dsr, nil
 }
+
+func getOrCreateReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	dockerConfig *Config,
+) (*Receiver, error) {
+	receiversLock.Lock()
+	defer receiversLock.Unlock()
+
+	if dsr, ok := receivers[dockerConfig]; ok {
+		return dsr, nil
+	}
+
+	dsr, err := NewReceiver(ctx, params.Logger, dockerConfig, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	receivers[dockerConfig] = dsr.(*Receiver)
+	return dsr.(*Receiver), nil
+}
@@ -28,23 +28,17 @@ import (
 	"github.com/golang/protobuf/ptypes/timestamp"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/translator/conventions"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
 )
 
 const (
 	metricPrefix = "container."
 )
 
-// client.ContainerInspect() response container
-// stats and translated environment string map
-// for potential labels
-type DockerContainer struct {
-	*dtypes.ContainerJSON
-	EnvMap map[string]string
-}
-
 func ContainerStatsToMetrics(
 	containerStats *dtypes.StatsJSON,
-	container *DockerContainer,
+	container *docker.Container,
 	config *Config,
 ) (*consumerdata.MetricsData, error) {
 	now, _ := ptypes.TimestampProto(time.Now())
@@ -54,6 +48,7 @@ func ContainerStatsToMetrics(
 	metrics = append(metrics, cpuMetrics(&containerStats.CPUStats, &containerStats.PreCPUStats, now, config.ProvidePerCoreCPUMetrics)...)
 	metrics = append(metrics, memoryMetrics(&containerStats.MemoryStats, now)...)
 	metrics = append(metrics, networkMetrics(&containerStats.Networks, now)...)
+	metrics = append(metrics, stateMetrics(container.State, container.RestartCount, now)...)
 
 	if len(metrics) == 0 {
 		return nil, nil
@@ -62,33 +57,37 @@ func ContainerStatsToMetrics(
 	md := &consumerdata.MetricsData{
 		Metrics: metrics,
 		Resource: &resourcepb.Resource{
-			Type: "container",
-			Labels: map[string]string{
-				"container.hostname":                container.Config.Hostname,
-				conventions.AttributeContainerID:    container.ID,
-				conventions.AttributeContainerImage: container.Config.Image,
-				conventions.AttributeContainerName:  strings.TrimPrefix(container.Name, "/"),
-			},
+			Type:   "container",
+			Labels: containerResourceLabels(container, config),
 		},
 	}
 
-	updateConfiguredResourceLabels(md, container, config)
-
 	return md, nil
 }
 
-func updateConfiguredResourceLabels(md *consumerdata.MetricsData, container *DockerContainer, config *Config) {
+// containerResourceLabels returns the labels identifying the container, including
+// the ones configured from its environment variables and labels.
+func containerResourceLabels(container *docker.Container, config *Config) map[string]string {
+	labels := map[string]string{
+		"container.hostname":                container.Config.Hostname,
+		conventions.AttributeContainerID:    container.ID,
+		conventions.AttributeContainerImage: container.Config.Image,
+		conventions.AttributeContainerName:  strings.TrimPrefix(container.Name, "/"),
+	}
+
 	for k, label := range config.EnvVarsToMetricLabels {
 		if v := container.EnvMap[k]; v != "" {
-			md.Resource.Labels[label] = v
+			labels[label] = v
 		}
 	}
 
 	for k, label := range config.ContainerLabelsToMetricLabels {
 		if v := container.Config.Labels[k]; v != "" {
-			md.Resource.Labels[label] = v
+			labels[label] = v
 		}
 	}
+
+	return labels
 }
 
 type blkioStat struct {
@@ -288,6 +287,34 @@ func networkMetrics(
 	return metrics
 }
 
+// stateMetrics reports the restart count and health check status of the
+// container, as last inspected.
+func stateMetrics(
+	state *dtypes.ContainerState,
+	restartCount int,
+	ts *timestamp.Timestamp,
+) []*metricspb.Metric {
+	metrics := []*metricspb.Metric{
+		Cumulative("restarts", []int64{int64(restartCount)}, ts, "1", nil, nil),
+	}
+
+	// Containers without a health check have no health state, or a "none" status.
+	if state == nil || state.Health == nil || state.Health.Status == dtypes.NoHealthcheck {
+		return metrics
+	}
+
+	var healthy int64
+	if state.Health.Status == dtypes.Healthy {
+		healthy = 1
+	}
+	metrics = append(metrics, []*metricspb.Metric{
+		Gauge("health.status", []int64{healthy}, ts, "1", nil, nil),
+		Gauge("health.failing_streak", []int64{int64(state.Health.FailingStreak)}, ts, "1", nil, nil),
+	}...)
+
+	return metrics
+}
+
 func Cumulative(name string, vals []int64, ts *timestamp.Timestamp, unit string, labelKeys []string, labelValues [][]string) *metricspb.Metric {
 	return metric(name, metricspb.MetricDescriptor_CUMULATIVE_INT64, ts, unit, labelKeys, labelValues, vals, nil)
 }
@@ -176,6 +176,7 @@ func defaultMetrics() []Metric {
 		{name: "container.network.io.usage.tx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
 		{name: "container.network.io.usage.tx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
 		{name: "container.network.io.usage.tx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 9050}}},
+		{name: "container.restarts", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 	}
 }
 
@@ -236,6 +237,7 @@ func TestZeroValueStats(t *testing.T) {
 		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
 		{name: "container.memory.usage.max", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.restarts", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 	}
 	assertMetricsDataEqual(t, metrics, nil, md)
 }
@@ -254,7 +256,7 @@ func statsJSON(t *testing.T) *dtypes.StatsJSON {
 	return &stats
 }
 
-func containerJSON(t *testing.T) *DockerContainer {
+func containerJSON(t *testing.T) *docker.Container {
 	containerRaw, err := ioutil.ReadFile(path.Join(".", "testdata", "container.json"))
 	if err != nil {
 		t.Fatal(err)
@@ -265,9 +267,9 @@ func containerJSON(t *testing.T) *DockerContainer {
 	if err != nil {
 		t.Fatal(err)
 	}
-	return &DockerContainer{
+	return &docker.Container{
 		ContainerJSON: &container,
-		EnvMap:        containerEnvToMap(container.Config.Env),
+		EnvMap:        docker.ContainerEnvToMap(container.Config.Env),
 	}
 }
 
@@ -375,6 +377,7 @@ func TestStatsToAllMetrics(t *testing.T) {
 		{name: "container.network.io.usage.tx_dropped", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
 		{name: "container.network.io.usage.tx_errors", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 0}}},
 		{name: "container.network.io.usage.tx_packets", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: []string{"interface"}, values: []Value{{labelValues: []string{"eth0"}, value: 9050}}},
+		{name: "container.restarts", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
 	}
 
 	assertMetricsDataEqual(t, metrics, nil, md)
@@ -423,3 +426,42 @@ func TestContainerLabelToMetricLabels(t *testing.T) {
 
 	assertMetricsDataEqual(t, defaultMetrics(), expectedLabels, md)
 }
+
+func TestContainerStateToMetrics(t *testing.T) {
+	stats := &dtypes.StatsJSON{}
+	container := containerJSON(t)
+	container.RestartCount = 3
+	container.State.Health = &dtypes.Health{
+		Status:        dtypes.Unhealthy,
+		FailingStreak: 2,
+	}
+
+	md, err := ContainerStatsToMetrics(stats, container, &Config{})
+	assert.Nil(t, err)
+	assert.NotNil(t, md)
+
+	metrics := []Metric{
+		{name: "container.cpu.usage.system", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.total", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.kernelmode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.usage.usermode", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_periods", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.throttling_data.throttled_time", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "ns", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.cpu.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
+		{name: "container.memory.usage.limit", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.usage.total", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.memory.percent", mtype: metricspb.MetricDescriptor_GAUGE_DOUBLE, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, doubleValue: 0}}},
+		{name: "container.memory.usage.max", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "By", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.restarts", mtype: metricspb.MetricDescriptor_CUMULATIVE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 3}}},
+		{name: "container.health.status", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 0}}},
+		{name: "container.health.failing_streak", mtype: metricspb.MetricDescriptor_GAUGE_INT64, unit: "1", labelKeys: nil, values: []Value{{labelValues: nil, value: 2}}},
+	}
+	assertMetricsDataEqual(t, metrics, nil, md)
+
+	container.State.Health.Status = dtypes.Healthy
+	md, err = ContainerStatsToMetrics(stats, container, &Config{})
+	assert.Nil(t, err)
+	assert.Equal(t, "container.health.status", md.Metrics[len(md.Metrics)-2].MetricDescriptor.Name)
+	assert.Equal(t, int64(1), md.Metrics[len(md.Metrics)-2].Timeseries[0].Points[0].GetInt64Value())
+}
@@ -0,0 +1,48 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"math"
+
+	dtypes "github.com/docker/docker/api/types"
+)
+
+// normalizePodmanStats fills in the stats that Podman's Docker-compatible API
+// reports differently from the Docker daemon, so that they are converted to
+// the same metrics:
+//   - The previous CPU stats aren't reported, so the ones of the previous
+//     collection are used to compute the CPU percentage.
+//   - On cgroups v2 hosts the page cache is reported as "file" instead of
+//     "cache" and "total_cache", and is excluded from the memory usage.
+//   - Containers without a memory limit report the largest uint64 limit.
+func normalizePodmanStats(stats *dtypes.StatsJSON, previous *dtypes.CPUStats) {
+	if previous != nil && stats.PreCPUStats.SystemUsage == 0 && stats.PreCPUStats.CPUUsage.TotalUsage == 0 {
+		stats.PreCPUStats = *previous
+	}
+
+	if file, ok := stats.MemoryStats.Stats["file"]; ok {
+		if _, ok := stats.MemoryStats.Stats["cache"]; !ok {
+			stats.MemoryStats.Stats["cache"] = file
+		}
+		if _, ok := stats.MemoryStats.Stats["total_cache"]; !ok {
+			stats.MemoryStats.Stats["total_cache"] = file
+		}
+	}
+
+	if stats.MemoryStats.Limit > math.MaxInt64 {
+		stats.MemoryStats.Limit = 0
+	}
+}
@@ -0,0 +1,112 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package dockerstatsreceiver
+
+import (
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"path"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	dtypes "github.com/docker/docker/api/types"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
+)
+
+func podmanStatsJSON(t *testing.T) *dtypes.StatsJSON {
+	statsRaw, err := ioutil.ReadFile(path.Join(".", "testdata", "podman_stats.json"))
+	require.NoError(t, err)
+
+	var stats dtypes.StatsJSON
+	require.NoError(t, json.Unmarshal(statsRaw, &stats))
+	return &stats
+}
+
+func findMetric(t *testing.T, md *consumerdata.MetricsData, name string) *metricspb.Metric {
+	for _, m := range md.Metrics {
+		if m.MetricDescriptor.Name == name {
+			return m
+		}
+	}
+	require.Failf(t, "metric not found", name)
+	return nil
+}
+
+func TestPodmanStatsToMetrics(t *testing.T) {
+	stats := podmanStatsJSON(t)
+	normalizePodmanStats(stats, nil)
+
+	md, err := ContainerStatsToMetrics(stats, containerJSON(t), &Config{})
+	require.NoError(t, err)
+	require.NotNil(t, md)
+
+	// The page cache is excluded from the usage and the unlimited memory limit is dropped.
+	assert.Equal(t, int64(9850880-5365760), findMetric(t, md, "container.memory.usage.total").Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, int64(0), findMetric(t, md, "container.memory.usage.limit").Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, 0.0, findMetric(t, md, "container.memory.percent").Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, int64(5365760), findMetric(t, md, "container.memory.total_cache").Timeseries[0].Points[0].GetInt64Value())
+
+	// The CPU percentage can't be computed without previous stats.
+	assert.Equal(t, 0.0, findMetric(t, md, "container.cpu.percent").Timeseries[0].Points[0].GetDoubleValue())
+	assert.Equal(t, int64(61219000), findMetric(t, md, "container.cpu.usage.total").Timeseries[0].Points[0].GetInt64Value())
+
+	assert.Equal(t, int64(5697536), findMetric(t, md, "container.blockio.io_service_bytes_recursive.read").Timeseries[0].Points[0].GetInt64Value())
+	assert.Equal(t, int64(1086), findMetric(t, md, "container.network.io.usage.rx_bytes").Timeseries[0].Points[0].GetInt64Value())
+}
+
+func TestPodmanStatsWithPreviousCPUStats(t *testing.T) {
+	stats := podmanStatsJSON(t)
+	previous := &dtypes.CPUStats{
+		CPUUsage:    dtypes.CPUUsage{TotalUsage: 41219000},
+		SystemUsage: 1195845000000,
+	}
+	normalizePodmanStats(stats, previous)
+	assert.Equal(t, *previous, stats.PreCPUStats)
+
+	md, err := ContainerStatsToMetrics(stats, containerJSON(t), &Config{})
+	require.NoError(t, err)
+	// 20ms of the 2s of the 2 CPUs.
+	assert.InDelta(t, 2.0, findMetric(t, md, "container.cpu.percent").Timeseries[0].Points[0].GetDoubleValue(), 1e-9)
+}
+
+func TestNormalizeDockerStatsIsNoop(t *testing.T) {
+	stats := statsJSON(t)
+	expected := statsJSON(t)
+	normalizePodmanStats(stats, &dtypes.CPUStats{SystemUsage: 1})
+	assert.Equal(t, expected, stats)
+}
+
+func TestForgetPodmanCPUStats(t *testing.T) {
+	r, err := NewReceiver(context.Background(), zap.NewNop(), &Config{
+		Endpoint:           "unix:///run/podman/podman.sock",
+		CollectionInterval: 1,
+		CompatibilityMode:  podmanCompatibilityMode,
+	}, nil)
+	require.NoError(t, err)
+	receiver := r.(*Receiver)
+	receiver.podmanCPUStats["a"] = dtypes.CPUStats{}
+	receiver.podmanCPUStats["b"] = dtypes.CPUStats{}
+
+	container := containerJSON(t)
+	container.ID = "a"
+	receiver.forgetPodmanCPUStats([]docker.Container{*container})
+	assert.Equal(t, map[string]dtypes.CPUStats{"a": {}}, receiver.podmanCPUStats)
+}
@@ -16,10 +16,13 @@ package dockerstatsreceiver
 
 import (
 	"context"
+	"crypto/tls"
 	"fmt"
 	"net/url"
 	"sync"
 
+	dtypes "github.com/docker/docker/api/types"
+	devents "github.com/docker/docker/api/types/events"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/consumer/consumerdata"
@@ -27,23 +30,35 @@ import (
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 
+	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/docker"
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
 )
 
+const userAgent = "OpenTelemetry-Collector Docker Stats Receiver/v0.0.1"
+
 var _ component.MetricsReceiver = (*Receiver)(nil)
+var _ component.LogsReceiver = (*Receiver)(nil)
 var _ interval.Runnable = (*Receiver)(nil)
 
+// Receiver reports container stats as metrics and container lifecycle events
+// as logs.  A single instance is shared by all pipelines using the same config.
 type Receiver struct {
 	config            *Config
 	logger            *zap.Logger
 	nextConsumer      consumer.MetricsConsumer
-	client            *dockerClient
+	logsConsumer      consumer.LogsConsumer
+	client            *docker.Client
 	runner            *interval.Runner
 	obsCtx            context.Context
 	runnerCtx         context.Context
 	runnerCancel      context.CancelFunc
 	successfullySetup bool
 	transport         string
+
+	// podmanCPUStats holds the last CPU stats of each container in the podman
+	// compatibility mode, since Podman doesn't report the previous ones.
+	podmanCPUStats     map[string]dtypes.CPUStats
+	podmanCPUStatsLock sync.Mutex
 }
 
 func NewReceiver(
@@ -68,17 +83,39 @@ func NewReceiver(
 		logger:       logger,
 		transport:    parsed.Scheme,
 	}
+	if config.CompatibilityMode == podmanCompatibilityMode {
+		receiver.podmanCPUStats = map[string]dtypes.CPUStats{}
+	}
 
 	return &receiver, nil
 }
 
 func (r *Receiver) Start(ctx context.Context, host component.Host) error {
+	var tlsConfig *tls.Config
+	if r.config.TLS != nil {
+		var err error
+		if tlsConfig, err = r.config.TLS.LoadTLSConfig(); err != nil {
+			return fmt.Errorf("failed to load TLS config: %w", err)
+		}
+	}
+
 	var err error
-	r.client, err = newDockerClient(r.config, r.logger)
+	r.client, err = docker.NewDockerClient(&docker.Config{
+		Endpoint:       r.config.Endpoint,
+		Timeout:        r.config.Timeout,
+		ExcludedImages: r.config.ExcludedImages,
+		UserAgent:      userAgent,
+		APIVersion:     r.config.APIVersion,
+		TLSConfig:      tlsConfig,
+	}, r.logger)
 	if err != nil {
 		return err
 	}
 
+	if r.logsConsumer != nil {
+		r.client.SetEventHandler(r.consumeContainerEvent)
+	}
+
 	r.obsCtx = obsreport.ReceiverContext(ctx, typeStr, r.transport, r.config.Name())
 
 	r.runnerCtx, r.runnerCancel = context.WithCancel(context.Background())
@@ -120,16 +157,22 @@ func (r *Receiver) Run() error {
 		return r.Setup()
 	}
 
+	// Only events are reported when the receiver isn't used in a metrics pipeline.
+	if r.nextConsumer == nil {
+		return nil
+	}
+
 	c := obsreport.StartMetricsReceiveOp(r.obsCtx, typeStr, r.transport)
 
 	containers := r.client.Containers()
 	results := make(chan result, len(containers))
+	r.forgetPodmanCPUStats(containers)
 
 	wg := &sync.WaitGroup{}
 	wg.Add(len(containers))
 	for _, container := range containers {
-		go func(dc DockerContainer) {
-			md, err := r.client.FetchContainerStatsAndConvertToMetrics(r.runnerCtx, dc)
+		go func(dc docker.Container) {
+			md, err := r.fetchContainerStatsAndConvertToMetrics(r.runnerCtx, dc)
 			results <- result{md, err}
 			wg.Done()
 		}(container)
@@ -162,3 +205,70 @@ func (r *Receiver) Run() error {
 	obsreport.EndMetricsReceiveOp(c, typeStr, numPoints, numTimeSeries, lastErr)
 	return nil
 }
+
+// fetchContainerStatsAndConvertToMetrics will query the desired container stats and
+// return the converted metrics, since this is intended to be run in a goroutine.
+func (r *Receiver) fetchContainerStatsAndConvertToMetrics(
+	ctx context.Context,
+	container docker.Container,
+) (*consumerdata.MetricsData, error) {
+	statsJSON, err := r.client.FetchContainerStatsAsJSON(ctx, container)
+	if err != nil {
+		return nil, err
+	}
+
+	if r.podmanCPUStats != nil {
+		r.podmanCPUStatsLock.Lock()
+		previous, ok := r.podmanCPUStats[container.ID]
+		r.podmanCPUStats[container.ID] = statsJSON.CPUStats
+		r.podmanCPUStatsLock.Unlock()
+
+		var previousCPUStats *dtypes.CPUStats
+		if ok {
+			previousCPUStats = &previous
+		}
+		normalizePodmanStats(statsJSON, previousCPUStats)
+	}
+
+	md, err := ContainerStatsToMetrics(statsJSON, &container, r.config)
+	if err != nil {
+		r.logger.Error(
+			"Could not convert docker containerStats for container id",
+			zap.String("id", container.ID),
+			zap.Error(err),
+		)
+		return nil, err
+	}
+	return md, nil
+}
+
+// forgetPodmanCPUStats removes the CPU stats of the containers no longer monitored.
+func (r *Receiver) forgetPodmanCPUStats(containers []docker.Container) {
+	if r.podmanCPUStats == nil {
+		return
+	}
+
+	monitored := make(map[string]bool, len(containers))
+	for _, container := range containers {
+		monitored[container.ID] = true
+	}
+
+	r.podmanCPUStatsLock.Lock()
+	defer r.podmanCPUStatsLock.Unlock()
+	for id := range r.podmanCPUStats {
+		if !monitored[id] {
+			delete(r.podmanCPUStats, id)
+		}
+	}
+}
+
+// consumeContainerEvent reports the lifecycle events of the monitored containers as logs.
+func (r *Receiver) consumeContainerEvent(event devents.Message, container *docker.Container) {
+	ld, ok := containerEventToLogs(event, container, r.config)
+	if !ok {
+		return
+	}
+	if err := r.logsConsumer.ConsumeLogs(r.runnerCtx, ld); err != nil {
+		r.logger.Error("Failed to consume container event logs", zap.Error(err))
+	}
+}
@@ -22,6 +22,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configtls"
 	"go.opentelemetry.io/collector/testbed/testbed"
 	"go.uber.org/zap"
 )
@@ -91,3 +92,19 @@ func TestErrorsInStart(t *testing.T) {
 
 	require.Nil(t, receiver.Shutdown(context.Background()))
 }
+
+func TestStartWithInvalidTLSConfig(t *testing.T) {
+	config := &Config{
+		Endpoint:           "tcp://localhost:2376",
+		CollectionInterval: 1 * time.Second,
+		TLS: &configtls.TLSClientSetting{
+			TLSSetting: configtls.TLSSetting{CAFile: "/not/a/ca.pem"},
+		},
+	}
+	receiver, err := NewReceiver(context.Background(), zap.NewNop(), config, &testbed.MockMetricConsumer{})
+	require.NoError(t, err)
+
+	err = receiver.Start(context.Background(), componenttest.NewNopHost())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "failed to load TLS config")
+}
@@ -25,6 +25,7 @@ import (
 	batchv1 "k8s.io/api/batch/v1"
 	batchv1beta1 "k8s.io/api/batch/v1beta1"
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/tools/cache"
@@ -46,12 +47,21 @@ const (
 	k8sKeyReplicationControllerUID = "k8s.replicationcontroller.uid"
 	k8sKeyHPAUID                   = "k8s.hpa.uid"
 	k8sKeyResourceQuotaUID         = "k8s.resourcequota.uid"
+	k8sKeyPersistentVolumeUID      = "k8s.persistentvolume.uid"
+	k8sKeyPersistentVolumeClaimUID = "k8s.persistentvolumeclaim.uid"
+	k8sKeyIngressUID               = "k8s.ingress.uid"
 
 	// Resource labels keys for Name.
 	k8sKeyNodeName                  = "k8s.node.name"
 	k8sKeyReplicationControllerName = "k8s.replicationcontroller.name"
 	k8sKeyHPAName                   = "k8s.hpa.name"
 	k8sKeyResourceQuotaName         = "k8s.resourcequota.name"
+	k8sKeyPersistentVolumeName      = "k8s.persistentvolume.name"
+	k8sKeyPersistentVolumeClaimName = "k8s.persistentvolumeclaim.name"
+	k8sKeyIngressName               = "k8s.ingress.name"
+
+	// Resource labels keys for storage.
+	k8sKeyStorageClassName = "k8s.storageclass.name"
 
 	// Kubernetes resource kinds
 	k8sKindCronJob               = "CronJob"
@@ -62,6 +72,9 @@ const (
 	k8sKindReplicaSet            = "ReplicaSet"
 	k8sKindService               = "Service"
 	k8sStatefulSet               = "StatefulSet"
+	k8sKindPersistentVolume      = "PersistentVolume"
+	k8sKindPersistentVolumeClaim = "PersistentVolumeClaim"
+	k8sKindIngress               = "Ingress"
 )
 
 // DataCollector wraps around a metricsStore and a metadaStore exposing
@@ -131,6 +144,10 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForReplicationController(o)
 	case *corev1.ResourceQuota:
 		rm = getMetricsForResourceQuota(o)
+	case *corev1.PersistentVolume:
+		rm = getMetricsForPersistentVolume(o)
+	case *corev1.PersistentVolumeClaim:
+		rm = getMetricsForPersistentVolumeClaim(o)
 	case *appsv1.Deployment:
 		rm = getMetricsForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -145,6 +162,8 @@ func (dc *DataCollector) SyncMetrics(obj interface{}) {
 		rm = getMetricsForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
 		rm = getMetricsForHPA(o)
+	case *networkingv1beta1.Ingress:
+		rm = getMetricsForIngress(o)
 	default:
 		return
 	}
@@ -166,6 +185,10 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*Kubernete
 		km = getMetadataForNode(o)
 	case *corev1.ReplicationController:
 		km = getMetadataForReplicationController(o)
+	case *corev1.PersistentVolume:
+		km = getMetadataForPersistentVolume(o)
+	case *corev1.PersistentVolumeClaim:
+		km = getMetadataForPersistentVolumeClaim(o)
 	case *appsv1.Deployment:
 		km = getMetadataForDeployment(o)
 	case *appsv1.ReplicaSet:
@@ -180,6 +203,8 @@ func (dc *DataCollector) SyncMetadata(obj interface{}) map[ResourceID]*Kubernete
 		km = getMetadataForCronJob(o)
 	case *v2beta1.HorizontalPodAutoscaler:
 		km = getMetadataForHPA(o)
+	case *networkingv1beta1.Ingress:
+		km = getMetadataForIngress(o)
 	}
 
 	return km
@@ -0,0 +1,117 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"sort"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for ingress metadata.
+	ingressHosts = "ingress.hosts"
+	ingressClass = "ingress.class"
+
+	// ingressClassAnnotation is the annotation used to set the class of
+	// ingresses before spec.ingressClassName was introduced.
+	ingressClassAnnotation = "kubernetes.io/ingress.class"
+)
+
+var ingressRulesMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/ingress/rules",
+	Description: "Number of host rules of the ingress",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var ingressLoadBalancersMetric = &metricspb.MetricDescriptor{
+	Name: "k8s/ingress/load_balancer_ingresses",
+	Description: "Number of load balancer ingress points of the ingress" +
+		" (0 until a load balancer has been provisioned)",
+	Type: metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForIngress(ing *networkingv1beta1.Ingress) []*resourceMetrics {
+	return []*resourceMetrics{
+		{
+			resource: getResourceForIngress(ing),
+			metrics: []*metricspb.Metric{
+				{
+					MetricDescriptor: ingressRulesMetric,
+					Timeseries: []*metricspb.TimeSeries{
+						utils.GetInt64TimeSeries(int64(len(ing.Spec.Rules))),
+					},
+				},
+				{
+					MetricDescriptor: ingressLoadBalancersMetric,
+					Timeseries: []*metricspb.TimeSeries{
+						utils.GetInt64TimeSeries(int64(len(ing.Status.LoadBalancer.Ingress))),
+					},
+				},
+			},
+		},
+	}
+}
+
+func getResourceForIngress(ing *networkingv1beta1.Ingress) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyIngressUID:                  string(ing.UID),
+			k8sKeyIngressName:                 ing.Name,
+			conventions.AttributeK8sNamespace: ing.Namespace,
+			conventions.AttributeK8sCluster:   ing.ClusterName,
+		},
+	}
+}
+
+func getMetadataForIngress(ing *networkingv1beta1.Ingress) map[ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&ing.ObjectMeta, k8sKindIngress)
+	if hosts := getIngressHosts(ing); len(hosts) > 0 {
+		rm.metadata[ingressHosts] = strings.Join(hosts, ",")
+	}
+	if class := getIngressClass(ing); class != "" {
+		rm.metadata[ingressClass] = class
+	}
+	return map[ResourceID]*KubernetesMetadata{ResourceID(ing.UID): rm}
+}
+
+// getIngressHosts returns the sorted, distinct hosts of the rules of ing.
+func getIngressHosts(ing *networkingv1beta1.Ingress) []string {
+	seen := map[string]bool{}
+	var hosts []string
+	for _, rule := range ing.Spec.Rules {
+		if rule.Host == "" || seen[rule.Host] {
+			continue
+		}
+		seen[rule.Host] = true
+		hosts = append(hosts, rule.Host)
+	}
+	sort.Strings(hosts)
+	return hosts
+}
+
+func getIngressClass(ing *networkingv1beta1.Ingress) string {
+	if ing.Spec.IngressClassName != nil {
+		return *ing.Spec.IngressClassName
+	}
+	return ing.Annotations[ingressClassAnnotation]
+}
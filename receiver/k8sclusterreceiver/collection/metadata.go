@@ -137,6 +137,22 @@ func GetMetadataUpdate(old, new map[ResourceID]*KubernetesMetadata) []*MetadataU
 	return out
 }
 
+// GetMetadataRemoval returns updates removing all the metadata of the
+// resources, e.g. once they have been deleted.
+func GetMetadataRemoval(old map[ResourceID]*KubernetesMetadata) []*MetadataUpdate {
+	var out []*MetadataUpdate
+
+	for id, km := range old {
+		out = append(out, &MetadataUpdate{
+			ResourceIDKey: km.resourceIDKey,
+			ResourceID:    id,
+			MetadataDelta: MetadataDelta{MetadataToRemove: km.metadata},
+		})
+	}
+
+	return out
+}
+
 // MetadataDelta keeps track of changes to metadata on resources.
 // The fields on this struct should help determine if there have
 // been changes to resource metadata such as Kubernetes labels.
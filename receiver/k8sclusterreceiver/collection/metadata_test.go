@@ -201,3 +201,24 @@ func TestGetPropertiesDelta(t *testing.T) {
 		})
 	}
 }
+
+func TestGetMetadataRemoval(t *testing.T) {
+	old := map[ResourceID]*KubernetesMetadata{
+		"uid-1": {
+			resourceIDKey: "k8s.pod.uid",
+			resourceID:    "uid-1",
+			metadata:      map[string]string{"foo": "bar"},
+		},
+	}
+
+	assert.Equal(t, []*MetadataUpdate{
+		{
+			ResourceIDKey: "k8s.pod.uid",
+			ResourceID:    "uid-1",
+			MetadataDelta: MetadataDelta{
+				MetadataToRemove: map[string]string{"foo": "bar"},
+			},
+		},
+	}, GetMetadataRemoval(old))
+	assert.Nil(t, GetMetadataRemoval(nil))
+}
@@ -31,14 +31,53 @@ type metadataStore struct {
 	replicaSets cache.Store
 }
 
-// setupStore tracks metadata of services, jobs and replicasets.
+// setupStore tracks metadata of services, jobs and replicasets. The stores
+// of the informers watching a kind in different namespaces are combined.
 func (ms *metadataStore) setupStore(o runtime.Object, store cache.Store) {
 	switch o.(type) {
 	case *corev1.Service:
-		ms.services = store
+		ms.services = combineStores(ms.services, store)
 	case *batchv1.Job:
-		ms.jobs = store
+		ms.jobs = combineStores(ms.jobs, store)
 	case *appsv1.ReplicaSet:
-		ms.replicaSets = store
+		ms.replicaSets = combineStores(ms.replicaSets, store)
 	}
 }
+
+func combineStores(existing cache.Store, store cache.Store) cache.Store {
+	switch s := existing.(type) {
+	case nil:
+		return store
+	case *multiStore:
+		s.stores = append(s.stores, store)
+		return s
+	default:
+		return &multiStore{stores: []cache.Store{existing, store}}
+	}
+}
+
+// multiStore is a read-only view of the stores of informers watching the
+// same kind in different namespaces. Only the methods used to look up
+// metadata are implemented.
+type multiStore struct {
+	cache.Store
+	stores []cache.Store
+}
+
+func (ms *multiStore) List() []interface{} {
+	var out []interface{}
+	for _, store := range ms.stores {
+		out = append(out, store.List()...)
+	}
+	return out
+}
+
+func (ms *multiStore) GetByKey(key string) (interface{}, bool, error) {
+	for _, store := range ms.stores {
+		item, exists, err := store.GetByKey(key)
+		if err != nil || exists {
+			return item, exists, err
+		}
+	}
+	return nil, false, nil
+}
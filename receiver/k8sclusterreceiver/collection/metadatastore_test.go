@@ -0,0 +1,53 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/testutils"
+)
+
+func TestMetadataStoreCombinesStores(t *testing.T) {
+	ms := &metadataStore{}
+
+	store1 := &testutils.MockStore{Cache: map[string]interface{}{"ns-1/svc-1": "svc-1"}}
+	ms.setupStore(&corev1.Service{}, store1)
+	assert.Same(t, store1, ms.services)
+
+	store2 := &testutils.MockStore{Cache: map[string]interface{}{"ns-2/svc-2": "svc-2"}}
+	store3 := &testutils.MockStore{Cache: map[string]interface{}{"ns-3/svc-3": "svc-3"}}
+	ms.setupStore(&corev1.Service{}, store2)
+	ms.setupStore(&corev1.Service{}, store3)
+
+	assert.ElementsMatch(t, []interface{}{"svc-1", "svc-2", "svc-3"}, ms.services.List())
+
+	item, exists, err := ms.services.GetByKey("ns-2/svc-2")
+	require.NoError(t, err)
+	assert.True(t, exists)
+	assert.Equal(t, "svc-2", item)
+
+	_, exists, err = ms.services.GetByKey("ns-4/svc-4")
+	require.NoError(t, err)
+	assert.False(t, exists)
+
+	store2.WantErr = true
+	_, _, err = ms.services.GetByKey("ns-3/svc-3")
+	require.Error(t, err)
+}
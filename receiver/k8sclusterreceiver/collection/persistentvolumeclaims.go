@@ -0,0 +1,133 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+var persistentVolumeClaimCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/capacity",
+	Description: "Storage capacity of the volume bound to the persistent volume claim",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimRequestedMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/requested_storage",
+	Description: "Storage requested by the persistent volume claim",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimPhaseMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/phase",
+	Description: "Current phase of the persistent volume claim (1 - Pending, 2 - Bound, 3 - Lost)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumeClaimBoundMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolumeclaim/bound",
+	Description: "Whether the persistent volume claim is bound to a volume (1 - bound, 0 - not bound)",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) []*resourceMetrics {
+	bound := int64(0)
+	if pvc.Status.Phase == corev1.ClaimBound {
+		bound = 1
+	}
+
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumeClaimPhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(persistentVolumeClaimPhaseToInt(pvc.Status.Phase))),
+			},
+		},
+		{
+			MetricDescriptor: persistentVolumeClaimBoundMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(bound),
+			},
+		},
+	}
+
+	if requested, ok := pvc.Spec.Resources.Requests[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeClaimRequestedMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(requested.Value()),
+			},
+		})
+	}
+
+	if capacity, ok := pvc.Status.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeClaimCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolumeClaim(pvc),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeClaimUID:    string(pvc.UID),
+			k8sKeyPersistentVolumeClaimName:   pvc.Name,
+			conventions.AttributeK8sNamespace: pvc.Namespace,
+			conventions.AttributeK8sCluster:   pvc.ClusterName,
+		},
+	}
+}
+
+func persistentVolumeClaimPhaseToInt(phase corev1.PersistentVolumeClaimPhase) int32 {
+	switch phase {
+	case corev1.ClaimPending:
+		return 1
+	case corev1.ClaimBound:
+		return 2
+	case corev1.ClaimLost:
+		return 3
+	default:
+		return 0
+	}
+}
+
+func getMetadataForPersistentVolumeClaim(pvc *corev1.PersistentVolumeClaim) map[ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&pvc.ObjectMeta, k8sKindPersistentVolumeClaim)
+	if pvc.Spec.StorageClassName != nil && *pvc.Spec.StorageClassName != "" {
+		rm.metadata[k8sKeyStorageClassName] = *pvc.Spec.StorageClassName
+	}
+	if pvc.Spec.VolumeName != "" {
+		rm.metadata[k8sKeyPersistentVolumeName] = pvc.Spec.VolumeName
+	}
+	return map[ResourceID]*KubernetesMetadata{ResourceID(pvc.UID): rm}
+}
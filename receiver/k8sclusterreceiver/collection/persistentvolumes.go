@@ -0,0 +1,115 @@
+// Copyright 2020 OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//      http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package collection
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"go.opentelemetry.io/collector/translator/conventions"
+	corev1 "k8s.io/api/core/v1"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/utils"
+)
+
+const (
+	// Keys for persistent volume metadata.
+	persistentVolumeReclaimPolicy = "persistentvolume.reclaim_policy"
+	persistentVolumeClaimName     = "persistentvolumeclaim.name"
+	persistentVolumeClaimNs       = "persistentvolumeclaim.namespace"
+)
+
+var persistentVolumeCapacityMetric = &metricspb.MetricDescriptor{
+	Name:        "k8s/persistentvolume/capacity",
+	Description: "Storage capacity of the persistent volume",
+	Unit:        "By",
+	Type:        metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+var persistentVolumePhaseMetric = &metricspb.MetricDescriptor{
+	Name: "k8s/persistentvolume/phase",
+	Description: "Current phase of the persistent volume (1 - Pending, 2 - Available, " +
+		"3 - Bound, 4 - Released, 5 - Failed)",
+	Type: metricspb.MetricDescriptor_GAUGE_INT64,
+}
+
+func getMetricsForPersistentVolume(pv *corev1.PersistentVolume) []*resourceMetrics {
+	metrics := []*metricspb.Metric{
+		{
+			MetricDescriptor: persistentVolumePhaseMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(int64(persistentVolumePhaseToInt(pv.Status.Phase))),
+			},
+		},
+	}
+
+	if capacity, ok := pv.Spec.Capacity[corev1.ResourceStorage]; ok {
+		metrics = append(metrics, &metricspb.Metric{
+			MetricDescriptor: persistentVolumeCapacityMetric,
+			Timeseries: []*metricspb.TimeSeries{
+				utils.GetInt64TimeSeries(capacity.Value()),
+			},
+		})
+	}
+
+	return []*resourceMetrics{
+		{
+			resource: getResourceForPersistentVolume(pv),
+			metrics:  metrics,
+		},
+	}
+}
+
+func getResourceForPersistentVolume(pv *corev1.PersistentVolume) *resourcepb.Resource {
+	return &resourcepb.Resource{
+		Type: k8sType,
+		Labels: map[string]string{
+			k8sKeyPersistentVolumeUID:       string(pv.UID),
+			k8sKeyPersistentVolumeName:      pv.Name,
+			conventions.AttributeK8sCluster: pv.ClusterName,
+		},
+	}
+}
+
+func persistentVolumePhaseToInt(phase corev1.PersistentVolumePhase) int32 {
+	switch phase {
+	case corev1.VolumePending:
+		return 1
+	case corev1.VolumeAvailable:
+		return 2
+	case corev1.VolumeBound:
+		return 3
+	case corev1.VolumeReleased:
+		return 4
+	case corev1.VolumeFailed:
+		return 5
+	default:
+		return 0
+	}
+}
+
+func getMetadataForPersistentVolume(pv *corev1.PersistentVolume) map[ResourceID]*KubernetesMetadata {
+	rm := getGenericMetadata(&pv.ObjectMeta, k8sKindPersistentVolume)
+	if pv.Spec.StorageClassName != "" {
+		rm.metadata[k8sKeyStorageClassName] = pv.Spec.StorageClassName
+	}
+	if pv.Spec.PersistentVolumeReclaimPolicy != "" {
+		rm.metadata[persistentVolumeReclaimPolicy] = string(pv.Spec.PersistentVolumeReclaimPolicy)
+	}
+	if claim := pv.Spec.ClaimRef; claim != nil {
+		rm.metadata[persistentVolumeClaimName] = claim.Name
+		rm.metadata[persistentVolumeClaimNs] = claim.Namespace
+	}
+	return map[ResourceID]*KubernetesMetadata{ResourceID(pv.UID): rm}
+}
@@ -37,10 +37,40 @@ type Config struct {
 	// List of exporters to which metadata from this receiver should be forwarded to.
 	MetadataExporters []string `mapstructure:"metadata_exporters"`
 
+	// Whether to emit metadata updates as logs when the receiver is part of
+	// a logs pipeline.
+	MetadataLogs bool `mapstructure:"metadata_logs"`
+
+	// Kinds of resources to watch, e.g. pods or deployments. All supported
+	// kinds are watched if empty.
+	ResourceKinds []string `mapstructure:"resource_kinds"`
+	// Namespaces to watch namespaced resources in. Resources of all namespaces
+	// are watched if empty.
+	Namespaces []string `mapstructure:"namespaces"`
+	// Label selector restricting the watched namespaced resources.
+	// Cluster-scoped resources, such as nodes, are not restricted.
+	LabelSelector string `mapstructure:"label_selector"`
+
+	// Configuration of the Kubernetes events reported as logs.
+	Events EventsConfig `mapstructure:"events"`
+
 	// For mocking.
 	makeClient func(apiConf k8sconfig.APIConfig) (k8s.Interface, error)
 }
 
+// EventsConfig defines which Kubernetes events are reported as logs when the
+// receiver is part of a logs pipeline.
+type EventsConfig struct {
+	// Whether to report events.
+	Enabled bool `mapstructure:"enabled"`
+	// Namespaces to watch events in. Events of all namespaces are watched
+	// if empty.
+	Namespaces []string `mapstructure:"namespaces"`
+	// Types of events to report, Normal and/or Warning. All events are
+	// reported if empty.
+	Types []string `mapstructure:"types"`
+}
+
 func (cfg *Config) getK8sClient() (k8s.Interface, error) {
 	if cfg.makeClient == nil {
 		cfg.makeClient = k8sconfig.MakeClient
@@ -57,6 +57,12 @@ func TestLoadConfig(t *testing.T) {
 			CollectionInterval:         30 * time.Second,
 			NodeConditionTypesToReport: []string{"Ready", "MemoryPressure"},
 			MetadataExporters:          []string{"exampleexporter"},
+			MetadataLogs:               false,
+			Events: EventsConfig{
+				Enabled:    true,
+				Namespaces: []string{"default", "kube-system"},
+				Types:      []string{"Warning"},
+			},
 			APIConfig: k8sconfig.APIConfig{
 				AuthType: k8sconfig.AuthTypeServiceAccount,
 			},
@@ -74,5 +80,9 @@ func TestLoadConfig(t *testing.T) {
 			APIConfig: k8sconfig.APIConfig{
 				AuthType: k8sconfig.AuthTypeServiceAccount,
 			},
+			MetadataLogs:  true,
+			ResourceKinds: []string{"pods", "deployments", "nodes"},
+			Namespaces:    []string{"default", "monitoring"},
+			LabelSelector: "team=observability",
 		})
 }
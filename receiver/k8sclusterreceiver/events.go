@@ -0,0 +1,299 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+const (
+	// eventLogName is the name of the log records of Kubernetes events.
+	eventLogName = "k8s.event"
+
+	// Resource attribute keys describing the object involved in an event.
+	k8sKeyObjectKind       = "k8s.object.kind"
+	k8sKeyObjectName       = "k8s.object.name"
+	k8sKeyObjectUID        = "k8s.object.uid"
+	k8sKeyObjectAPIVersion = "k8s.object.api_version"
+	k8sKeyObjectFieldPath  = "k8s.object.fieldpath"
+	k8sKeyNodeName         = "k8s.node.name"
+	k8sKeyNodeUID          = "k8s.node.uid"
+
+	// Attribute keys of event log records.
+	eventKeyName            = "k8s.event.name"
+	eventKeyUID             = "k8s.event.uid"
+	eventKeyReason          = "k8s.event.reason"
+	eventKeyType            = "k8s.event.type"
+	eventKeyCount           = "k8s.event.count"
+	eventKeyAction          = "k8s.event.action"
+	eventKeyStartTime       = "k8s.event.start_time"
+	eventKeySourceComponent = "k8s.event.source.component"
+	eventKeySourceHost      = "k8s.event.source.host"
+)
+
+// involvedObjectKeys maps kinds of objects involved in events to the resource
+// attribute keys of their name and UID.
+var involvedObjectKeys = map[string][2]string{
+	"Pod":         {conventions.AttributeK8sPod, conventions.AttributeK8sPodUID},
+	"Node":        {k8sKeyNodeName, k8sKeyNodeUID},
+	"Deployment":  {conventions.AttributeK8sDeployment, conventions.AttributeK8sDeploymentUID},
+	"ReplicaSet":  {conventions.AttributeK8sReplicaSet, conventions.AttributeK8sReplicaSetUID},
+	"StatefulSet": {conventions.AttributeK8sStatefulSet, conventions.AttributeK8sStatefulSetUID},
+	"DaemonSet":   {conventions.AttributeK8sDaemonSet, conventions.AttributeK8sDaemonSetUID},
+	"Job":         {conventions.AttributeK8sJob, conventions.AttributeK8sJobUID},
+	"CronJob":     {conventions.AttributeK8sCronJob, conventions.AttributeK8sCronJobUID},
+}
+
+// eventsWatcher watches Kubernetes events and reports them as logs.
+type eventsWatcher struct {
+	client kubernetes.Interface
+	logger *zap.Logger
+	config EventsConfig
+	types  map[string]bool
+
+	mu sync.Mutex
+	// reported holds the resource version of the last reported revision of
+	// each event. It is seeded with the revisions of the events that exist
+	// when the watcher starts, so that the events still held by the API
+	// server are not reported again when the collector restarts, and is kept
+	// across restarts of the informers so that the events listed again are
+	// not reported twice.
+	reported map[types.UID]string
+}
+
+// newEventsWatcher creates a watcher of the events matching config.
+func newEventsWatcher(logger *zap.Logger, client kubernetes.Interface, config EventsConfig) (*eventsWatcher, error) {
+	eventTypes := map[string]bool{}
+	for _, t := range config.Types {
+		if t != corev1.EventTypeNormal && t != corev1.EventTypeWarning {
+			return nil, fmt.Errorf("invalid event type %q, must be one of %s or %s",
+				t, corev1.EventTypeNormal, corev1.EventTypeWarning)
+		}
+		eventTypes[t] = true
+	}
+
+	return &eventsWatcher{
+		client:   client,
+		logger:   logger,
+		config:   config,
+		types:    eventTypes,
+		reported: map[types.UID]string{},
+	}, nil
+}
+
+// start starts watching events until ctx is done. Every reported event is
+// passed to consume. Only the events that occur or recur after start is
+// called are reported.
+func (ew *eventsWatcher) start(ctx context.Context, consume func(ld pdata.Logs)) {
+	handler := cache.ResourceEventHandlerFuncs{
+		AddFunc: func(obj interface{}) {
+			ew.report(obj, consume)
+		},
+		UpdateFunc: func(_, newObj interface{}) {
+			// Events are updated when they recur, with their count incremented.
+			ew.report(newObj, consume)
+		},
+		DeleteFunc: ew.onDelete,
+	}
+
+	namespaces := ew.config.Namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{corev1.NamespaceAll}
+	}
+
+	for _, ns := range namespaces {
+		ew.seed(ctx, ns)
+		factory := informers.NewSharedInformerFactoryWithOptions(ew.client, 0, informers.WithNamespace(ns))
+		factory.Core().V1().Events().Informer().AddEventHandler(handler)
+		factory.Start(ctx.Done())
+	}
+}
+
+// seed records the current revision of the events of namespace as reported.
+// The informers list the events again when they start, and only the events
+// created or updated since, whose resource version changed, are reported.
+func (ew *eventsWatcher) seed(ctx context.Context, namespace string) {
+	events, err := ew.client.CoreV1().Events(namespace).List(ctx, metav1.ListOptions{})
+	if err != nil {
+		ew.logger.Warn("Failed to list the existing events, they will be reported again",
+			zap.String("namespace", namespace), zap.Error(err))
+		return
+	}
+
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	for i := range events.Items {
+		ev := &events.Items[i]
+		ew.reported[ev.UID] = ev.ResourceVersion
+	}
+}
+
+func (ew *eventsWatcher) onDelete(obj interface{}) {
+	if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = deleted.Obj
+	}
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		return
+	}
+
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	delete(ew.reported, ev.UID)
+}
+
+func (ew *eventsWatcher) report(obj interface{}, consume func(ld pdata.Logs)) {
+	ev, ok := obj.(*corev1.Event)
+	if !ok {
+		ew.logger.Error("object received was not of type v1.Event",
+			zap.String("type", fmt.Sprintf("%T", obj)))
+		return
+	}
+
+	if !ew.shouldReport(ev) {
+		return
+	}
+
+	consume(eventToLogs(ev))
+}
+
+// shouldReport returns whether ev matches the configured types and its
+// current revision was not reported yet.
+func (ew *eventsWatcher) shouldReport(ev *corev1.Event) bool {
+	if len(ew.types) > 0 && !ew.types[ev.Type] {
+		return false
+	}
+
+	ew.mu.Lock()
+	defer ew.mu.Unlock()
+	if rv, ok := ew.reported[ev.UID]; ok && rv == ev.ResourceVersion {
+		return false
+	}
+	ew.reported[ev.UID] = ev.ResourceVersion
+	return true
+}
+
+// eventToLogs converts a Kubernetes event to logs with a single log record.
+// The object involved in the event is described by resource attributes.
+func eventToLogs(ev *corev1.Event) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(1)
+	rl := rls.At(0)
+
+	resource := rl.Resource()
+	resource.InitEmpty()
+	resourceAttrs := resource.Attributes()
+
+	obj := ev.InvolvedObject
+	resourceAttrs.InsertString(k8sKeyObjectKind, obj.Kind)
+	resourceAttrs.InsertString(k8sKeyObjectName, obj.Name)
+	resourceAttrs.InsertString(k8sKeyObjectUID, string(obj.UID))
+	resourceAttrs.InsertString(k8sKeyObjectAPIVersion, obj.APIVersion)
+	if obj.FieldPath != "" {
+		resourceAttrs.InsertString(k8sKeyObjectFieldPath, obj.FieldPath)
+	}
+	if obj.Namespace != "" {
+		resourceAttrs.InsertString(conventions.AttributeK8sNamespace, obj.Namespace)
+	}
+	if keys, ok := involvedObjectKeys[obj.Kind]; ok {
+		resourceAttrs.InsertString(keys[0], obj.Name)
+		resourceAttrs.InsertString(keys[1], string(obj.UID))
+	}
+
+	ills := rl.InstrumentationLibraryLogs()
+	ills.Resize(1)
+	lrs := ills.At(0).Logs()
+	lrs.Resize(1)
+
+	lr := lrs.At(0)
+	lr.InitEmpty()
+	lr.SetName(eventLogName)
+	lr.SetTimestamp(pdata.TimestampUnixNano(eventTimestamp(ev)))
+	lr.SetSeverityText(ev.Type)
+	if ev.Type == corev1.EventTypeWarning {
+		lr.SetSeverityNumber(pdata.SeverityNumberWARN)
+	} else {
+		lr.SetSeverityNumber(pdata.SeverityNumberINFO)
+	}
+	lr.Body().SetStringVal(ev.Message)
+
+	attrs := lr.Attributes()
+	attrs.InsertString(eventKeyName, ev.Name)
+	attrs.InsertString(eventKeyUID, string(ev.UID))
+	attrs.InsertString(eventKeyReason, ev.Reason)
+	attrs.InsertString(eventKeyType, ev.Type)
+	attrs.InsertInt(eventKeyCount, int64(eventCount(ev)))
+	if ev.Action != "" {
+		attrs.InsertString(eventKeyAction, ev.Action)
+	}
+	if !ev.FirstTimestamp.IsZero() {
+		attrs.InsertString(eventKeyStartTime, ev.FirstTimestamp.UTC().Format(time.RFC3339))
+	}
+	if component := eventSourceComponent(ev); component != "" {
+		attrs.InsertString(eventKeySourceComponent, component)
+	}
+	if ev.Source.Host != "" {
+		attrs.InsertString(eventKeySourceHost, ev.Source.Host)
+	}
+
+	return ld
+}
+
+// eventTimestamp returns the time of the last occurrence of ev in nanoseconds.
+func eventTimestamp(ev *corev1.Event) int64 {
+	switch {
+	case !ev.LastTimestamp.IsZero():
+		return ev.LastTimestamp.UnixNano()
+	case ev.Series != nil && !ev.Series.LastObservedTime.IsZero():
+		return ev.Series.LastObservedTime.UnixNano()
+	case !ev.EventTime.IsZero():
+		return ev.EventTime.UnixNano()
+	default:
+		return ev.CreationTimestamp.UnixNano()
+	}
+}
+
+// eventCount returns the number of occurrences of ev. Events created through
+// the events.k8s.io API report it in their series instead.
+func eventCount(ev *corev1.Event) int32 {
+	if ev.Series != nil && ev.Series.Count > ev.Count {
+		return ev.Series.Count
+	}
+	if ev.Count == 0 {
+		return 1
+	}
+	return ev.Count
+}
+
+func eventSourceComponent(ev *corev1.Event) string {
+	if ev.Source.Component != "" {
+		return ev.Source.Component
+	}
+	return ev.ReportingController
+}
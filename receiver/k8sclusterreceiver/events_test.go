@@ -0,0 +1,178 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func newEvent(name, namespace, eventType string) *corev1.Event {
+	return &corev1.Event{
+		ObjectMeta: v1.ObjectMeta{
+			Name:            name,
+			Namespace:       namespace,
+			UID:             types.UID(name + "-uid"),
+			ResourceVersion: "1",
+		},
+		InvolvedObject: corev1.ObjectReference{
+			Kind:       "Pod",
+			Namespace:  namespace,
+			Name:       "pod-1",
+			UID:        "pod-1-uid",
+			APIVersion: "v1",
+			FieldPath:  "spec.containers{app}",
+		},
+		Reason:         "BackOff",
+		Message:        "Back-off restarting failed container",
+		Type:           eventType,
+		Count:          3,
+		FirstTimestamp: v1.NewTime(time.Unix(100, 0)),
+		LastTimestamp:  v1.NewTime(time.Unix(200, 0)),
+		Source:         corev1.EventSource{Component: "kubelet", Host: "node-1"},
+	}
+}
+
+func TestEventToLogs(t *testing.T) {
+	ld := eventToLogs(newEvent("event-1", "default", corev1.EventTypeWarning))
+	require.Equal(t, 1, ld.ResourceLogs().Len())
+	rl := ld.ResourceLogs().At(0)
+
+	resourceAttrs := rl.Resource().Attributes()
+	assertStringAttr(t, resourceAttrs, k8sKeyObjectKind, "Pod")
+	assertStringAttr(t, resourceAttrs, k8sKeyObjectName, "pod-1")
+	assertStringAttr(t, resourceAttrs, k8sKeyObjectUID, "pod-1-uid")
+	assertStringAttr(t, resourceAttrs, k8sKeyObjectFieldPath, "spec.containers{app}")
+	assertStringAttr(t, resourceAttrs, "k8s.namespace.name", "default")
+	assertStringAttr(t, resourceAttrs, "k8s.pod.name", "pod-1")
+	assertStringAttr(t, resourceAttrs, "k8s.pod.uid", "pod-1-uid")
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, eventLogName, lr.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(200*time.Second), lr.Timestamp())
+	assert.Equal(t, "Warning", lr.SeverityText())
+	assert.Equal(t, pdata.SeverityNumberWARN, lr.SeverityNumber())
+	assert.Equal(t, "Back-off restarting failed container", lr.Body().StringVal())
+
+	attrs := lr.Attributes()
+	assertStringAttr(t, attrs, eventKeyName, "event-1")
+	assertStringAttr(t, attrs, eventKeyReason, "BackOff")
+	assertStringAttr(t, attrs, eventKeyType, "Warning")
+	assertStringAttr(t, attrs, eventKeyStartTime, "1970-01-01T00:01:40Z")
+	assertStringAttr(t, attrs, eventKeySourceComponent, "kubelet")
+	assertStringAttr(t, attrs, eventKeySourceHost, "node-1")
+	count, ok := attrs.Get(eventKeyCount)
+	require.True(t, ok)
+	assert.EqualValues(t, 3, count.IntVal())
+}
+
+func TestNewEventsWatcherInvalidType(t *testing.T) {
+	_, err := newEventsWatcher(zap.NewNop(), fake.NewSimpleClientset(), EventsConfig{Types: []string{"Error"}})
+	require.Error(t, err)
+}
+
+func TestEventsWatcherShouldReport(t *testing.T) {
+	ew, err := newEventsWatcher(zap.NewNop(), fake.NewSimpleClientset(), EventsConfig{Types: []string{"Warning"}})
+	require.NoError(t, err)
+
+	assert.False(t, ew.shouldReport(newEvent("normal", "default", corev1.EventTypeNormal)))
+
+	warning := newEvent("warning", "default", corev1.EventTypeWarning)
+	assert.True(t, ew.shouldReport(warning))
+	// The same revision is only reported once.
+	assert.False(t, ew.shouldReport(warning))
+
+	recurred := warning.DeepCopy()
+	recurred.ResourceVersion = "2"
+	recurred.Count++
+	assert.True(t, ew.shouldReport(recurred))
+
+	// Deleted events are forgotten.
+	ew.onDelete(recurred)
+	assert.True(t, ew.shouldReport(recurred))
+}
+
+func TestEventsWatcher(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	createEvent := func(ev *corev1.Event) {
+		_, err := client.CoreV1().Events(ev.Namespace).Create(context.Background(), ev, v1.CreateOptions{})
+		require.NoError(t, err)
+	}
+	// The events that exist when the watcher starts are not reported, even
+	// if they occurred recently.
+	existing := newEvent("event-0", "default", corev1.EventTypeWarning)
+	existing.LastTimestamp = v1.Now()
+	createEvent(existing)
+
+	ew, err := newEventsWatcher(zap.NewNop(), client, EventsConfig{
+		Enabled:    true,
+		Namespaces: []string{"default"},
+		Types:      []string{"Warning"},
+	})
+	require.NoError(t, err)
+
+	sink := &exportertest.SinkLogsExporter{}
+	consume := func(ld pdata.Logs) {
+		require.NoError(t, sink.ConsumeLogs(context.Background(), ld))
+	}
+	waitForLogs := func(n int) {
+		require.Eventually(t, func() bool {
+			return sink.LogRecordsCount() == n
+		}, 10*time.Second, 10*time.Millisecond)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	ew.start(ctx, consume)
+	createEvent(newEvent("event-1", "default", corev1.EventTypeWarning))
+	createEvent(newEvent("event-2", "other", corev1.EventTypeWarning))
+	createEvent(newEvent("event-3", "default", corev1.EventTypeNormal))
+	waitForLogs(1)
+
+	// Existing events are reported when they recur.
+	recurred := existing.DeepCopy()
+	recurred.ResourceVersion = "2"
+	recurred.Count++
+	_, err = client.CoreV1().Events(recurred.Namespace).Update(context.Background(), recurred, v1.UpdateOptions{})
+	require.NoError(t, err)
+	waitForLogs(2)
+	cancel()
+
+	// Events already reported are not reported again on restart.
+	ctx, cancel = context.WithCancel(context.Background())
+	defer cancel()
+	ew.start(ctx, consume)
+	createEvent(newEvent("event-4", "default", corev1.EventTypeWarning))
+	waitForLogs(3)
+
+	var names []string
+	for _, ld := range sink.AllLogs() {
+		attrs := ld.ResourceLogs().At(0).InstrumentationLibraryLogs().At(0).Logs().At(0).Attributes()
+		name, ok := attrs.Get(eventKeyName)
+		require.True(t, ok)
+		names = append(names, name.StringVal())
+	}
+	assert.Equal(t, []string{"event-1", "event-0", "event-4"}, names)
+}
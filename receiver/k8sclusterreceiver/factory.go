@@ -16,6 +16,7 @@ package k8sclusterreceiver
 
 import (
 	"context"
+	"sync"
 	"time"
 
 	"go.opentelemetry.io/collector/component"
@@ -36,6 +37,11 @@ const (
 
 var defaultNodeConditionsToReport = []string{"Ready"}
 
+// receivers holds the receiver created for each config so that the same
+// instance is used by all data types.
+var receivers = map[*Config]*kubernetesReceiver{}
+var receiversLock sync.Mutex
+
 func createDefaultConfig() configmodels.Receiver {
 	return &Config{
 		ReceiverSettings: configmodels.ReceiverSettings{
@@ -47,19 +53,50 @@ func createDefaultConfig() configmodels.Receiver {
 		APIConfig: k8sconfig.APIConfig{
 			AuthType: k8sconfig.AuthTypeServiceAccount,
 		},
+		MetadataLogs: true,
 	}
 }
 
 func createMetricsReceiver(
 	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer) (component.MetricsReceiver, error) {
-	rCfg := cfg.(*Config)
+	r, err := getOrCreateReceiver(params, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.metricsConsumer = consumer
+	return r, nil
+}
+
+func createLogsReceiver(
+	_ context.Context, params component.ReceiverCreateParams, cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer) (component.LogsReceiver, error) {
+	r, err := getOrCreateReceiver(params, cfg.(*Config))
+	if err != nil {
+		return nil, err
+	}
+	r.logsConsumer = consumer
+	return r, nil
+}
+
+func getOrCreateReceiver(params component.ReceiverCreateParams, rCfg *Config) (*kubernetesReceiver, error) {
+	receiversLock.Lock()
+	defer receiversLock.Unlock()
+
+	if r, ok := receivers[rCfg]; ok {
+		return r, nil
+	}
 
 	k8sClient, err := rCfg.getK8sClient()
 	if err != nil {
 		return nil, err
 	}
-	return newReceiver(params.Logger, rCfg, consumer, k8sClient)
+	r, err := newReceiver(params.Logger, rCfg, k8sClient)
+	if err != nil {
+		return nil, err
+	}
+	receivers[rCfg] = r
+	return r, nil
 }
 
 // NewFactory creates a factory for k8s_cluster receiver.
@@ -67,5 +104,6 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
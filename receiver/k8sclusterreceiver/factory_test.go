@@ -25,6 +25,7 @@ import (
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/kubernetes/fake"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/k8sconfig"
 )
@@ -47,6 +48,7 @@ func TestFactory(t *testing.T) {
 		APIConfig: k8sconfig.APIConfig{
 			AuthType: k8sconfig.AuthTypeServiceAccount,
 		},
+		MetadataLogs: true,
 	}, rCfg)
 
 	r, err := f.CreateTraceReceiver(
@@ -66,7 +68,7 @@ func TestFactory(t *testing.T) {
 
 	// Override for tests.
 	rCfg.makeClient = func(apiConf k8sconfig.APIConfig) (kubernetes.Interface, error) {
-		return nil, nil
+		return fake.NewSimpleClientset(), nil
 	}
 	r, err = f.CreateMetricsReceiver(
 		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
@@ -75,6 +77,14 @@ func TestFactory(t *testing.T) {
 	require.NoError(t, err)
 	require.NotNil(t, r)
 
+	// The same receiver is used for logs.
+	lr, err := f.CreateLogsReceiver(
+		context.Background(), component.ReceiverCreateParams{Logger: zap.NewNop()},
+		rCfg, &exportertest.SinkLogsExporter{},
+	)
+	require.NoError(t, err)
+	require.Same(t, r, lr)
+
 	// Test metadata exporters setup.
 	ctx := context.Background()
 	require.NoError(t, r.Start(ctx, nopHostWithExporters{}))
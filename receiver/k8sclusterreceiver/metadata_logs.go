@@ -0,0 +1,106 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
+)
+
+const (
+	// metadataLogName is the name of the log records describing metadata changes.
+	metadataLogName = "k8s.metadata"
+
+	// Attribute keys of metadata log records.
+	metadataOperationKey = "k8s.metadata.operation"
+	resourceKindKey      = "k8s.resource.kind"
+	metadataAddedKey     = "k8s.metadata.added"
+	metadataRemovedKey   = "k8s.metadata.removed"
+	metadataUpdatedKey   = "k8s.metadata.updated"
+)
+
+// metadataOperation is the change to a resource that caused its metadata to change.
+type metadataOperation string
+
+const (
+	metadataAdded   metadataOperation = "added"
+	metadataUpdated metadataOperation = "updated"
+	metadataDeleted metadataOperation = "deleted"
+)
+
+// metadataUpdatesToLogs converts metadata updates to logs with one log record
+// per updated resource. The resource ID is set as a resource attribute and the
+// changed metadata as log record attributes.
+func metadataUpdatesToLogs(
+	op metadataOperation, updates []*collection.MetadataUpdate, ts time.Time) pdata.Logs {
+	ld := pdata.NewLogs()
+	rls := ld.ResourceLogs()
+	rls.Resize(len(updates))
+
+	for i, update := range updates {
+		rl := rls.At(i)
+		resource := rl.Resource()
+		resource.InitEmpty()
+		resource.Attributes().InsertString(update.ResourceIDKey, string(update.ResourceID))
+
+		ills := rl.InstrumentationLibraryLogs()
+		ills.Resize(1)
+		lrs := ills.At(0).Logs()
+		lrs.Resize(1)
+
+		lr := lrs.At(0)
+		lr.InitEmpty()
+		lr.SetName(metadataLogName)
+		lr.SetTimestamp(pdata.TimestampUnixNano(ts.UnixNano()))
+
+		attrs := lr.Attributes()
+		attrs.InsertString(metadataOperationKey, string(op))
+		attrs.InsertString(resourceKindKey, resourceKind(update.ResourceIDKey))
+		insertStringMap(attrs, metadataAddedKey, update.MetadataToAdd)
+		insertStringMap(attrs, metadataRemovedKey, update.MetadataToRemove)
+		insertStringMap(attrs, metadataUpdatedKey, update.MetadataToUpdate)
+	}
+
+	return ld
+}
+
+// resourceKind returns the kind of resource from the key of its ID
+// (e.g. "pod" for "k8s.pod.uid" and "container" for "container.id").
+func resourceKind(resourceIDKey string) string {
+	kind := strings.TrimPrefix(resourceIDKey, "k8s.")
+	kind = strings.TrimSuffix(kind, ".uid")
+	return strings.TrimSuffix(kind, ".id")
+}
+
+// insertStringMap inserts m as a map value under key, unless it is empty.
+func insertStringMap(attrs pdata.AttributeMap, key string, m map[string]string) {
+	if len(m) == 0 {
+		return
+	}
+
+	am := pdata.NewAttributeMap()
+	am.InitEmptyWithCapacity(len(m))
+	for k, v := range m {
+		am.InsertString(k, v)
+	}
+
+	val := pdata.NewAttributeValueMap()
+	val.SetMapVal(am)
+	attrs.Insert(key, val)
+}
@@ -0,0 +1,91 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
+)
+
+func TestMetadataUpdatesToLogs(t *testing.T) {
+	ts := time.Unix(0, 1000)
+	updates := []*collection.MetadataUpdate{
+		{
+			ResourceIDKey: "k8s.pod.uid",
+			ResourceID:    "pod-uid",
+			MetadataDelta: collection.MetadataDelta{
+				MetadataToAdd:    map[string]string{"k8s.workload.name": "deployment"},
+				MetadataToUpdate: map[string]string{"env": "prod"},
+			},
+		},
+		{
+			ResourceIDKey: "container.id",
+			ResourceID:    "container-id",
+			MetadataDelta: collection.MetadataDelta{
+				MetadataToRemove: map[string]string{"container.status": "running"},
+			},
+		},
+	}
+
+	ld := metadataUpdatesToLogs(metadataUpdated, updates, ts)
+	require.Equal(t, 2, ld.ResourceLogs().Len())
+
+	rl := ld.ResourceLogs().At(0)
+	id, ok := rl.Resource().Attributes().Get("k8s.pod.uid")
+	require.True(t, ok)
+	assert.Equal(t, "pod-uid", id.StringVal())
+
+	lr := rl.InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assert.Equal(t, metadataLogName, lr.Name())
+	assert.Equal(t, pdata.TimestampUnixNano(1000), lr.Timestamp())
+	assertStringAttr(t, lr.Attributes(), metadataOperationKey, "updated")
+	assertStringAttr(t, lr.Attributes(), resourceKindKey, "pod")
+	assertMapAttr(t, lr.Attributes(), metadataAddedKey, map[string]string{"k8s.workload.name": "deployment"})
+	assertMapAttr(t, lr.Attributes(), metadataUpdatedKey, map[string]string{"env": "prod"})
+	_, ok = lr.Attributes().Get(metadataRemovedKey)
+	assert.False(t, ok)
+
+	lr = ld.ResourceLogs().At(1).InstrumentationLibraryLogs().At(0).Logs().At(0)
+	assertStringAttr(t, lr.Attributes(), resourceKindKey, "container")
+	assertMapAttr(t, lr.Attributes(), metadataRemovedKey, map[string]string{"container.status": "running"})
+}
+
+func TestResourceKind(t *testing.T) {
+	assert.Equal(t, "pod", resourceKind("k8s.pod.uid"))
+	assert.Equal(t, "container", resourceKind("container.id"))
+	assert.Equal(t, "replicaset", resourceKind("k8s.replicaset.uid"))
+}
+
+func assertStringAttr(t *testing.T, attrs pdata.AttributeMap, key, expected string) {
+	v, ok := attrs.Get(key)
+	require.True(t, ok, key)
+	assert.Equal(t, expected, v.StringVal())
+}
+
+func assertMapAttr(t *testing.T, attrs pdata.AttributeMap, key string, expected map[string]string) {
+	v, ok := attrs.Get(key)
+	require.True(t, ok, key)
+	actual := map[string]string{}
+	v.MapVal().ForEach(func(k string, av pdata.AttributeValue) {
+		actual[k] = av.StringVal()
+	})
+	assert.Equal(t, expected, actual)
+}
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/client-go/kubernetes/fake"
@@ -79,3 +80,62 @@ func createNodes(t *testing.T, client *fake.Clientset, numNodes int) {
 		time.Sleep(2 * time.Millisecond)
 	}
 }
+
+func createPersistentVolumes(t *testing.T, client *fake.Clientset, numPVs int) {
+	for i := 0; i < numPVs; i++ {
+		pv := &corev1.PersistentVolume{
+			ObjectMeta: v1.ObjectMeta{
+				UID:  types.UID("pv" + strconv.Itoa(i)),
+				Name: strconv.Itoa(i),
+			},
+		}
+		_, err := client.CoreV1().PersistentVolumes().Create(context.Background(), pv, v1.CreateOptions{})
+
+		if err != nil {
+			t.Errorf("error creating persistent volume: %v", err)
+			t.FailNow()
+		}
+
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func createPersistentVolumeClaims(t *testing.T, client *fake.Clientset, numPVCs int) {
+	for i := 0; i < numPVCs; i++ {
+		pvc := &corev1.PersistentVolumeClaim{
+			ObjectMeta: v1.ObjectMeta{
+				UID:       types.UID("pvc" + strconv.Itoa(i)),
+				Name:      strconv.Itoa(i),
+				Namespace: "test",
+			},
+		}
+		_, err := client.CoreV1().PersistentVolumeClaims(pvc.Namespace).Create(context.Background(), pvc, v1.CreateOptions{})
+
+		if err != nil {
+			t.Errorf("error creating persistent volume claim: %v", err)
+			t.FailNow()
+		}
+
+		time.Sleep(2 * time.Millisecond)
+	}
+}
+
+func createIngresses(t *testing.T, client *fake.Clientset, numIngresses int) {
+	for i := 0; i < numIngresses; i++ {
+		ing := &networkingv1beta1.Ingress{
+			ObjectMeta: v1.ObjectMeta{
+				UID:       types.UID("ingress" + strconv.Itoa(i)),
+				Name:      strconv.Itoa(i),
+				Namespace: "test",
+			},
+		}
+		_, err := client.NetworkingV1beta1().Ingresses(ing.Namespace).Create(context.Background(), ing, v1.CreateOptions{})
+
+		if err != nil {
+			t.Errorf("error creating ingress: %v", err)
+			t.FailNow()
+		}
+
+		time.Sleep(2 * time.Millisecond)
+	}
+}
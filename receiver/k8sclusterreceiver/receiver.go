@@ -22,10 +22,13 @@ import (
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 	"k8s.io/client-go/kubernetes"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/k8sclusterreceiver/collection"
 )
 
 const (
@@ -35,14 +38,19 @@ const (
 )
 
 var _ component.MetricsReceiver = (*kubernetesReceiver)(nil)
+var _ component.LogsReceiver = (*kubernetesReceiver)(nil)
 
+// kubernetesReceiver implements component.MetricsReceiver and component.LogsReceiver.
+// A single instance is shared by all pipelines using the same config.
 type kubernetesReceiver struct {
 	resourceWatcher *resourceWatcher
+	eventsWatcher   *eventsWatcher
 
-	config   *Config
-	logger   *zap.Logger
-	consumer consumer.MetricsConsumer
-	cancel   context.CancelFunc
+	config          *Config
+	logger          *zap.Logger
+	metricsConsumer consumer.MetricsConsumer
+	logsConsumer    consumer.LogsConsumer
+	cancel          context.CancelFunc
 }
 
 func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) error {
@@ -55,6 +63,18 @@ func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) er
 		return err
 	}
 
+	if kr.logsConsumer != nil && kr.config.MetadataLogs {
+		kr.resourceWatcher.metadataLogsConsumer = func(op metadataOperation, metadata []*collection.MetadataUpdate) {
+			kr.dispatchMetadataLogs(c, op, metadata)
+		}
+	}
+
+	if kr.logsConsumer != nil && kr.eventsWatcher != nil {
+		kr.eventsWatcher.start(c, func(ld pdata.Logs) {
+			kr.dispatchLogs(c, ld)
+		})
+	}
+
 	go func() {
 		kr.logger.Info("Starting shared informers and wait for initial cache sync.")
 		kr.resourceWatcher.startWatchingResources(c)
@@ -75,6 +95,10 @@ func (kr *kubernetesReceiver) Start(ctx context.Context, host component.Host) er
 		kr.logger.Info("Completed syncing shared informer caches.")
 		kr.resourceWatcher.initialSyncDone.Store(true)
 
+		if kr.metricsConsumer == nil {
+			return
+		}
+
 		ticker := time.NewTicker(kr.config.CollectionInterval)
 		defer ticker.Stop()
 
@@ -105,20 +129,41 @@ func (kr *kubernetesReceiver) dispatchMetrics(ctx context.Context) {
 
 	numTimeseries, numPoints := resourceMetrics.MetricAndDataPointCount()
 
-	err := kr.consumer.ConsumeMetrics(c, resourceMetrics)
+	err := kr.metricsConsumer.ConsumeMetrics(c, resourceMetrics)
 	obsreport.EndMetricsReceiveOp(c, typeStr, numPoints, numTimeseries, err)
 }
 
+func (kr *kubernetesReceiver) dispatchMetadataLogs(
+	ctx context.Context, op metadataOperation, metadata []*collection.MetadataUpdate) {
+	kr.dispatchLogs(ctx, metadataUpdatesToLogs(op, metadata, time.Now()))
+}
+
+func (kr *kubernetesReceiver) dispatchLogs(ctx context.Context, ld pdata.Logs) {
+	if err := kr.logsConsumer.ConsumeLogs(ctx, ld); err != nil {
+		kr.logger.Error("Failed to consume logs", zap.Error(err))
+	}
+}
+
 // newReceiver creates the Kubernetes cluster receiver with the given configuration.
+// The consumers of the data types it is used for are set afterwards.
 func newReceiver(
-	logger *zap.Logger, config *Config, consumer consumer.MetricsConsumer,
-	client kubernetes.Interface) (component.MetricsReceiver, error) {
-	resourceWatcher := newResourceWatcher(logger, client, config.NodeConditionTypesToReport, defaultInitialSyncTimeout)
+	logger *zap.Logger, config *Config, client kubernetes.Interface) (*kubernetesReceiver, error) {
+	resourceWatcher, err := newResourceWatcher(logger, client, config, defaultInitialSyncTimeout)
+	if err != nil {
+		return nil, err
+	}
+
+	var eventsWatcher *eventsWatcher
+	if config.Events.Enabled {
+		if eventsWatcher, err = newEventsWatcher(logger, client, config.Events); err != nil {
+			return nil, fmt.Errorf("failed to configure events: %w", err)
+		}
+	}
 
 	return &kubernetesReceiver{
 		resourceWatcher: resourceWatcher,
+		eventsWatcher:   eventsWatcher,
 		logger:          logger,
 		config:          config,
-		consumer:        consumer,
 	}, nil
 }
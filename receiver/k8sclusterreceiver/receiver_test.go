@@ -74,6 +74,29 @@ func TestReceiver(t *testing.T) {
 	r.Shutdown(ctx)
 }
 
+func TestReceiverWithStorageAndIngresses(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	consumer := &exportertest.SinkMetricsExporter{}
+
+	r, err := setupReceiver(client, consumer, 10*time.Second)
+	require.NoError(t, err)
+
+	createPersistentVolumes(t, client, 1)
+	createPersistentVolumeClaims(t, client, 2)
+	createIngresses(t, client, 1)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, componenttest.NewNopHost()))
+
+	// Expects metric data from every resource.
+	require.Eventually(t, func() bool {
+		return len(r.resourceWatcher.dataCollector.CollectMetricData(time.Now())) == 4
+	}, 10*time.Second, 100*time.Millisecond,
+		"metrics not collected")
+
+	r.Shutdown(ctx)
+}
+
 func TestReceiverTimesOutAfterStartup(t *testing.T) {
 	client := fake.NewSimpleClientset()
 	consumer := &exportertest.SinkMetricsExporter{}
@@ -156,6 +179,47 @@ func TestReceiverWithMetadata(t *testing.T) {
 	r.Shutdown(ctx)
 }
 
+func TestReceiverWithMetadataLogs(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	sink := &exportertest.SinkLogsExporter{}
+
+	r, err := setupReceiver(client, nil, 10*time.Second)
+	require.NoError(t, err)
+	r.logsConsumer = sink
+	r.config.MetadataLogs = true
+
+	pods := createPods(t, client, 1)
+
+	ctx := context.Background()
+	require.NoError(t, r.Start(ctx, nopHostWithExporters{}))
+
+	require.Eventually(t, func() bool {
+		return sink.LogRecordsCount() > 0
+	}, 10*time.Second, 100*time.Millisecond,
+		"metadata logs not received for added pod")
+
+	require.Len(t, pods, 1)
+	updatedPod := getUpdatedPod(pods[0])
+	r.resourceWatcher.onUpdate(pods[0], updatedPod)
+	r.resourceWatcher.onDelete(updatedPod)
+
+	var ops []string
+	for _, ld := range sink.AllLogs() {
+		rls := ld.ResourceLogs()
+		for i := 0; i < rls.Len(); i++ {
+			lr := rls.At(i).InstrumentationLibraryLogs().At(0).Logs().At(0)
+			op, ok := lr.Attributes().Get(metadataOperationKey)
+			require.True(t, ok)
+			ops = append(ops, op.StringVal())
+		}
+	}
+	require.Contains(t, ops, "added")
+	require.Contains(t, ops, "updated")
+	require.Contains(t, ops, "deleted")
+
+	r.Shutdown(ctx)
+}
+
 func getUpdatedPod(pod *corev1.Pod) interface{} {
 	return &corev1.Pod{
 		ObjectMeta: v1.ObjectMeta{
@@ -180,13 +244,16 @@ func setupReceiver(
 		NodeConditionTypesToReport: []string{"Ready"},
 	}
 
-	rw := newResourceWatcher(logger, client, config.NodeConditionTypesToReport, initialSyncTimeout)
+	rw, err := newResourceWatcher(logger, client, config, initialSyncTimeout)
+	if err != nil {
+		return nil, err
+	}
 	rw.dataCollector.SetupMetadataStore(&corev1.Service{}, &testutils.MockStore{})
 
 	return &kubernetesReceiver{
 		resourceWatcher: rw,
 		logger:          logger,
 		config:          config,
-		consumer:        consumer,
+		metricsConsumer: consumer,
 	}, nil
 }
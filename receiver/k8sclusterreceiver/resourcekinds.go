@@ -0,0 +1,246 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package k8sclusterreceiver
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	appsv1 "k8s.io/api/apps/v1"
+	"k8s.io/api/autoscaling/v2beta1"
+	batchv1 "k8s.io/api/batch/v1"
+	batchv1beta1 "k8s.io/api/batch/v1beta1"
+	corev1 "k8s.io/api/core/v1"
+	networkingv1beta1 "k8s.io/api/networking/v1beta1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/informers"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/tools/cache"
+)
+
+// resourceKind describes a kind of Kubernetes resource the receiver can watch.
+type resourceKind struct {
+	// object is an empty object of the kind.
+	object runtime.Object
+	// namespaced is whether resources of the kind belong to a namespace.
+	namespaced bool
+	// informer returns the informer of the kind from factory.
+	informer func(factory informers.SharedInformerFactory) cache.SharedIndexInformer
+	// list lists resources of the kind. It is used to check whether the
+	// receiver is allowed to watch the kind.
+	list func(ctx context.Context, client kubernetes.Interface, namespace string, opts metav1.ListOptions) error
+}
+
+// resourceKinds holds the supported kinds, keyed by the plural name of their
+// resource, as used in RBAC rules.
+var resourceKinds = map[string]resourceKind{
+	"pods": {
+		object:     &corev1.Pod{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Pods().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().Pods(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"nodes": {
+		object: &corev1.Node{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Nodes().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, _ string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().Nodes().List(ctx, opts)
+			return err
+		},
+	},
+	"namespaces": {
+		object: &corev1.Namespace{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Namespaces().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, _ string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().Namespaces().List(ctx, opts)
+			return err
+		},
+	},
+	"replicationcontrollers": {
+		object:     &corev1.ReplicationController{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().ReplicationControllers().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().ReplicationControllers(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"resourcequotas": {
+		object:     &corev1.ResourceQuota{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().ResourceQuotas().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().ResourceQuotas(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"services": {
+		object:     &corev1.Service{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().Services().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().Services(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"persistentvolumes": {
+		object: &corev1.PersistentVolume{},
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().PersistentVolumes().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, _ string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().PersistentVolumes().List(ctx, opts)
+			return err
+		},
+	},
+	"persistentvolumeclaims": {
+		object:     &corev1.PersistentVolumeClaim{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Core().V1().PersistentVolumeClaims().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.CoreV1().PersistentVolumeClaims(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"daemonsets": {
+		object:     &appsv1.DaemonSet{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().DaemonSets().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.AppsV1().DaemonSets(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"deployments": {
+		object:     &appsv1.Deployment{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().Deployments().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.AppsV1().Deployments(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"replicasets": {
+		object:     &appsv1.ReplicaSet{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().ReplicaSets().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.AppsV1().ReplicaSets(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"statefulsets": {
+		object:     &appsv1.StatefulSet{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Apps().V1().StatefulSets().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.AppsV1().StatefulSets(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"jobs": {
+		object:     &batchv1.Job{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Batch().V1().Jobs().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.BatchV1().Jobs(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"cronjobs": {
+		object:     &batchv1beta1.CronJob{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Batch().V1beta1().CronJobs().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.BatchV1beta1().CronJobs(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"horizontalpodautoscalers": {
+		object:     &v2beta1.HorizontalPodAutoscaler{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Autoscaling().V2beta1().HorizontalPodAutoscalers().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.AutoscalingV2beta1().HorizontalPodAutoscalers(ns).List(ctx, opts)
+			return err
+		},
+	},
+	"ingresses": {
+		object:     &networkingv1beta1.Ingress{},
+		namespaced: true,
+		informer: func(f informers.SharedInformerFactory) cache.SharedIndexInformer {
+			return f.Networking().V1beta1().Ingresses().Informer()
+		},
+		list: func(ctx context.Context, c kubernetes.Interface, ns string, opts metav1.ListOptions) error {
+			_, err := c.NetworkingV1beta1().Ingresses(ns).List(ctx, opts)
+			return err
+		},
+	},
+}
+
+// supportedResourceKinds returns the sorted names of the supported kinds.
+func supportedResourceKinds() []string {
+	out := make([]string, 0, len(resourceKinds))
+	for name := range resourceKinds {
+		out = append(out, name)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// validateResourceKinds returns an error if any of names is not a supported kind.
+func validateResourceKinds(names []string) error {
+	for _, name := range names {
+		if _, ok := resourceKinds[name]; !ok {
+			return fmt.Errorf("unsupported resource kind %q, must be one of %s",
+				name, strings.Join(supportedResourceKinds(), ", "))
+		}
+	}
+	return nil
+}
@@ -23,11 +23,9 @@ import (
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.uber.org/atomic"
 	"go.uber.org/zap"
-	appsv1 "k8s.io/api/apps/v1"
-	"k8s.io/api/autoscaling/v2beta1"
-	batchv1 "k8s.io/api/batch/v1"
-	batchv1beta1 "k8s.io/api/batch/v1beta1"
-	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/informers"
 	"k8s.io/client-go/kubernetes"
@@ -39,10 +37,14 @@ import (
 
 type resourceWatcher struct {
 	client                     kubernetes.Interface
-	sharedInformerFactory      informers.SharedInformerFactory
+	sharedInformerFactories    []informers.SharedInformerFactory
 	dataCollector              *collection.DataCollector
 	logger                     *zap.Logger
 	metadataConsumers          []metadataConsumer
+	metadataLogsConsumer       metadataLogsConsumer
+	resourceKinds              []string
+	namespaces                 []string
+	labelSelector              string
 	initialTimeout             time.Duration
 	timedContextForInitialSync context.Context
 	initialSyncDone            *atomic.Bool
@@ -51,56 +53,137 @@ type resourceWatcher struct {
 
 type metadataConsumer func(metadata []*collection.MetadataUpdate) error
 
-// newResourceWatcher creates a Kubernetes resource watcher.
+// metadataLogsConsumer is sent all metadata updates, including the ones of
+// deleted resources, along with the operation that caused them.
+type metadataLogsConsumer func(op metadataOperation, metadata []*collection.MetadataUpdate)
+
+// newResourceWatcher creates a Kubernetes resource watcher of the resource
+// kinds, namespaces and labels selected by config.
 func newResourceWatcher(
 	logger *zap.Logger, client kubernetes.Interface,
-	nodeConditionTypesToReport []string, initialSyncTimeout time.Duration) *resourceWatcher {
-	rw := &resourceWatcher{
+	config *Config, initialSyncTimeout time.Duration) (*resourceWatcher, error) {
+	if err := validateResourceKinds(config.ResourceKinds); err != nil {
+		return nil, err
+	}
+	if _, err := labels.Parse(config.LabelSelector); err != nil {
+		return nil, fmt.Errorf("invalid label_selector: %w", err)
+	}
+
+	resourceKinds := config.ResourceKinds
+	if len(resourceKinds) == 0 {
+		resourceKinds = supportedResourceKinds()
+	}
+
+	return &resourceWatcher{
 		client:              client,
 		logger:              logger,
-		dataCollector:       collection.NewDataCollector(logger, nodeConditionTypesToReport),
+		dataCollector:       collection.NewDataCollector(logger, config.NodeConditionTypesToReport),
+		resourceKinds:       resourceKinds,
+		namespaces:          config.Namespaces,
+		labelSelector:       config.LabelSelector,
 		initialSyncDone:     atomic.NewBool(false),
 		initialSyncTimedOut: atomic.NewBool(false),
 		initialTimeout:      initialSyncTimeout,
+	}, nil
+}
+
+// prepareSharedInformerFactories adds shared informers for each resource kind
+// that has to be watched. Namespaced kinds are watched in each of the configured
+// namespaces, or in all namespaces if none is configured, and are restricted by
+// the label selector. Cluster-scoped kinds, such as nodes and namespaces, are
+// watched in full: neither the namespaces nor the label selector apply to them,
+// since they are rarely labeled like the workloads. Kinds the receiver is not
+// allowed to list are skipped.
+func (rw *resourceWatcher) prepareSharedInformerFactories(ctx context.Context) {
+	tweakListOptions := func(opts *metav1.ListOptions) {
+		opts.LabelSelector = rw.labelSelector
+	}
+
+	namespaces := rw.namespaces
+	if len(namespaces) == 0 {
+		namespaces = []string{metav1.NamespaceAll}
 	}
 
-	rw.prepareSharedInformerFactory()
+	newFactory := func(namespace string) informers.SharedInformerFactory {
+		return informers.NewSharedInformerFactoryWithOptions(rw.client, 0,
+			informers.WithNamespace(namespace),
+			informers.WithTweakListOptions(tweakListOptions),
+		)
+	}
+
+	clusterFactory := informers.NewSharedInformerFactory(rw.client, 0)
+	factories := map[string]informers.SharedInformerFactory{}
+
+	for _, name := range rw.resourceKinds {
+		kind := resourceKinds[name]
+		if !kind.namespaced {
+			if rw.isAllowed(ctx, name, kind, metav1.NamespaceAll) {
+				rw.setupInformers(kind.object, kind.informer(clusterFactory))
+			}
+			continue
+		}
 
-	return rw
+		for _, ns := range namespaces {
+			if !rw.isAllowed(ctx, name, kind, ns) {
+				continue
+			}
+			factory, ok := factories[ns]
+			if !ok {
+				factory = newFactory(ns)
+				factories[ns] = factory
+			}
+			rw.setupInformers(kind.object, kind.informer(factory))
+		}
+	}
+
+	rw.sharedInformerFactories = []informers.SharedInformerFactory{clusterFactory}
+	for _, ns := range namespaces {
+		if factory, ok := factories[ns]; ok {
+			rw.sharedInformerFactories = append(rw.sharedInformerFactories, factory)
+		}
+	}
 }
 
-func (rw *resourceWatcher) prepareSharedInformerFactory() {
-	factory := informers.NewSharedInformerFactoryWithOptions(rw.client, 0)
-
-	// Add shared informers for each resource type that has to be watched.
-	rw.setupInformers(&corev1.Pod{}, factory.Core().V1().Pods().Informer())
-	rw.setupInformers(&corev1.Node{}, factory.Core().V1().Nodes().Informer())
-	rw.setupInformers(&corev1.Namespace{}, factory.Core().V1().Namespaces().Informer())
-	rw.setupInformers(&corev1.ReplicationController{},
-		factory.Core().V1().ReplicationControllers().Informer(),
-	)
-	rw.setupInformers(&corev1.ResourceQuota{}, factory.Core().V1().ResourceQuotas().Informer())
-	rw.setupInformers(&corev1.Service{}, factory.Core().V1().Services().Informer())
-	rw.setupInformers(&appsv1.DaemonSet{}, factory.Apps().V1().DaemonSets().Informer())
-	rw.setupInformers(&appsv1.Deployment{}, factory.Apps().V1().Deployments().Informer())
-	rw.setupInformers(&appsv1.ReplicaSet{}, factory.Apps().V1().ReplicaSets().Informer())
-	rw.setupInformers(&appsv1.StatefulSet{}, factory.Apps().V1().StatefulSets().Informer())
-	rw.setupInformers(&batchv1.Job{}, factory.Batch().V1().Jobs().Informer())
-	rw.setupInformers(&batchv1beta1.CronJob{}, factory.Batch().V1beta1().CronJobs().Informer())
-	rw.setupInformers(&v2beta1.HorizontalPodAutoscaler{},
-		factory.Autoscaling().V2beta1().HorizontalPodAutoscalers().Informer(),
-	)
-
-	rw.sharedInformerFactory = factory
+// isAllowed returns false if the receiver is forbidden to list resources of
+// kind in namespace. Other errors are logged and left for the informers to retry.
+func (rw *resourceWatcher) isAllowed(ctx context.Context, name string, kind resourceKind, namespace string) bool {
+	opts := metav1.ListOptions{Limit: 1}
+	if kind.namespaced {
+		opts.LabelSelector = rw.labelSelector
+	}
+	err := kind.list(ctx, rw.client, namespace, opts)
+	switch {
+	case err == nil:
+		return true
+	case apierrors.IsForbidden(err):
+		rw.logger.Warn("Not allowed to list resource kind, it will not be watched",
+			zap.String("kind", name),
+			zap.String("namespace", namespace),
+			zap.Error(err),
+		)
+		return false
+	default:
+		rw.logger.Warn("Failed to list resource kind",
+			zap.String("kind", name),
+			zap.String("namespace", namespace),
+			zap.Error(err),
+		)
+		return true
+	}
 }
 
 // startWatchingResources starts up all informers.
 func (rw *resourceWatcher) startWatchingResources(ctx context.Context) {
 	var cancel context.CancelFunc
 	rw.timedContextForInitialSync, cancel = context.WithTimeout(ctx, rw.initialTimeout)
+	defer cancel()
+
+	rw.prepareSharedInformerFactories(rw.timedContextForInitialSync)
 
-	// Start off individual informers in the factory.
-	rw.sharedInformerFactory.Start(ctx.Done())
+	// Start off individual informers in the factories.
+	for _, factory := range rw.sharedInformerFactories {
+		factory.Start(ctx.Done())
+	}
 
 	// Ensure cache is synced with initial state, once informers are started up.
 	// Note that the event handler can start receiving events as soon as the informers
@@ -108,8 +191,9 @@ func (rw *resourceWatcher) startWatchingResources(ctx context.Context) {
 	// collecting data before the cache sync since all data may not be available.
 	// This method will block either till the timeout set on the context, until
 	// the initial sync is complete or the parent context is cancelled.
-	rw.sharedInformerFactory.WaitForCacheSync(rw.timedContextForInitialSync.Done())
-	defer cancel()
+	for _, factory := range rw.sharedInformerFactories {
+		factory.WaitForCacheSync(rw.timedContextForInitialSync.Done())
+	}
 }
 
 // setupInformers adds event handlers to informers and setups a metadataStore.
@@ -127,17 +211,30 @@ func (rw *resourceWatcher) onAdd(obj interface{}) {
 	rw.dataCollector.SyncMetrics(obj)
 
 	// Sync metadata only if there's at least one destination for it to sent.
-	if len(rw.metadataConsumers) == 0 {
+	if !rw.hasMetadataDestinations() {
 		return
 	}
 
 	newMetadata := rw.dataCollector.SyncMetadata(obj)
-	rw.syncMetadataUpdate(map[collection.ResourceID]*collection.KubernetesMetadata{}, newMetadata)
+	rw.syncMetadataUpdate(map[collection.ResourceID]*collection.KubernetesMetadata{}, newMetadata, metadataAdded)
 }
 
 func (rw *resourceWatcher) onDelete(obj interface{}) {
 	rw.waitForInitialInformerSync()
 	rw.dataCollector.RemoveFromMetricsStore(obj)
+
+	// Only metadata logs report deleted resources.
+	if rw.metadataLogsConsumer == nil {
+		return
+	}
+
+	if deleted, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+		obj = deleted.Obj
+	}
+	oldMetadata := rw.dataCollector.SyncMetadata(obj)
+	if metadataUpdate := collection.GetMetadataRemoval(oldMetadata); len(metadataUpdate) > 0 {
+		rw.metadataLogsConsumer(metadataDeleted, metadataUpdate)
+	}
 }
 
 func (rw *resourceWatcher) onUpdate(oldObj, newObj interface{}) {
@@ -146,14 +243,18 @@ func (rw *resourceWatcher) onUpdate(oldObj, newObj interface{}) {
 	rw.dataCollector.SyncMetrics(newObj)
 
 	// Sync metadata only if there's at least one destination for it to sent.
-	if len(rw.metadataConsumers) == 0 {
+	if !rw.hasMetadataDestinations() {
 		return
 	}
 
 	oldMetadata := rw.dataCollector.SyncMetadata(oldObj)
 	newMetadata := rw.dataCollector.SyncMetadata(newObj)
 
-	rw.syncMetadataUpdate(oldMetadata, newMetadata)
+	rw.syncMetadataUpdate(oldMetadata, newMetadata, metadataUpdated)
+}
+
+func (rw *resourceWatcher) hasMetadataDestinations() bool {
+	return len(rw.metadataConsumers) > 0 || rw.metadataLogsConsumer != nil
 }
 
 func (rw *resourceWatcher) waitForInitialInformerSync() {
@@ -218,7 +319,7 @@ func validateMetadataExporters(metadataExporters map[string]bool,
 }
 
 func (rw *resourceWatcher) syncMetadataUpdate(oldMetadata,
-	newMetadata map[collection.ResourceID]*collection.KubernetesMetadata) {
+	newMetadata map[collection.ResourceID]*collection.KubernetesMetadata, op metadataOperation) {
 
 	metadataUpdate := collection.GetMetadataUpdate(oldMetadata, newMetadata)
 	if len(metadataUpdate) == 0 {
@@ -228,4 +329,8 @@ func (rw *resourceWatcher) syncMetadataUpdate(oldMetadata,
 	for _, consume := range rw.metadataConsumers {
 		consume(metadataUpdate)
 	}
+
+	if rw.metadataLogsConsumer != nil {
+		rw.metadataLogsConsumer(op, metadataUpdate)
+	}
 }
@@ -15,12 +15,23 @@
 package k8sclusterreceiver
 
 import (
+	"context"
+	"errors"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.uber.org/zap"
+	corev1 "k8s.io/api/core/v1"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/apimachinery/pkg/runtime/schema"
+	"k8s.io/apimachinery/pkg/types"
+	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 )
 
 func TestSetupMetadataExporters(t *testing.T) {
@@ -86,3 +97,129 @@ func TestSetupMetadataExporters(t *testing.T) {
 		})
 	}
 }
+
+func TestNewResourceWatcherValidation(t *testing.T) {
+	client := fake.NewSimpleClientset()
+
+	_, err := newResourceWatcher(zap.NewNop(), client, &Config{ResourceKinds: []string{"pods", "secrets"}}, time.Second)
+	require.Error(t, err)
+
+	_, err = newResourceWatcher(zap.NewNop(), client, &Config{LabelSelector: "app in (foo"}, time.Second)
+	require.Error(t, err)
+
+	rw, err := newResourceWatcher(zap.NewNop(), client, &Config{}, time.Second)
+	require.NoError(t, err)
+	require.Equal(t, supportedResourceKinds(), rw.resourceKinds)
+}
+
+func TestResourceWatcherSelection(t *testing.T) {
+	tests := []struct {
+		name         string
+		config       *Config
+		expectedPods []string
+	}{
+		{
+			name:         "All namespaces",
+			config:       &Config{ResourceKinds: []string{"pods"}},
+			expectedPods: []string{"pod-a", "pod-b", "pod-c"},
+		},
+		{
+			name:         "Namespaces",
+			config:       &Config{ResourceKinds: []string{"pods"}, Namespaces: []string{"ns-1"}},
+			expectedPods: []string{"pod-a", "pod-b"},
+		},
+		{
+			name:         "Label selector",
+			config:       &Config{ResourceKinds: []string{"pods"}, LabelSelector: "app=foo"},
+			expectedPods: []string{"pod-a", "pod-c"},
+		},
+		{
+			name: "Namespaces and label selector",
+			config: &Config{
+				ResourceKinds: []string{"pods"},
+				Namespaces:    []string{"ns-1", "ns-2"},
+				LabelSelector: "app=foo",
+			},
+			expectedPods: []string{"pod-a", "pod-c"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			client := fake.NewSimpleClientset()
+			createPod(t, client, "pod-a", "ns-1", map[string]string{"app": "foo"})
+			createPod(t, client, "pod-b", "ns-1", map[string]string{"app": "bar"})
+			createPod(t, client, "pod-c", "ns-2", map[string]string{"app": "foo"})
+
+			rw := startResourceWatcher(t, client, tt.config)
+
+			require.Eventually(t, func() bool {
+				return len(rw.dataCollector.CollectMetricData(time.Now())) == len(tt.expectedPods)
+			}, 10*time.Second, 10*time.Millisecond)
+
+			var pods []string
+			for _, md := range rw.dataCollector.CollectMetricData(time.Now()) {
+				pods = append(pods, md.Resource.Labels["k8s.pod.name"])
+			}
+			require.ElementsMatch(t, tt.expectedPods, pods)
+		})
+	}
+}
+
+func TestResourceWatcherLabelSelectorClusterScoped(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	createPod(t, client, "pod-a", "ns-1", map[string]string{"app": "foo"})
+	createPod(t, client, "pod-b", "ns-1", map[string]string{"app": "bar"})
+	createNodes(t, client, 1)
+
+	// The node isn't labeled app=foo but is still watched.
+	rw := startResourceWatcher(t, client, &Config{
+		ResourceKinds: []string{"pods", "nodes"},
+		LabelSelector: "app=foo",
+	})
+	require.Eventually(t, func() bool {
+		return len(rw.dataCollector.CollectMetricData(time.Now())) == 2
+	}, 10*time.Second, 10*time.Millisecond)
+}
+
+func TestResourceWatcherForbiddenKind(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	client.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, apierrors.NewForbidden(schema.GroupResource{Resource: "nodes"}, "", errors.New("rbac"))
+	})
+	createPod(t, client, "pod-a", "ns-1", nil)
+	createNodes(t, client, 1)
+
+	rw := startResourceWatcher(t, client, &Config{ResourceKinds: []string{"pods", "nodes"}})
+	require.True(t, rw.timedContextForInitialSync.Err() != context.DeadlineExceeded,
+		"initial sync should not wait for forbidden kinds")
+
+	require.Eventually(t, func() bool {
+		return len(rw.dataCollector.CollectMetricData(time.Now())) == 1
+	}, 10*time.Second, 10*time.Millisecond)
+	require.Len(t, rw.sharedInformerFactories, 2)
+}
+
+func startResourceWatcher(t *testing.T, client *fake.Clientset, config *Config) *resourceWatcher {
+	rw, err := newResourceWatcher(zap.NewNop(), client, config, 10*time.Second)
+	require.NoError(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	t.Cleanup(cancel)
+	rw.startWatchingResources(ctx)
+	rw.initialSyncDone.Store(true)
+	return rw
+}
+
+func createPod(t *testing.T, client *fake.Clientset, name, namespace string, labels map[string]string) {
+	p := &corev1.Pod{
+		ObjectMeta: v1.ObjectMeta{
+			UID:       types.UID(name + "-uid"),
+			Name:      name,
+			Namespace: namespace,
+			Labels:    labels,
+		},
+	}
+	_, err := client.CoreV1().Pods(namespace).Create(context.Background(), p, v1.CreateOptions{})
+	require.NoError(t, err)
+}
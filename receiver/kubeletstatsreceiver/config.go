@@ -35,6 +35,12 @@ type Config struct {
 	confignet.TCPAddr             `mapstructure:",squash"`
 	CollectionInterval            time.Duration `mapstructure:"collection_interval"`
 
+	// DataSource is the kubelet endpoint metrics are collected from, either
+	// "summary" for /stats/summary or "cadvisor" for /metrics/cadvisor.
+	// Defaults to "summary". The "cadvisor" source always fetches the /pods
+	// endpoint and does not provide the "volume" metric group.
+	DataSource kubelet.DataSource `mapstructure:"data_source"`
+
 	// ExtraMetadataLabels contains list of extra metadata that should be taken from /pods endpoint
 	// and put as extra labels on metrics resource.
 	// No additional metadata is fetched by default, so there are no extra calls to /pods endpoint.
@@ -42,11 +48,18 @@ type Config struct {
 	ExtraMetadataLabels []kubelet.MetadataLabel `mapstructure:"extra_metadata_labels"`
 
 	// MetricGroupsToCollect provides a list of metrics groups to collect metrics from.
-	// "container", "pod", "node" and "volume" are the only valid groups.
+	// "container", "pod", "node", "volume" and "utilization" are the only valid groups.
+	// The "utilization" group requires an extra call to the /pods endpoint.
 	MetricGroupsToCollect []kubelet.MetricGroup `mapstructure:"metric_groups"`
 
 	// Configuration of the Kubernetes API client.
 	K8sAPIConfig *k8sconfig.APIConfig `mapstructure:"k8s_api_config"`
+
+	// DiscoverNodes enables scraping the kubelets of all the nodes of the cluster
+	// through the API server proxy, instead of the kubelet at Endpoint, so that
+	// a single collector can monitor a cluster. Nodes are discovered with the
+	// Kubernetes API client, so K8sAPIConfig is required.
+	DiscoverNodes bool `mapstructure:"discover_nodes"`
 }
 
 // getReceiverOptions returns receiverOptions is the config is valid,
@@ -62,6 +75,17 @@ func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
 		return nil, err
 	}
 
+	if cfg.DataSource != "" && !kubelet.ValidDataSources[cfg.DataSource] {
+		return nil, fmt.Errorf("invalid data_source %q", cfg.DataSource)
+	}
+	if cfg.DataSource == kubelet.CadvisorDataSource && mgs[kubelet.VolumeMetricGroup] {
+		return nil, errors.New("the volume metric group is not supported by the cadvisor data source")
+	}
+
+	if cfg.DiscoverNodes && cfg.K8sAPIConfig == nil {
+		return nil, errors.New("k8s_api_config is required to discover nodes")
+	}
+
 	var k8sAPIClient kubernetes.Interface
 	if cfg.K8sAPIConfig != nil {
 		k8sAPIClient, err = k8sconfig.MakeClient(*cfg.K8sAPIConfig)
@@ -75,7 +99,9 @@ func (cfg *Config) getReceiverOptions() (*receiverOptions, error) {
 		collectionInterval:    cfg.CollectionInterval,
 		extraMetadataLabels:   cfg.ExtraMetadataLabels,
 		metricGroupsToCollect: mgs,
+		dataSource:            cfg.DataSource,
 		k8sAPIClient:          k8sAPIClient,
+		discoverNodes:         cfg.DiscoverNodes,
 	}, nil
 }
 
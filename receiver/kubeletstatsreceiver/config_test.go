@@ -174,13 +174,56 @@ func TestLoadConfig(t *testing.T) {
 		},
 		K8sAPIConfig: &k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeKubeConfig},
 	}, metadataWithK8sAPICfg)
+
+	cadvisorCfg := cfg.Receivers["kubeletstats/cadvisor"].(*Config)
+	require.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "kubeletstats",
+			NameVal: "kubeletstats/cadvisor",
+		},
+		ClientConfig: kubelet.ClientConfig{
+			APIConfig: k8sconfig.APIConfig{
+				AuthType: "serviceAccount",
+			},
+		},
+		CollectionInterval: duration,
+		DataSource:         kubelet.CadvisorDataSource,
+		MetricGroupsToCollect: []kubelet.MetricGroup{
+			kubelet.ContainerMetricGroup,
+			kubelet.PodMetricGroup,
+			kubelet.NodeMetricGroup,
+		},
+	}, cadvisorCfg)
+
+	discoverNodesCfg := cfg.Receivers["kubeletstats/discover_nodes"].(*Config)
+	require.Equal(t, &Config{
+		ReceiverSettings: configmodels.ReceiverSettings{
+			TypeVal: "kubeletstats",
+			NameVal: "kubeletstats/discover_nodes",
+		},
+		ClientConfig: kubelet.ClientConfig{
+			APIConfig: k8sconfig.APIConfig{
+				AuthType: "tls",
+			},
+		},
+		CollectionInterval: duration,
+		MetricGroupsToCollect: []kubelet.MetricGroup{
+			kubelet.ContainerMetricGroup,
+			kubelet.PodMetricGroup,
+			kubelet.NodeMetricGroup,
+		},
+		DiscoverNodes: true,
+		K8sAPIConfig:  &k8sconfig.APIConfig{AuthType: k8sconfig.AuthTypeServiceAccount},
+	}, discoverNodesCfg)
 }
 
 func TestGetReceiverOptions(t *testing.T) {
 	type fields struct {
 		extraMetadataLabels   []kubelet.MetadataLabel
 		metricGroupsToCollect []kubelet.MetricGroup
+		dataSource            kubelet.DataSource
 		k8sAPIConfig          *k8sconfig.APIConfig
+		discoverNodes         bool
 	}
 	tests := []struct {
 		name    string
@@ -231,6 +274,50 @@ func TestGetReceiverOptions(t *testing.T) {
 			want:    nil,
 			wantErr: true,
 		},
+		{
+			name: "Valid cadvisor data source",
+			fields: fields{
+				metricGroupsToCollect: []kubelet.MetricGroup{
+					kubelet.ContainerMetricGroup,
+				},
+				dataSource: kubelet.CadvisorDataSource,
+			},
+			want: &receiverOptions{
+				name: typeStr,
+				metricGroupsToCollect: map[kubelet.MetricGroup]bool{
+					kubelet.ContainerMetricGroup: true,
+				},
+				dataSource:         kubelet.CadvisorDataSource,
+				collectionInterval: 10 * time.Second,
+			},
+		},
+		{
+			name: "Invalid data source",
+			fields: fields{
+				dataSource: "unsupported",
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Volume metric group with cadvisor data source",
+			fields: fields{
+				metricGroupsToCollect: []kubelet.MetricGroup{
+					kubelet.VolumeMetricGroup,
+				},
+				dataSource: kubelet.CadvisorDataSource,
+			},
+			want:    nil,
+			wantErr: true,
+		},
+		{
+			name: "Discover nodes without k8s API config",
+			fields: fields{
+				discoverNodes: true,
+			},
+			want:    nil,
+			wantErr: true,
+		},
 		{
 			name: "Fails to create k8s API client",
 			fields: fields{
@@ -249,7 +336,9 @@ func TestGetReceiverOptions(t *testing.T) {
 				CollectionInterval:    10 * time.Second,
 				ExtraMetadataLabels:   tt.fields.extraMetadataLabels,
 				MetricGroupsToCollect: tt.fields.metricGroupsToCollect,
+				DataSource:            tt.fields.dataSource,
 				K8sAPIConfig:          tt.fields.k8sAPIConfig,
+				DiscoverNodes:         tt.fields.discoverNodes,
 			}
 			got, err := cfg.getReceiverOptions()
 			if (err != nil) != tt.wantErr {
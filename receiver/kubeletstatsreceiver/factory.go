@@ -76,9 +76,14 @@ func createMetricsReceiver(
 	if err != nil {
 		return nil, err
 	}
-	rest, err := restClient(params.Logger, cfg)
-	if err != nil {
-		return nil, err
+	// Discovered nodes are scraped through the API server proxy, which
+	// doesn't use the kubelet client settings.
+	var rest kubelet.RestClient
+	if !rOptions.discoverNodes {
+		rest, err = restClient(params.Logger, cfg)
+		if err != nil {
+			return nil, err
+		}
 	}
 
 	return newReceiver(rOptions, params.Logger, rest, consumer)
@@ -33,13 +33,18 @@ const (
 	PodMetricGroup       = MetricGroup("pod")
 	NodeMetricGroup      = MetricGroup("node")
 	VolumeMetricGroup    = MetricGroup("volume")
+	// UtilizationMetricGroup is the group of the CPU and memory usage of pods
+	// and containers as a percentage of their requests and limits. Collecting
+	// it requires fetching the pod specs from the /pods endpoint.
+	UtilizationMetricGroup = MetricGroup("utilization")
 )
 
 var ValidMetricGroups = map[MetricGroup]bool{
-	ContainerMetricGroup: true,
-	PodMetricGroup:       true,
-	NodeMetricGroup:      true,
-	VolumeMetricGroup:    true,
+	ContainerMetricGroup:   true,
+	PodMetricGroup:         true,
+	NodeMetricGroup:        true,
+	VolumeMetricGroup:      true,
+	UtilizationMetricGroup: true,
 }
 
 type metricDataAccumulator struct {
@@ -48,6 +53,9 @@ type metricDataAccumulator struct {
 	logger                *zap.Logger
 	metricGroupsToCollect map[MetricGroup]bool
 	time                  time.Time
+	// extraMetrics returns the metrics of a resource that the data source
+	// provides on top of the ones built from the summary stats, if any.
+	extraMetrics func(group MetricGroup, r *resourcepb.Resource) []*metricspb.Metric
 }
 
 const (
@@ -64,35 +72,60 @@ func (a *metricDataAccumulator) nodeStats(s stats.NodeStats) {
 	}
 
 	// todo s.Runtime.ImageFs
+	resource := nodeResource(s)
 	a.accumulate(
 		timestamppb.New(s.StartTime.Time),
-		nodeResource(s),
+		resource,
 
 		cpuMetrics(nodePrefix, s.CPU),
 		fsMetrics(nodePrefix, s.Fs),
 		memMetrics(nodePrefix, s.Memory),
 		networkMetrics(nodePrefix, s.Network),
+		a.extra(NodeMetricGroup, resource),
 	)
 }
 
 func (a *metricDataAccumulator) podStats(podResource *resourcepb.Resource, s stats.PodStats) {
-	if !a.metricGroupsToCollect[PodMetricGroup] {
+	collectPod := a.metricGroupsToCollect[PodMetricGroup]
+	collectUtilization := a.metricGroupsToCollect[UtilizationMetricGroup]
+	if !collectPod && !collectUtilization {
+		return
+	}
+
+	var metrics [][]*metricspb.Metric
+	if collectPod {
+		metrics = append(metrics,
+			cpuMetrics(podPrefix, s.CPU),
+			fsMetrics(podPrefix, s.EphemeralStorage),
+			memMetrics(podPrefix, s.Memory),
+			networkMetrics(podPrefix, s.Network),
+			a.extra(PodMetricGroup, podResource),
+		)
+	}
+	if collectUtilization {
+		if pod := a.metadata.getPod(s.PodRef.UID); pod != nil {
+			requests, limits := podResourceRequirements(pod)
+			if um := utilizationMetrics(podPrefix, s.CPU, s.Memory, requests, limits); len(um) > 0 {
+				metrics = append(metrics, um)
+			}
+		}
+	}
+
+	if len(metrics) == 0 {
 		return
 	}
 
 	a.accumulate(
 		timestamppb.New(s.StartTime.Time),
 		podResource,
-
-		cpuMetrics(podPrefix, s.CPU),
-		fsMetrics(podPrefix, s.EphemeralStorage),
-		memMetrics(podPrefix, s.Memory),
-		networkMetrics(podPrefix, s.Network),
+		metrics...,
 	)
 }
 
 func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource, s stats.ContainerStats) {
-	if !a.metricGroupsToCollect[ContainerMetricGroup] {
+	collectContainer := a.metricGroupsToCollect[ContainerMetricGroup]
+	collectUtilization := a.metricGroupsToCollect[UtilizationMetricGroup]
+	if !collectContainer && !collectUtilization {
 		return
 	}
 
@@ -103,14 +136,35 @@ func (a *metricDataAccumulator) containerStats(podResource *resourcepb.Resource,
 		return
 	}
 
-	// todo s.Logs
+	var metrics [][]*metricspb.Metric
+	if collectContainer {
+		// todo s.Logs
+		metrics = append(metrics,
+			cpuMetrics(containerPrefix, s.CPU),
+			memMetrics(containerPrefix, s.Memory),
+			fsMetrics(containerPrefix, s.Rootfs),
+			a.extra(ContainerMetricGroup, resource),
+		)
+	}
+	if collectUtilization {
+		if pod := a.metadata.getPod(podResource.Labels[conventions.AttributeK8sPodUID]); pod != nil {
+			if resources, ok := containerResourceRequirements(pod, s.Name); ok {
+				um := utilizationMetrics(containerPrefix, s.CPU, s.Memory, resources.Requests, resources.Limits)
+				if len(um) > 0 {
+					metrics = append(metrics, um)
+				}
+			}
+		}
+	}
+
+	if len(metrics) == 0 {
+		return
+	}
+
 	a.accumulate(
 		timestamppb.New(s.StartTime.Time),
 		resource,
-
-		cpuMetrics(containerPrefix, s.CPU),
-		memMetrics(containerPrefix, s.Memory),
-		fsMetrics(containerPrefix, s.Rootfs),
+		metrics...,
 	)
 }
 
@@ -137,6 +191,13 @@ func (a *metricDataAccumulator) volumeStats(podResource *resourcepb.Resource, s
 	)
 }
 
+func (a *metricDataAccumulator) extra(group MetricGroup, r *resourcepb.Resource) []*metricspb.Metric {
+	if a.extraMetrics == nil {
+		return nil
+	}
+	return a.extraMetrics(group, r)
+}
+
 func (a *metricDataAccumulator) accumulate(
 	startTime *timestamppb.Timestamp,
 	r *resourcepb.Resource,
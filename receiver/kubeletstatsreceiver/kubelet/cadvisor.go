@@ -0,0 +1,65 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"bytes"
+	"fmt"
+
+	dto "github.com/prometheus/client_model/go"
+	"github.com/prometheus/common/expfmt"
+)
+
+// DataSource is the kubelet endpoint that metrics are collected from.
+type DataSource string
+
+const (
+	// SummaryDataSource collects metrics from the /stats/summary endpoint.
+	SummaryDataSource = DataSource("summary")
+	// CadvisorDataSource collects metrics from the /metrics/cadvisor endpoint,
+	// which exposes signals such as CPU throttling, disk IO and OOM events
+	// that are missing from the summary.
+	CadvisorDataSource = DataSource("cadvisor")
+)
+
+var ValidDataSources = map[DataSource]bool{
+	SummaryDataSource:  true,
+	CadvisorDataSource: true,
+}
+
+// CadvisorProvider scrapes the Prometheus metrics exposed by the kubelet
+// /metrics/cadvisor endpoint.
+type CadvisorProvider struct {
+	rc RestClient
+}
+
+func NewCadvisorProvider(rc RestClient) *CadvisorProvider {
+	return &CadvisorProvider{rc: rc}
+}
+
+// Cadvisor returns the metric families of the cadvisor endpoint, keyed by
+// metric name.
+func (p *CadvisorProvider) Cadvisor() (map[string]*dto.MetricFamily, error) {
+	body, err := p.rc.Cadvisor()
+	if err != nil {
+		return nil, err
+	}
+	var parser expfmt.TextParser
+	families, err := parser.TextToMetricFamilies(bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse cadvisor metrics: %w", err)
+	}
+	return families, nil
+}
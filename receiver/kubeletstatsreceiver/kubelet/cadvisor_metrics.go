@@ -0,0 +1,547 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"sort"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	dto "github.com/prometheus/client_model/go"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
+)
+
+// Labels identifying the cgroup of a cadvisor series. Kubelets older than
+// 1.16 use pod_name and container_name instead of pod and container.
+const (
+	cadvisorLabelID              = "id"
+	cadvisorLabelNamespace       = "namespace"
+	cadvisorLabelPod             = "pod"
+	cadvisorLabelPodLegacy       = "pod_name"
+	cadvisorLabelContainer       = "container"
+	cadvisorLabelContainerLegacy = "container_name"
+
+	// cadvisorRootCgroup is the cgroup of the whole node.
+	cadvisorRootCgroup = "/"
+	// cadvisorPauseContainer is the sandbox container of a pod, which holds
+	// the network namespace shared by its containers.
+	cadvisorPauseContainer = "POD"
+	// cadvisorMachineMemory is the only series used that is not labeled with a
+	// cgroup. It is the memory of the node.
+	cadvisorMachineMemory = "machine_memory_bytes"
+
+	// Memory limits above this value are considered unset.
+	memoryUnlimited = 1 << 62
+)
+
+// cgroupRef identifies the node, a pod or a container of a pod from the labels
+// of a cadvisor series. The zero value refers to the node.
+type cgroupRef struct {
+	namespace string
+	pod       string
+	container string
+}
+
+// cgroupStats holds the series scraped from cadvisor for a cgroup.
+type cgroupStats struct {
+	startTime   metav1.Time
+	cpuTotal    *float64
+	cpuPerCore  *float64
+	memory      stats.MemoryStats
+	memoryLimit *uint64
+	fs          stats.FsStats
+	interfaces  map[string]*stats.InterfaceStats
+	// usageNanoCores is the CPU usage rate since the previous scrape, which
+	// cadvisor doesn't expose.
+	usageNanoCores *uint64
+	// extraMetrics builds the metrics that are not part of the summary,
+	// given the prefix of the resource they belong to.
+	extraMetrics []func(prefix string) *metricspb.Metric
+}
+
+type cadvisorHandler func(cs *cgroupStats, labels map[string]string, value float64)
+
+// cadvisorHandlers maps the cadvisor series that are collected to the stats
+// they are recorded in.
+var cadvisorHandlers = map[string]cadvisorHandler{
+	"container_start_time_seconds": func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.startTime = metav1.NewTime(time.Unix(int64(value), 0))
+	},
+	"container_cpu_usage_seconds_total": func(cs *cgroupStats, labels map[string]string, value float64) {
+		// Older kubelets only report the usage of each core.
+		if cpu := labels["cpu"]; cpu == "" || cpu == "total" {
+			cs.cpuTotal = &value
+		} else {
+			total := value
+			if cs.cpuPerCore != nil {
+				total += *cs.cpuPerCore
+			}
+			cs.cpuPerCore = &total
+		}
+	},
+	"container_memory_usage_bytes": func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.memory.UsageBytes = uint64Value(value)
+	},
+	"container_memory_working_set_bytes": func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.memory.WorkingSetBytes = uint64Value(value)
+	},
+	"container_memory_rss": func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.memory.RSSBytes = uint64Value(value)
+	},
+	"container_memory_failures_total": func(cs *cgroupStats, labels map[string]string, value float64) {
+		if labels["scope"] != "container" {
+			return
+		}
+		switch labels["failure_type"] {
+		case "pgfault":
+			cs.memory.PageFaults = uint64Value(value)
+		case "pgmajfault":
+			cs.memory.MajorPageFaults = uint64Value(value)
+		}
+	},
+	"container_spec_memory_limit_bytes": memoryLimitHandler,
+	cadvisorMachineMemory:               memoryLimitHandler,
+	"container_fs_usage_bytes": func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.fs.UsedBytes = addUint64(cs.fs.UsedBytes, value)
+	},
+	"container_fs_limit_bytes": func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.fs.CapacityBytes = addUint64(cs.fs.CapacityBytes, value)
+	},
+	"container_network_receive_bytes_total": func(cs *cgroupStats, labels map[string]string, value float64) {
+		cs.networkInterface(labels["interface"]).RxBytes = uint64Value(value)
+	},
+	"container_network_transmit_bytes_total": func(cs *cgroupStats, labels map[string]string, value float64) {
+		cs.networkInterface(labels["interface"]).TxBytes = uint64Value(value)
+	},
+	"container_network_receive_errors_total": func(cs *cgroupStats, labels map[string]string, value float64) {
+		cs.networkInterface(labels["interface"]).RxErrors = uint64Value(value)
+	},
+	"container_network_transmit_errors_total": func(cs *cgroupStats, labels map[string]string, value float64) {
+		cs.networkInterface(labels["interface"]).TxErrors = uint64Value(value)
+	},
+	"container_cpu_cfs_periods_total":           extraCumulativeInt("cpu.periods", "1"),
+	"container_cpu_cfs_throttled_periods_total": extraCumulativeInt("cpu.throttled_periods", "1"),
+	"container_cpu_cfs_throttled_seconds_total": extraCumulativeDouble("cpu.throttled_time", "s"),
+	"container_oom_events_total":                extraCumulativeInt("memory.oom_events", "1"),
+	"container_fs_reads_bytes_total":            extraDiskMetric("disk.io", "By", "read"),
+	"container_fs_writes_bytes_total":           extraDiskMetric("disk.io", "By", "write"),
+	"container_fs_reads_total":                  extraDiskMetric("disk.operations", "1", "read"),
+	"container_fs_writes_total":                 extraDiskMetric("disk.operations", "1", "write"),
+}
+
+// cadvisorNetworkSeries are reported for the pause container of pods, rather
+// than for the pod cgroup, by some kubelets.
+var cadvisorNetworkSeries = map[string]bool{
+	"container_network_receive_bytes_total":   true,
+	"container_network_transmit_bytes_total":  true,
+	"container_network_receive_errors_total":  true,
+	"container_network_transmit_errors_total": true,
+}
+
+func memoryLimitHandler(cs *cgroupStats, _ map[string]string, value float64) {
+	// Unlimited cgroups report a limit of 0 or of the max int64.
+	if value > 0 && value < memoryUnlimited {
+		cs.memoryLimit = uint64Value(value)
+	}
+}
+
+func extraCumulativeInt(name string, units string) cadvisorHandler {
+	return func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.extraMetrics = append(cs.extraMetrics, func(prefix string) *metricspb.Metric {
+			return cumulativeInt(prefix+name, units, uint64Value(value))
+		})
+	}
+}
+
+func extraCumulativeDouble(name string, units string) cadvisorHandler {
+	return func(cs *cgroupStats, _ map[string]string, value float64) {
+		cs.extraMetrics = append(cs.extraMetrics, func(prefix string) *metricspb.Metric {
+			return cumulativeDouble(prefix+name, units, &value)
+		})
+	}
+}
+
+func extraDiskMetric(name string, units string, direction string) cadvisorHandler {
+	return func(cs *cgroupStats, labels map[string]string, value float64) {
+		device := labels["device"]
+		cs.extraMetrics = append(cs.extraMetrics, func(prefix string) *metricspb.Metric {
+			metric := cumulativeInt(prefix+name, units, uint64Value(value))
+			applyLabels(metric, map[string]string{"device": device, directionLabel: direction})
+			return metric
+		})
+	}
+}
+
+// CadvisorCPUUsage holds the cumulative CPU usage of the cgroups scraped from
+// cadvisor, which doesn't expose the CPU usage rate reported by the summary, so
+// that the rate can be derived from the usage of the previous scrape.
+type CadvisorCPUUsage struct {
+	samples map[cgroupRef]cpuUsageSample
+}
+
+type cpuUsageSample struct {
+	startTime metav1.Time
+	seconds   float64
+	time      time.Time
+}
+
+func NewCadvisorCPUUsage() *CadvisorCPUUsage {
+	return &CadvisorCPUUsage{samples: map[cgroupRef]cpuUsageSample{}}
+}
+
+// update sets the CPU usage rate of the cgroups over the time since the
+// previous scrape, and records their usage for the next one. The rate of new
+// or restarted cgroups is only known from their next scrape.
+func (u *CadvisorCPUUsage) update(cgroups map[cgroupRef]*cgroupStats, now time.Time) {
+	samples := make(map[cgroupRef]cpuUsageSample, len(cgroups))
+	for ref, cs := range cgroups {
+		seconds := cs.cpuUsage()
+		if seconds == nil {
+			continue
+		}
+		samples[ref] = cpuUsageSample{startTime: cs.startTime, seconds: *seconds, time: now}
+
+		prev, ok := u.samples[ref]
+		elapsed := now.Sub(prev.time).Seconds()
+		if !ok || elapsed <= 0 || *seconds < prev.seconds || !prev.startTime.Equal(&cs.startTime) {
+			continue
+		}
+		cs.usageNanoCores = uint64Value((*seconds - prev.seconds) / elapsed * 1_000_000_000)
+	}
+	u.samples = samples
+}
+
+// CadvisorMetricsData converts the metric families scraped from the kubelet
+// /metrics/cadvisor endpoint to the same metrics and resources as MetricsData,
+// along with the CPU throttling, disk IO and OOM metrics missing from the summary.
+// The pods metadata is required to resolve the pod UIDs and the node name. The
+// CPU usage rates are derived from the usage recorded in cpuUsage by the previous
+// scrape, and aren't reported if it is nil.
+func CadvisorMetricsData(
+	logger *zap.Logger, families map[string]*dto.MetricFamily,
+	metadata Metadata, typeStr string,
+	metricGroupsToCollect map[MetricGroup]bool, cpuUsage *CadvisorCPUUsage) []consumerdata.MetricsData {
+	now := time.Now()
+	cgroups := cadvisorCgroups(families)
+	if cpuUsage != nil {
+		cpuUsage.update(cgroups, now)
+	}
+	summary, byKey := cadvisorSummary(logger, cgroups, metadata)
+	acc := &metricDataAccumulator{
+		metadata:              metadata,
+		logger:                logger,
+		metricGroupsToCollect: metricGroupsToCollect,
+		time:                  now,
+		extraMetrics:          cadvisorExtraMetrics(byKey),
+	}
+	return acc.metricsData(summary, typeStr)
+}
+
+// cadvisorCgroups groups the collected cadvisor series by cgroup. The series
+// of system cgroups, which are not part of a pod, are dropped.
+func cadvisorCgroups(families map[string]*dto.MetricFamily) map[cgroupRef]*cgroupStats {
+	names := make([]string, 0, len(families))
+	for name := range families {
+		if _, ok := cadvisorHandlers[name]; ok {
+			names = append(names, name)
+		}
+	}
+	// Keep the order of the extra metrics stable.
+	sort.Strings(names)
+
+	cgroups := map[cgroupRef]*cgroupStats{}
+	for _, name := range names {
+		handle := cadvisorHandlers[name]
+		for _, m := range families[name].Metric {
+			value, ok := cadvisorValue(m)
+			if !ok {
+				continue
+			}
+			labels := make(map[string]string, len(m.Label))
+			for _, l := range m.Label {
+				labels[l.GetName()] = l.GetValue()
+			}
+
+			ref, ok := cadvisorCgroupRef(name, labels)
+			if !ok {
+				continue
+			}
+			cs := cgroups[ref]
+			if cs == nil {
+				cs = &cgroupStats{}
+				cgroups[ref] = cs
+			}
+			handle(cs, labels, value)
+		}
+	}
+	return cgroups
+}
+
+func cadvisorCgroupRef(name string, labels map[string]string) (cgroupRef, bool) {
+	if name == cadvisorMachineMemory || labels[cadvisorLabelID] == cadvisorRootCgroup {
+		return cgroupRef{}, true
+	}
+
+	ref := cgroupRef{
+		namespace: labels[cadvisorLabelNamespace],
+		pod:       labels[cadvisorLabelPod],
+		container: labels[cadvisorLabelContainer],
+	}
+	if ref.pod == "" {
+		ref.pod = labels[cadvisorLabelPodLegacy]
+	}
+	if ref.container == "" {
+		ref.container = labels[cadvisorLabelContainerLegacy]
+	}
+
+	if ref.pod == "" {
+		return cgroupRef{}, false
+	}
+	if ref.container == cadvisorPauseContainer {
+		if !cadvisorNetworkSeries[name] {
+			return cgroupRef{}, false
+		}
+		ref.container = ""
+	}
+	return ref, true
+}
+
+func cadvisorValue(m *dto.Metric) (float64, bool) {
+	switch {
+	case m.Gauge != nil:
+		return m.Gauge.GetValue(), true
+	case m.Counter != nil:
+		return m.Counter.GetValue(), true
+	case m.Untyped != nil:
+		return m.Untyped.GetValue(), true
+	}
+	return 0, false
+}
+
+// cadvisorSummary arranges the cgroups scraped from cadvisor into a summary.
+// It also returns the cgroups keyed by cgroupKey. Pods that are not part of
+// the pods metadata, e.g. because they have just been deleted, are left out
+// since their UID is unknown.
+func cadvisorSummary(
+	logger *zap.Logger, cgroups map[cgroupRef]*cgroupStats, metadata Metadata,
+) (*stats.Summary, map[string]*cgroupStats) {
+	byKey := map[string]*cgroupStats{}
+
+	node := cgroups[cgroupRef{}]
+	if node == nil {
+		node = &cgroupStats{}
+	}
+	byKey[cgroupKey("", "")] = node
+	summary := &stats.Summary{
+		Node: stats.NodeStats{
+			NodeName:  metadata.nodeName(),
+			StartTime: node.startTime,
+			CPU:       node.cpuStats(),
+			Memory:    node.memoryStats(),
+			Network:   node.networkStats(),
+			Fs:        node.fsStats(),
+		},
+	}
+
+	containers := map[cgroupRef][]string{}
+	for ref := range cgroups {
+		if ref.pod == "" {
+			continue
+		}
+		podRef := cgroupRef{namespace: ref.namespace, pod: ref.pod}
+		if ref.container == "" {
+			if _, ok := containers[podRef]; !ok {
+				containers[podRef] = nil
+			}
+			continue
+		}
+		containers[podRef] = append(containers[podRef], ref.container)
+	}
+
+	podRefs := make([]cgroupRef, 0, len(containers))
+	for podRef := range containers {
+		podRefs = append(podRefs, podRef)
+	}
+	sort.Slice(podRefs, func(i, j int) bool {
+		if podRefs[i].namespace != podRefs[j].namespace {
+			return podRefs[i].namespace < podRefs[j].namespace
+		}
+		return podRefs[i].pod < podRefs[j].pod
+	})
+
+	for _, podRef := range podRefs {
+		pod := metadata.getPodByName(podRef.namespace, podRef.pod)
+		if pod == nil {
+			logger.Debug("pod not found in the fetched metadata, skipping its cadvisor metrics",
+				zap.String("namespace", podRef.namespace), zap.String("pod", podRef.pod))
+			continue
+		}
+		uid := string(pod.UID)
+
+		cs := cgroups[podRef]
+		if cs == nil {
+			cs = &cgroupStats{}
+		}
+		byKey[cgroupKey(uid, "")] = cs
+		podStats := stats.PodStats{
+			PodRef: stats.PodReference{
+				Name:      podRef.pod,
+				Namespace: podRef.namespace,
+				UID:       uid,
+			},
+			StartTime:        cs.startTime,
+			CPU:              cs.cpuStats(),
+			Memory:           cs.memoryStats(),
+			Network:          cs.networkStats(),
+			EphemeralStorage: cs.fsStats(),
+		}
+
+		names := containers[podRef]
+		sort.Strings(names)
+		for _, name := range names {
+			cs := cgroups[cgroupRef{namespace: podRef.namespace, pod: podRef.pod, container: name}]
+			byKey[cgroupKey(uid, name)] = cs
+			podStats.Containers = append(podStats.Containers, stats.ContainerStats{
+				Name:      name,
+				StartTime: cs.startTime,
+				CPU:       cs.cpuStats(),
+				Memory:    cs.memoryStats(),
+				Rootfs:    cs.fsStats(),
+			})
+		}
+		summary.Pods = append(summary.Pods, podStats)
+	}
+
+	return summary, byKey
+}
+
+// cadvisorExtraMetrics returns a function building the extra metrics of the
+// resources the accumulator collects metrics for.
+func cadvisorExtraMetrics(cgroups map[string]*cgroupStats) func(MetricGroup, *resourcepb.Resource) []*metricspb.Metric {
+	return func(group MetricGroup, r *resourcepb.Resource) []*metricspb.Metric {
+		var key, prefix string
+		switch group {
+		case NodeMetricGroup:
+			key, prefix = cgroupKey("", ""), nodePrefix
+		case PodMetricGroup:
+			key, prefix = cgroupKey(r.Labels[conventions.AttributeK8sPodUID], ""), podPrefix
+		case ContainerMetricGroup:
+			key = cgroupKey(r.Labels[conventions.AttributeK8sPodUID], r.Labels[conventions.AttributeK8sContainer])
+			prefix = containerPrefix
+		default:
+			return nil
+		}
+
+		cs := cgroups[key]
+		if cs == nil {
+			return nil
+		}
+		metrics := make([]*metricspb.Metric, 0, len(cs.extraMetrics))
+		for _, build := range cs.extraMetrics {
+			metrics = append(metrics, build(prefix))
+		}
+		return metrics
+	}
+}
+
+// cgroupKey identifies the cgroup of a resource by the UID of its pod and the
+// name of its container. Both are empty for the node.
+func cgroupKey(podUID string, container string) string {
+	if container == "" {
+		return podUID
+	}
+	return podUID + "/" + container
+}
+
+func (cs *cgroupStats) networkInterface(name string) *stats.InterfaceStats {
+	if cs.interfaces == nil {
+		cs.interfaces = map[string]*stats.InterfaceStats{}
+	}
+	i := cs.interfaces[name]
+	if i == nil {
+		i = &stats.InterfaceStats{Name: name}
+		cs.interfaces[name] = i
+	}
+	return i
+}
+
+// cpuUsage returns the cumulative CPU usage of the cgroup, in seconds.
+func (cs *cgroupStats) cpuUsage() *float64 {
+	if cs.cpuTotal != nil {
+		return cs.cpuTotal
+	}
+	return cs.cpuPerCore
+}
+
+func (cs *cgroupStats) cpuStats() *stats.CPUStats {
+	out := &stats.CPUStats{UsageNanoCores: cs.usageNanoCores}
+	if usage := cs.cpuUsage(); usage != nil {
+		out.UsageCoreNanoSeconds = uint64Value(*usage * 1_000_000_000)
+	}
+	return out
+}
+
+func (cs *cgroupStats) memoryStats() *stats.MemoryStats {
+	out := cs.memory
+	if cs.memoryLimit != nil && out.WorkingSetBytes != nil && *out.WorkingSetBytes <= *cs.memoryLimit {
+		available := *cs.memoryLimit - *out.WorkingSetBytes
+		out.AvailableBytes = &available
+	}
+	return &out
+}
+
+// networkStats reports eth0 as the default interface, as the summary does,
+// or the first interface when there is no eth0.
+func (cs *cgroupStats) networkStats() *stats.NetworkStats {
+	names := make([]string, 0, len(cs.interfaces))
+	for name := range cs.interfaces {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	out := &stats.NetworkStats{}
+	for _, name := range names {
+		out.Interfaces = append(out.Interfaces, *cs.interfaces[name])
+	}
+	if i, ok := cs.interfaces["eth0"]; ok {
+		out.InterfaceStats = *i
+	} else if len(out.Interfaces) > 0 {
+		out.InterfaceStats = out.Interfaces[0]
+	}
+	return out
+}
+
+func (cs *cgroupStats) fsStats() *stats.FsStats {
+	out := cs.fs
+	return &out
+}
+
+func uint64Value(value float64) *uint64 {
+	out := uint64(value)
+	return &out
+}
+
+func addUint64(total *uint64, value float64) *uint64 {
+	out := uint64(value)
+	if total != nil {
+		out += *total
+	}
+	return &out
+}
@@ -0,0 +1,280 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+var cadvisorMetricGroups = map[MetricGroup]bool{
+	ContainerMetricGroup: true,
+	PodMetricGroup:       true,
+	NodeMetricGroup:      true,
+}
+
+func cadvisorMetrics(t *testing.T, metadataLabels []MetadataLabel) []consumerdata.MetricsData {
+	rc := &fakeRestClient{}
+	families, err := NewCadvisorProvider(rc).Cadvisor()
+	require.NoError(t, err)
+	podsMetadata, err := NewMetadataProvider(rc).Pods()
+	require.NoError(t, err)
+	metadata := NewMetadata(metadataLabels, podsMetadata, nil)
+	return CadvisorMetricsData(zap.NewNop(), families, metadata, "foo", cadvisorMetricGroups, nil)
+}
+
+// findCadvisorMetric returns the metric with the given name and label values
+// of the resource with the given label value.
+func findCadvisorMetric(
+	t *testing.T, mds []consumerdata.MetricsData,
+	resourceLabel, resourceValue, name string, labels map[string]string,
+) *metricspb.Metric {
+	for _, md := range mds {
+		if md.Resource.Labels[resourceLabel] != resourceValue {
+			continue
+		}
+	metrics:
+		for _, metric := range md.Metrics {
+			if metric.MetricDescriptor.Name != name {
+				continue
+			}
+			for i, key := range metric.MetricDescriptor.LabelKeys {
+				if v, ok := labels[key.Key]; ok && metric.Timeseries[0].LabelValues[i].Value != v {
+					continue metrics
+				}
+			}
+			return metric
+		}
+	}
+	require.Failf(t, "metric not found", "%s of %s=%s with %v", name, resourceLabel, resourceValue, labels)
+	return nil
+}
+
+func TestCadvisorMetricsData(t *testing.T) {
+	mds := cadvisorMetrics(t, []MetadataLabel{MetadataLabelContainerID})
+	requireMetricsDataOk(t, mds)
+
+	// A node, 3 pods and 2 containers.
+	require.Len(t, mds, 6)
+
+	node := mds[0]
+	require.Equal(t, "minikube", node.Resource.Labels["k8s.node.name"])
+	require.Equal(t, "foo", node.Resource.Labels["receiver"])
+
+	metric := findCadvisorMetric(t, mds, "k8s.node.name", "minikube", "k8s.node.cpu.time", nil)
+	require.Equal(t, 2231.591236476, metric.Timeseries[0].Points[0].GetDoubleValue())
+	metric = findCadvisorMetric(t, mds, "k8s.node.name", "minikube", "k8s.node.memory.available", nil)
+	require.Equal(t, int64(2090491904-1190686720), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "k8s.node.name", "minikube", "k8s.node.filesystem.usage", nil)
+	require.Equal(t, int64(4071485440+8192), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "k8s.node.name", "minikube", "k8s.node.network.io",
+		map[string]string{"interface": "eth0", "direction": "receive"})
+	require.Equal(t, int64(406357393), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "k8s.node.name", "minikube", "k8s.node.disk.io",
+		map[string]string{"device": "/dev/sdb", "direction": "read"})
+	require.Equal(t, int64(1228800), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "k8s.node.name", "minikube", "k8s.node.memory.oom_events", nil)
+	require.Equal(t, int64(1), metric.Timeseries[0].Points[0].GetInt64Value())
+
+	// The network of the pod is reported for its pause container.
+	podUID := "42ad382b-ed0b-446d-9aab-3fdce8b4f9e2"
+	metric = findCadvisorMetric(t, mds, "k8s.pod.uid", podUID, "k8s.pod.network.io",
+		map[string]string{"interface": "eth0", "direction": "transmit"})
+	require.Equal(t, int64(1010), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "k8s.pod.uid", podUID, "k8s.pod.cpu.throttled_periods", nil)
+	require.Equal(t, int64(12), metric.Timeseries[0].Points[0].GetInt64Value())
+
+	metric = findCadvisorMetric(t, mds, "container.id", "c3d470faf18eba2b", "container.cpu.throttled_time", nil)
+	require.Equal(t, 0.354128, metric.Timeseries[0].Points[0].GetDoubleValue())
+	metric = findCadvisorMetric(t, mds, "container.id", "c3d470faf18eba2b", "container.memory.working_set", nil)
+	require.Equal(t, int64(25083904), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "container.id", "c3d470faf18eba2b", "container.disk.operations",
+		map[string]string{"device": "/dev/sda", "direction": "write"})
+	require.Equal(t, int64(1), metric.Timeseries[0].Points[0].GetInt64Value())
+	metric = findCadvisorMetric(t, mds, "container.id", "c3d470faf18eba2b", "container.memory.oom_events", nil)
+	require.Equal(t, int64(1), metric.Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "server", mds[2].Resource.Labels["k8s.container.name"])
+}
+
+func TestCadvisorMetricsDataWithoutPodsMetadata(t *testing.T) {
+	families, err := NewCadvisorProvider(&fakeRestClient{}).Cadvisor()
+	require.NoError(t, err)
+
+	// Pods can't be identified without metadata, only the node is reported.
+	mds := CadvisorMetricsData(zap.NewNop(), families, NewMetadata(nil, nil, nil), "", cadvisorMetricGroups, nil)
+	require.Len(t, mds, 1)
+	require.Equal(t, "", mds[0].Resource.Labels["k8s.node.name"])
+}
+
+func TestCadvisorMetricsDataMetricGroups(t *testing.T) {
+	families, err := NewCadvisorProvider(&fakeRestClient{}).Cadvisor()
+	require.NoError(t, err)
+	podsMetadata, err := NewMetadataProvider(&fakeRestClient{}).Pods()
+	require.NoError(t, err)
+	metadata := NewMetadata(nil, podsMetadata, nil)
+
+	mds := CadvisorMetricsData(zap.NewNop(), families, metadata, "", map[MetricGroup]bool{}, nil)
+	require.Len(t, mds, 0)
+
+	// Only the memory utilization can be computed without the CPU usage of a
+	// previous scrape.
+	mds = CadvisorMetricsData(zap.NewNop(), families, metadata, "", map[MetricGroup]bool{
+		UtilizationMetricGroup: true,
+	}, NewCadvisorCPUUsage())
+	require.Len(t, mds, 2)
+	for _, md := range mds {
+		require.Len(t, md.Metrics, 2)
+		for _, metric := range md.Metrics {
+			require.Contains(t, []string{
+				"k8s.pod.memory.request_utilization",
+				"k8s.pod.memory.limit_utilization",
+				"container.memory.request_utilization",
+				"container.memory.limit_utilization",
+			}, metric.MetricDescriptor.Name)
+		}
+	}
+}
+
+func TestCadvisorCgroupRef(t *testing.T) {
+	tests := []struct {
+		name   string
+		series string
+		labels map[string]string
+		want   cgroupRef
+		ok     bool
+	}{
+		{
+			name:   "node",
+			series: "container_memory_rss",
+			labels: map[string]string{"id": "/"},
+			want:   cgroupRef{},
+			ok:     true,
+		},
+		{
+			name:   "machine",
+			series: "machine_memory_bytes",
+			labels: map[string]string{},
+			want:   cgroupRef{},
+			ok:     true,
+		},
+		{
+			name:   "system cgroup",
+			series: "container_memory_rss",
+			labels: map[string]string{"id": "/system.slice/docker.service"},
+		},
+		{
+			name:   "container",
+			series: "container_memory_rss",
+			labels: map[string]string{"id": "/kubepods/pod1/c1", "namespace": "ns", "pod": "p", "container": "c"},
+			want:   cgroupRef{namespace: "ns", pod: "p", container: "c"},
+			ok:     true,
+		},
+		{
+			name:   "legacy container labels",
+			series: "container_memory_rss",
+			labels: map[string]string{"id": "/kubepods/pod1/c1", "namespace": "ns", "pod_name": "p", "container_name": "c"},
+			want:   cgroupRef{namespace: "ns", pod: "p", container: "c"},
+			ok:     true,
+		},
+		{
+			name:   "pause container network",
+			series: "container_network_receive_bytes_total",
+			labels: map[string]string{"id": "/kubepods/pod1/c0", "namespace": "ns", "pod": "p", "container": "POD"},
+			want:   cgroupRef{namespace: "ns", pod: "p"},
+			ok:     true,
+		},
+		{
+			name:   "pause container memory",
+			series: "container_memory_rss",
+			labels: map[string]string{"id": "/kubepods/pod1/c0", "namespace": "ns", "pod": "p", "container": "POD"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ref, ok := cadvisorCgroupRef(tt.series, tt.labels)
+			require.Equal(t, tt.ok, ok)
+			require.Equal(t, tt.want, ref)
+		})
+	}
+}
+
+func TestCadvisorCPUPerCore(t *testing.T) {
+	cs := &cgroupStats{}
+	handle := cadvisorHandlers["container_cpu_usage_seconds_total"]
+	handle(cs, map[string]string{"cpu": "cpu00"}, 1.5)
+	handle(cs, map[string]string{"cpu": "cpu01"}, 2)
+	require.Equal(t, uint64(3_500_000_000), *cs.cpuStats().UsageCoreNanoSeconds)
+
+	handle(cs, map[string]string{"cpu": "total"}, 4)
+	require.Equal(t, uint64(4_000_000_000), *cs.cpuStats().UsageCoreNanoSeconds)
+}
+
+func TestCadvisorCPUUsage(t *testing.T) {
+	u := NewCadvisorCPUUsage()
+	now := time.Unix(1600000000, 0)
+	ref := cgroupRef{namespace: "ns", pod: "p", container: "c"}
+	usage := func(seconds float64, startTime int64) map[cgroupRef]*cgroupStats {
+		return map[cgroupRef]*cgroupStats{
+			ref: {cpuTotal: &seconds, startTime: metav1.NewTime(time.Unix(startTime, 0))},
+		}
+	}
+
+	// The rate is unknown on the first scrape.
+	cgroups := usage(10, 100)
+	u.update(cgroups, now)
+	require.Nil(t, cgroups[ref].cpuStats().UsageNanoCores)
+
+	cgroups = usage(15, 100)
+	u.update(cgroups, now.Add(10*time.Second))
+	require.Equal(t, uint64(500_000_000), *cgroups[ref].cpuStats().UsageNanoCores)
+
+	// Restarted cgroups start over.
+	cgroups = usage(1, 200)
+	u.update(cgroups, now.Add(20*time.Second))
+	require.Nil(t, cgroups[ref].cpuStats().UsageNanoCores)
+
+	cgroups = usage(3, 200)
+	u.update(cgroups, now.Add(30*time.Second))
+	require.Equal(t, uint64(200_000_000), *cgroups[ref].cpuStats().UsageNanoCores)
+
+	// Cgroups that are gone are forgotten.
+	u.update(map[cgroupRef]*cgroupStats{}, now.Add(40*time.Second))
+	require.Empty(t, u.samples)
+}
+
+type failingCadvisorClient struct {
+	fakeRestClient
+	body []byte
+	err  error
+}
+
+func (f failingCadvisorClient) Cadvisor() ([]byte, error) {
+	return f.body, f.err
+}
+
+func TestCadvisorProviderErrors(t *testing.T) {
+	_, err := NewCadvisorProvider(failingCadvisorClient{err: errors.New("failed")}).Cadvisor()
+	require.EqualError(t, err, "failed")
+
+	_, err = NewCadvisorProvider(failingCadvisorClient{body: []byte("container_memory_rss{ 1")}).Cadvisor()
+	require.Error(t, err)
+}
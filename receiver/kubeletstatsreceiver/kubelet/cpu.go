@@ -36,6 +36,10 @@ func cpuUsageMetric(prefix string, s *stats.CPUStats) *metricspb.Metric {
 }
 
 func cpuCumulativeUsageMetric(prefix string, s *stats.CPUStats) *metricspb.Metric {
-	value := float64(*s.UsageCoreNanoSeconds) / 1_000_000_000
+	nanoSeconds := s.UsageCoreNanoSeconds
+	if nanoSeconds == nil {
+		return nil
+	}
+	value := float64(*nanoSeconds) / 1_000_000_000
 	return cumulativeDouble(prefix+"cpu.time", "s", &value)
 }
@@ -123,6 +123,49 @@ func (m *Metadata) getContainerID(podUID string, containerName string) (string,
 	return "", fmt.Errorf("pod %q with container %q not found in the fetched metadata", podUID, containerName)
 }
 
+// getPod returns the pod with the given UID from the fetched metadata, or nil
+// if it was not fetched or not found.
+func (m *Metadata) getPod(podUID string) *v1.Pod {
+	if m.PodsMetadata == nil {
+		return nil
+	}
+	uid := types.UID(podUID)
+	for i := range m.PodsMetadata.Items {
+		if m.PodsMetadata.Items[i].UID == uid {
+			return &m.PodsMetadata.Items[i]
+		}
+	}
+	return nil
+}
+
+// getPodByName returns the pod with the given namespace and name from the
+// fetched metadata, or nil if it was not fetched or not found.
+func (m *Metadata) getPodByName(namespace string, name string) *v1.Pod {
+	if m.PodsMetadata == nil {
+		return nil
+	}
+	for i := range m.PodsMetadata.Items {
+		pod := &m.PodsMetadata.Items[i]
+		if pod.Namespace == namespace && pod.Name == name {
+			return pod
+		}
+	}
+	return nil
+}
+
+// nodeName returns the name of the node the fetched pods are scheduled on.
+func (m *Metadata) nodeName() string {
+	if m.PodsMetadata == nil {
+		return ""
+	}
+	for _, pod := range m.PodsMetadata.Items {
+		if pod.Spec.NodeName != "" {
+			return pod.Spec.NodeName
+		}
+	}
+	return ""
+}
+
 var containerSchemeRegexp = regexp.MustCompile(`^[\w_-]+://`)
 
 // stripContainerID returns a pure container id without the runtime scheme://
@@ -43,6 +43,10 @@ func (f testRestClient) Pods() ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
+func (f testRestClient) Cadvisor() ([]byte, error) {
+	return []byte{}, nil
+}
+
 func TestPods(t *testing.T) {
 	tests := []struct {
 		name      string
@@ -32,23 +32,26 @@ func MetricsData(
 		metricGroupsToCollect: metricGroupsToCollect,
 		time:                  time.Now(),
 	}
+	return acc.metricsData(summary, typeStr)
+}
 
-	acc.nodeStats(summary.Node)
+func (a *metricDataAccumulator) metricsData(summary *stats.Summary, typeStr string) []consumerdata.MetricsData {
+	a.nodeStats(summary.Node)
 	for _, podStats := range summary.Pods {
 		// propagate the pod resource down to the container
 		podResource := podResource(podStats)
-		acc.podStats(podResource, podStats)
+		a.podStats(podResource, podStats)
 		for _, containerStats := range podStats.Containers {
-			acc.containerStats(podResource, containerStats)
+			a.containerStats(podResource, containerStats)
 		}
 
 		for _, volumeStats := range podStats.VolumeStats {
-			acc.volumeStats(podResource, volumeStats)
+			a.volumeStats(podResource, volumeStats)
 		}
 	}
-	for _, md := range acc.m {
+	for _, md := range a.m {
 		// TODO this should prob go in core
 		md.Resource.Labels["receiver"] = typeStr
 	}
-	return acc.m
+	return a.m
 }
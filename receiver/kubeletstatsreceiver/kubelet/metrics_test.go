@@ -36,6 +36,10 @@ func (f fakeRestClient) Pods() ([]byte, error) {
 	return ioutil.ReadFile("../testdata/pods.json")
 }
 
+func (f fakeRestClient) Cadvisor() ([]byte, error) {
+	return ioutil.ReadFile("../testdata/cadvisor.txt")
+}
+
 func TestMetricAccumulator(t *testing.T) {
 	rc := &fakeRestClient{}
 	statsProvider := NewStatsProvider(rc)
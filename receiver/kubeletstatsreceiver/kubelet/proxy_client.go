@@ -0,0 +1,62 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"context"
+	"time"
+
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+var _ Client = (*apiServerProxyClient)(nil)
+
+// apiServerProxyClient reaches the kubelet of a node through the API server
+// proxy, so that the kubelet doesn't need to be reachable from the collector.
+type apiServerProxyClient struct {
+	ctx        context.Context
+	restClient rest.Interface
+	nodeName   string
+	timeout    time.Duration
+}
+
+// NewAPIServerProxyClient returns a Client sending requests to the kubelet of
+// the given node through /api/v1/nodes/<node>/proxy, authenticated as client.
+// Requests are canceled with ctx, or after timeout if it is positive, so that a
+// hung kubelet doesn't block the scrapes.
+func NewAPIServerProxyClient(ctx context.Context, client kubernetes.Interface, nodeName string, timeout time.Duration) Client {
+	return &apiServerProxyClient{
+		ctx:        ctx,
+		restClient: client.CoreV1().RESTClient(),
+		nodeName:   nodeName,
+		timeout:    timeout,
+	}
+}
+
+func (c *apiServerProxyClient) Get(path string) ([]byte, error) {
+	ctx := c.ctx
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	return c.restClient.Get().
+		Resource("nodes").
+		Name(c.nodeName).
+		SubResource("proxy").
+		Suffix(path).
+		DoRaw(ctx)
+}
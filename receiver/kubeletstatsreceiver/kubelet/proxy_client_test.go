@@ -0,0 +1,70 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+	"k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/rest"
+)
+
+func TestAPIServerProxyClient(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/api/v1/nodes/missing/proxy/stats/summary" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		_, _ = w.Write([]byte(r.URL.Path))
+	}))
+	defer server.Close()
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	rc := NewRestClient(NewAPIServerProxyClient(context.Background(), client, "node-1", time.Second))
+	resp, err := rc.StatsSummary()
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/nodes/node-1/proxy/stats/summary", string(resp))
+	resp, err = rc.Pods()
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/nodes/node-1/proxy/pods", string(resp))
+	resp, err = rc.Cadvisor()
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/nodes/node-1/proxy/metrics/cadvisor", string(resp))
+
+	_, err = NewRestClient(NewAPIServerProxyClient(context.Background(), client, "missing", time.Second)).StatsSummary()
+	require.Error(t, err)
+}
+
+func TestAPIServerProxyClientTimeout(t *testing.T) {
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-release
+	}))
+	defer server.Close()
+	defer close(release)
+
+	client, err := kubernetes.NewForConfig(&rest.Config{Host: server.URL})
+	require.NoError(t, err)
+
+	_, err = NewRestClient(NewAPIServerProxyClient(context.Background(), client, "node-1", 10*time.Millisecond)).StatsSummary()
+	require.Error(t, err)
+}
@@ -19,6 +19,7 @@ import (
 
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/pkg/errors"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/translator/conventions"
 	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
 )
@@ -98,3 +99,11 @@ func volumeResource(pod *resourcepb.Resource, vs stats.VolumeStats, metadata Met
 		Labels: labels,
 	}, nil
 }
+
+// SetNodeName labels all the resources with the name of the node they were
+// collected from, since only the node resource is labeled with it otherwise.
+func SetNodeName(mds []consumerdata.MetricsData, nodeName string) {
+	for _, md := range mds {
+		md.Resource.Labels[labelNodeName] = nodeName
+	}
+}
@@ -18,12 +18,14 @@ package kubelet
 type RestClient interface {
 	StatsSummary() ([]byte, error)
 	Pods() ([]byte, error)
+	Cadvisor() ([]byte, error)
 }
 
 // RestClient is a thin wrapper around a kubelet client, encapsulating endpoints
 // and their corresponding http methods. The endpoints /stats/container /spec/
 // are excluded because they require cadvisor. The /metrics endpoint is excluded
-// because it returns Prometheus data.
+// because it returns Prometheus data about the kubelet itself, whereas
+// /metrics/cadvisor returns Prometheus data about the containers.
 type HTTPRestClient struct {
 	client Client
 }
@@ -39,3 +41,7 @@ func (c *HTTPRestClient) StatsSummary() ([]byte, error) {
 func (c *HTTPRestClient) Pods() ([]byte, error) {
 	return c.client.Get("/pods")
 }
+
+func (c *HTTPRestClient) Cadvisor() ([]byte, error) {
+	return c.client.Get("/metrics/cadvisor")
+}
@@ -26,6 +26,8 @@ func TestRestClient(t *testing.T) {
 	require.Equal(t, "/stats/summary", string(resp))
 	resp, _ = rest.Pods()
 	require.Equal(t, "/pods", string(resp))
+	resp, _ = rest.Cadvisor()
+	require.Equal(t, "/metrics/cadvisor", string(resp))
 }
 
 var _ Client = (*fakeClient)(nil)
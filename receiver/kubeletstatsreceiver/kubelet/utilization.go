@@ -0,0 +1,114 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
+)
+
+// utilizationMetrics returns the CPU and memory usage as a percentage of the
+// given requests and limits. Memory usage is the working set, which is what
+// the kubelet compares to limits when evicting pods. No metric is returned
+// for a resource without a request or a limit, or without usage stats.
+func utilizationMetrics(
+	prefix string, cpu *stats.CPUStats, mem *stats.MemoryStats, requests, limits v1.ResourceList,
+) []*metricspb.Metric {
+	var cpuUsage, memUsage *float64
+	if cpu != nil && cpu.UsageNanoCores != nil {
+		cores := float64(*cpu.UsageNanoCores) / 1_000_000_000
+		cpuUsage = &cores
+	}
+	if mem != nil && mem.WorkingSetBytes != nil {
+		bytes := float64(*mem.WorkingSetBytes)
+		memUsage = &bytes
+	}
+
+	var out []*metricspb.Metric
+	for _, m := range []*metricspb.Metric{
+		doubleGauge(prefix+"cpu.request_utilization", "%", percentOf(cpuUsage, requests, v1.ResourceCPU)),
+		doubleGauge(prefix+"cpu.limit_utilization", "%", percentOf(cpuUsage, limits, v1.ResourceCPU)),
+		doubleGauge(prefix+"memory.request_utilization", "%", percentOf(memUsage, requests, v1.ResourceMemory)),
+		doubleGauge(prefix+"memory.limit_utilization", "%", percentOf(memUsage, limits, v1.ResourceMemory)),
+	} {
+		if m != nil {
+			out = append(out, m)
+		}
+	}
+	return out
+}
+
+// percentOf returns usage as a percentage of the quantity of name in list, or
+// nil if either is unknown. CPU quantities are in cores and memory ones in bytes.
+func percentOf(usage *float64, list v1.ResourceList, name v1.ResourceName) *float64 {
+	if usage == nil {
+		return nil
+	}
+	q, ok := list[name]
+	if !ok || q.IsZero() {
+		return nil
+	}
+
+	var total float64
+	if name == v1.ResourceCPU {
+		total = float64(q.MilliValue()) / 1000
+	} else {
+		total = float64(q.Value())
+	}
+	percent := *usage / total * 100
+	return &percent
+}
+
+// podResourceRequirements returns the requests and limits of a pod, summed over
+// its containers. A pod only has a limit for a resource if all its containers do.
+func podResourceRequirements(pod *v1.Pod) (requests, limits v1.ResourceList) {
+	requests = v1.ResourceList{}
+	limits = v1.ResourceList{}
+	for _, name := range []v1.ResourceName{v1.ResourceCPU, v1.ResourceMemory} {
+		limited := len(pod.Spec.Containers) > 0
+		request := resource.Quantity{}
+		limit := resource.Quantity{}
+		for _, c := range pod.Spec.Containers {
+			if q, ok := c.Resources.Requests[name]; ok {
+				request.Add(q)
+			}
+			if q, ok := c.Resources.Limits[name]; ok {
+				limit.Add(q)
+			} else {
+				limited = false
+			}
+		}
+		if !request.IsZero() {
+			requests[name] = request
+		}
+		if limited {
+			limits[name] = limit
+		}
+	}
+	return requests, limits
+}
+
+// containerResourceRequirements returns the resources of the container with
+// the given name, or false if it is not part of pod.
+func containerResourceRequirements(pod *v1.Pod, name string) (v1.ResourceRequirements, bool) {
+	for _, c := range pod.Spec.Containers {
+		if c.Name == name {
+			return c.Resources, true
+		}
+	}
+	return v1.ResourceRequirements{}, false
+}
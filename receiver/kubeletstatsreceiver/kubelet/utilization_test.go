@@ -0,0 +1,127 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package kubelet
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	v1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/resource"
+	stats "k8s.io/kubernetes/pkg/kubelet/apis/stats/v1alpha1"
+)
+
+func TestUtilizationMetrics(t *testing.T) {
+	nanoCores := uint64(250_000_000)
+	workingSet := uint64(32 * 1024 * 1024)
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("500m"),
+		v1.ResourceMemory: resource.MustParse("64Mi"),
+	}
+	limits := v1.ResourceList{
+		v1.ResourceCPU: resource.MustParse("1"),
+	}
+
+	metrics := utilizationMetrics(
+		containerPrefix,
+		&stats.CPUStats{UsageNanoCores: &nanoCores},
+		&stats.MemoryStats{WorkingSetBytes: &workingSet},
+		requests,
+		limits,
+	)
+
+	values := map[string]float64{}
+	for _, m := range metrics {
+		assert.Equal(t, "%", m.MetricDescriptor.Unit)
+		values[m.MetricDescriptor.Name] = m.Timeseries[0].Points[0].GetDoubleValue()
+	}
+	require.Equal(t, map[string]float64{
+		"container.cpu.request_utilization":    50,
+		"container.cpu.limit_utilization":      25,
+		"container.memory.request_utilization": 50,
+	}, values)
+}
+
+func TestUtilizationMetricsWithoutUsage(t *testing.T) {
+	requests := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("500m"),
+		v1.ResourceMemory: resource.MustParse("64Mi"),
+	}
+	require.Empty(t, utilizationMetrics(podPrefix, nil, &stats.MemoryStats{}, requests, requests))
+}
+
+func TestPercentOf(t *testing.T) {
+	usage := 0.1
+	list := v1.ResourceList{
+		v1.ResourceCPU:    resource.MustParse("200m"),
+		v1.ResourceMemory: resource.MustParse("0"),
+	}
+
+	percent := percentOf(&usage, list, v1.ResourceCPU)
+	require.NotNil(t, percent)
+	require.InDelta(t, 50, *percent, 1e-9)
+
+	require.Nil(t, percentOf(nil, list, v1.ResourceCPU))
+	require.Nil(t, percentOf(&usage, list, v1.ResourceMemory))
+	require.Nil(t, percentOf(&usage, v1.ResourceList{}, v1.ResourceCPU))
+}
+
+func TestPodResourceRequirements(t *testing.T) {
+	pod := &v1.Pod{
+		Spec: v1.PodSpec{
+			Containers: []v1.Container{
+				{
+					Name: "app",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("100m"),
+							v1.ResourceMemory: resource.MustParse("64Mi"),
+						},
+						Limits: v1.ResourceList{
+							v1.ResourceCPU:    resource.MustParse("500m"),
+							v1.ResourceMemory: resource.MustParse("128Mi"),
+						},
+					},
+				},
+				{
+					Name: "sidecar",
+					Resources: v1.ResourceRequirements{
+						Requests: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("50m"),
+						},
+						Limits: v1.ResourceList{
+							v1.ResourceCPU: resource.MustParse("100m"),
+						},
+					},
+				},
+			},
+		},
+	}
+
+	requests, limits := podResourceRequirements(pod)
+	assert.Equal(t, int64(150), requests.Cpu().MilliValue())
+	assert.Equal(t, int64(64*1024*1024), requests.Memory().Value())
+	assert.Equal(t, int64(600), limits.Cpu().MilliValue())
+	// The sidecar has no memory limit, so neither does the pod.
+	_, ok := limits[v1.ResourceMemory]
+	assert.False(t, ok)
+
+	resources, ok := containerResourceRequirements(pod, "sidecar")
+	require.True(t, ok)
+	assert.Equal(t, int64(50), resources.Requests.Cpu().MilliValue())
+	_, ok = containerResourceRequirements(pod, "missing")
+	assert.False(t, ok)
+}
@@ -138,3 +138,12 @@ func getMockedObjectsWithNonExistentVolumeName() []runtime.Object {
 		volumeClaim3,
 	}
 }
+
+func getNode(name string) *v1.Node {
+	return &v1.Node{
+		ObjectMeta: metav1.ObjectMeta{
+			Name: name,
+			UID:  types.UID(name),
+		},
+	}
+}
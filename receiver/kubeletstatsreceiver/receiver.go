@@ -35,6 +35,7 @@ type receiver struct {
 	consumer consumer.MetricsConsumer
 	runner   *interval.Runner
 	rest     kubelet.RestClient
+	cancel   context.CancelFunc
 }
 
 type receiverOptions struct {
@@ -42,7 +43,9 @@ type receiverOptions struct {
 	collectionInterval    time.Duration
 	extraMetadataLabels   []kubelet.MetadataLabel
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
+	dataSource            kubelet.DataSource
 	k8sAPIClient          kubernetes.Interface
+	discoverNodes         bool
 }
 
 func newReceiver(rOptions *receiverOptions,
@@ -58,6 +61,9 @@ func newReceiver(rOptions *receiverOptions,
 
 // Creates and starts the kubelet stats runnable.
 func (r *receiver) Start(ctx context.Context, host component.Host) error {
+	// The context is canceled at shutdown to interrupt the scrapes of nodes
+	// that are spread over the collection interval.
+	ctx, r.cancel = context.WithCancel(ctx)
 	runnable := newRunnable(ctx, r.consumer, r.rest, r.logger, r.options)
 	r.runner = interval.NewRunner(r.options.collectionInterval, runnable)
 
@@ -72,5 +78,8 @@ func (r *receiver) Start(ctx context.Context, host component.Host) error {
 // Stops the kubelet stats runner.
 func (r *receiver) Shutdown(ctx context.Context) error {
 	r.runner.Stop()
+	if r.cancel != nil {
+		r.cancel()
+	}
 	return nil
 }
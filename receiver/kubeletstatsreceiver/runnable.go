@@ -17,8 +17,13 @@ package kubeletstatsreceiver
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
 
 	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
@@ -38,13 +43,29 @@ type runnable struct {
 	receiverName          string
 	statsProvider         *kubelet.StatsProvider
 	metadataProvider      *kubelet.MetadataProvider
+	cadvisorProvider      *kubelet.CadvisorProvider
+	cadvisorCPUUsage      *kubelet.CadvisorCPUUsage
 	consumer              consumer.MetricsConsumer
 	logger                *zap.Logger
 	restClient            kubelet.RestClient
 	extraMetadataLabels   []kubelet.MetadataLabel
 	metricGroupsToCollect map[kubelet.MetricGroup]bool
+	dataSource            kubelet.DataSource
 	k8sAPIClient          kubernetes.Interface
 	cachedVolumeLabels    map[string]map[string]string
+
+	// options and the fields below are only used when discovering nodes.
+	options            *receiverOptions
+	collectionInterval time.Duration
+	discoverNodes      bool
+	// nodeName is the node scraped by the runnable of a discovered node.
+	nodeName string
+	// scraping is 1 while the runnable of a discovered node is scraping it.
+	scraping int32
+	// nodeRunnables scrape the discovered nodes, keyed by node name.
+	nodeRunnables map[string]*runnable
+	// nodeRestClient returns the client reaching the kubelet of a node.
+	nodeRestClient func(nodeName string) kubelet.RestClient
 }
 
 func newRunnable(
@@ -62,8 +83,16 @@ func newRunnable(
 		logger:                logger,
 		extraMetadataLabels:   rOptions.extraMetadataLabels,
 		metricGroupsToCollect: rOptions.metricGroupsToCollect,
+		dataSource:            rOptions.dataSource,
 		k8sAPIClient:          rOptions.k8sAPIClient,
 		cachedVolumeLabels:    make(map[string]map[string]string),
+		options:               rOptions,
+		collectionInterval:    rOptions.collectionInterval,
+		discoverNodes:         rOptions.discoverNodes,
+		nodeRunnables:         make(map[string]*runnable),
+		nodeRestClient: func(nodeName string) kubelet.RestClient {
+			return kubelet.NewRestClient(kubelet.NewAPIServerProxyClient(ctx, rOptions.k8sAPIClient, nodeName, rOptions.collectionInterval))
+		},
 	}
 }
 
@@ -71,43 +100,168 @@ func newRunnable(
 func (r *runnable) Setup() error {
 	r.statsProvider = kubelet.NewStatsProvider(r.restClient)
 	r.metadataProvider = kubelet.NewMetadataProvider(r.restClient)
+	r.cadvisorProvider = kubelet.NewCadvisorProvider(r.restClient)
+	r.cadvisorCPUUsage = kubelet.NewCadvisorCPUUsage()
 	return nil
 }
 
 func (r *runnable) Run() error {
+	if r.discoverNodes {
+		r.runNodes()
+		return nil
+	}
+
 	const transport = "http"
+	var mds []consumerdata.MetricsData
+	var ok bool
+	if r.dataSource == kubelet.CadvisorDataSource {
+		mds, ok = r.cadvisorMetricsData()
+	} else {
+		mds, ok = r.summaryMetricsData()
+	}
+	if !ok {
+		return nil
+	}
+	if r.nodeName != "" {
+		kubelet.SetNodeName(mds, r.nodeName)
+	}
+	metrics := internaldata.OCSliceToMetrics(mds)
+
+	var numTimeSeries, numPoints int
+	ctx := obsreport.ReceiverContext(r.ctx, typeStr, transport, r.receiverName)
+	ctx = obsreport.StartMetricsReceiveOp(ctx, typeStr, transport)
+	err := r.consumer.ConsumeMetrics(ctx, metrics)
+	if err != nil {
+		r.logger.Error("ConsumeMetricsData failed", zap.Error(err))
+	} else {
+		numTimeSeries, numPoints = metrics.MetricAndDataPointCount()
+	}
+	obsreport.EndMetricsReceiveOp(ctx, typeStr, numTimeSeries, numPoints, err)
+
+	return nil
+}
+
+// runNodes scrapes the kubelets of all the nodes of the cluster, spreading the
+// scrapes evenly over the collection interval. Each node is scraped in its own
+// goroutine, so that a slow kubelet doesn't delay the others, and is skipped if
+// its previous scrape is still running. runNodes returns once all the scrapes
+// are done, or at the latest after the collection interval.
+func (r *runnable) runNodes() {
+	nodes, err := r.k8sAPIClient.CoreV1().Nodes().List(r.ctx, metav1.ListOptions{})
+	if err != nil {
+		r.logger.Error("failed to list nodes", zap.Error(err))
+		return
+	}
+
+	// Keep the runnables of known nodes, so that their caches are preserved,
+	// and forget the nodes that no longer exist.
+	nodeRunnables := make(map[string]*runnable, len(nodes.Items))
+	names := make([]string, 0, len(nodes.Items))
+	for _, node := range nodes.Items {
+		nr, ok := r.nodeRunnables[node.Name]
+		if !ok {
+			nr = r.newNodeRunnable(node.Name)
+		}
+		nodeRunnables[node.Name] = nr
+		names = append(names, node.Name)
+	}
+	r.nodeRunnables = nodeRunnables
+	sort.Strings(names)
+
+	if len(names) == 0 {
+		return
+	}
+	deadline := time.NewTimer(r.collectionInterval)
+	defer deadline.Stop()
+	spread := r.collectionInterval / time.Duration(len(names))
+	var wg sync.WaitGroup
+	for i, name := range names {
+		nr := r.nodeRunnables[name]
+		if !atomic.CompareAndSwapInt32(&nr.scraping, 0, 1) {
+			r.logger.Warn("skipping the scrape of a node whose previous scrape is still running",
+				zap.String("node", name))
+			continue
+		}
+		wg.Add(1)
+		go func(nr *runnable, offset time.Duration) {
+			defer wg.Done()
+			defer atomic.StoreInt32(&nr.scraping, 0)
+			if offset > 0 {
+				timer := time.NewTimer(offset)
+				defer timer.Stop()
+				select {
+				case <-timer.C:
+				case <-r.ctx.Done():
+					return
+				}
+			}
+			_ = nr.Run()
+		}(nr, time.Duration(i)*spread)
+	}
+
+	done := make(chan struct{})
+	go func() {
+		wg.Wait()
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-deadline.C:
+	case <-r.ctx.Done():
+	}
+}
+
+func (r *runnable) newNodeRunnable(nodeName string) *runnable {
+	options := *r.options
+	options.discoverNodes = false
+	nr := newRunnable(r.ctx, r.consumer, r.nodeRestClient(nodeName), r.logger.With(zap.String("node", nodeName)), &options)
+	nr.nodeName = nodeName
+	_ = nr.Setup()
+	return nr
+}
+
+// summaryMetricsData collects metrics from the /stats/summary endpoint. It
+// returns false if the kubelet could not be scraped.
+func (r *runnable) summaryMetricsData() ([]consumerdata.MetricsData, bool) {
 	summary, err := r.statsProvider.StatsSummary()
 	if err != nil {
 		r.logger.Error("call to /stats/summary endpoint failed", zap.Error(err))
-		return nil
+		return nil, false
 	}
 
 	var podsMetadata *v1.PodList
-	// fetch metadata only when extra metadata labels are needed
-	if len(r.extraMetadataLabels) > 0 {
+	// fetch metadata only when extra metadata labels or pod specs are needed
+	if len(r.extraMetadataLabels) > 0 || r.metricGroupsToCollect[kubelet.UtilizationMetricGroup] {
 		podsMetadata, err = r.metadataProvider.Pods()
 		if err != nil {
 			r.logger.Error("call to /pods endpoint failed", zap.Error(err))
-			return nil
+			return nil, false
 		}
 	}
 
 	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata, r.detailedPVCLabelsSetter())
-	mds := kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect)
-	metrics := internaldata.OCSliceToMetrics(mds)
+	return kubelet.MetricsData(r.logger, summary, metadata, typeStr, r.metricGroupsToCollect), true
+}
 
-	var numTimeSeries, numPoints int
-	ctx := obsreport.ReceiverContext(r.ctx, typeStr, transport, r.receiverName)
-	ctx = obsreport.StartMetricsReceiveOp(ctx, typeStr, transport)
-	err = r.consumer.ConsumeMetrics(ctx, metrics)
+// cadvisorMetricsData collects metrics from the /metrics/cadvisor endpoint. It
+// returns false if the kubelet could not be scraped.
+func (r *runnable) cadvisorMetricsData() ([]consumerdata.MetricsData, bool) {
+	families, err := r.cadvisorProvider.Cadvisor()
 	if err != nil {
-		r.logger.Error("ConsumeMetricsData failed", zap.Error(err))
-	} else {
-		numTimeSeries, numPoints = metrics.MetricAndDataPointCount()
+		r.logger.Error("call to /metrics/cadvisor endpoint failed", zap.Error(err))
+		return nil, false
 	}
-	obsreport.EndMetricsReceiveOp(ctx, typeStr, numTimeSeries, numPoints, err)
 
-	return nil
+	// cadvisor series only have the name of pods, so their UIDs are always
+	// taken from the pods metadata.
+	podsMetadata, err := r.metadataProvider.Pods()
+	if err != nil {
+		r.logger.Error("call to /pods endpoint failed", zap.Error(err))
+		return nil, false
+	}
+
+	metadata := kubelet.NewMetadata(r.extraMetadataLabels, podsMetadata, r.detailedPVCLabelsSetter())
+	return kubelet.CadvisorMetricsData(r.logger, families, metadata, typeStr, r.metricGroupsToCollect, r.cadvisorCPUUsage), true
 }
 
 func (r *runnable) detailedPVCLabelsSetter() func(volCacheID, volumeClaim, namespace string, labels map[string]string) error {
@@ -20,14 +20,18 @@ import (
 	"io/ioutil"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.opentelemetry.io/collector/translator/internaldata"
 	"go.uber.org/zap"
 	"go.uber.org/zap/zaptest/observer"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/kubernetes"
 	"k8s.io/client-go/kubernetes/fake"
+	k8stesting "k8s.io/client-go/testing"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/kubeletstatsreceiver/kubelet"
 )
@@ -46,8 +50,22 @@ const (
 	podMetrics       = 15
 	containerMetrics = 11
 	volumeMetrics    = 5
+
+	// Number of utilization metrics of the pods and containers with requests
+	// or limits in testdata/pods.json
+	utilizationMetrics = 10
 )
 
+// Number of metrics in testdata/cadvisor.txt, for the node, the go-hello-world,
+// kube-scheduler and kube-proxy pods, and the server and kube-scheduler containers.
+const cadvisorDataLen = 22 + 14 + 4 + 1 + 17 + 11
+
+var cadvisorMetricGroups = map[kubelet.MetricGroup]bool{
+	kubelet.ContainerMetricGroup: true,
+	kubelet.PodMetricGroup:       true,
+	kubelet.NodeMetricGroup:      true,
+}
+
 var allMetricGroups = map[kubelet.MetricGroup]bool{
 	kubelet.ContainerMetricGroup: true,
 	kubelet.PodMetricGroup:       true,
@@ -74,6 +92,101 @@ func TestRunnable(t *testing.T) {
 	require.Equal(t, dataLen, consumer.MetricsCount())
 }
 
+func TestRunnableWithCadvisorDataSource(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporter{}
+	options := &receiverOptions{
+		metricGroupsToCollect: cadvisorMetricGroups,
+		dataSource:            kubelet.CadvisorDataSource,
+	}
+	r := newRunnable(
+		context.Background(),
+		consumer,
+		&fakeRestClient{},
+		zap.NewNop(),
+		options,
+	)
+	err := r.Setup()
+	require.NoError(t, err)
+	err = r.Run()
+	require.NoError(t, err)
+	require.Equal(t, cadvisorDataLen, consumer.MetricsCount())
+}
+
+func TestRunnableWithDiscoveredNodes(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporter{}
+	client := fake.NewSimpleClientset(getNode("node-1"), getNode("node-2"))
+	options := &receiverOptions{
+		metricGroupsToCollect: allMetricGroups,
+		k8sAPIClient:          client,
+		discoverNodes:         true,
+		collectionInterval:    time.Second,
+	}
+	r := newRunnable(
+		context.Background(),
+		consumer,
+		nil,
+		zap.NewNop(),
+		options,
+	)
+	var scrapedNodes []string
+	// Node runnables are created sequentially, before their scrapes start.
+	r.nodeRestClient = func(nodeName string) kubelet.RestClient {
+		scrapedNodes = append(scrapedNodes, nodeName)
+		return &fakeRestClient{}
+	}
+	err := r.Setup()
+	require.NoError(t, err)
+	err = r.Run()
+	require.NoError(t, err)
+	require.Equal(t, 2*dataLen, consumer.MetricsCount())
+	require.ElementsMatch(t, []string{"node-1", "node-2"}, scrapedNodes)
+
+	nodeNames := map[string]int{}
+	for _, m := range consumer.AllMetrics() {
+		for _, md := range internaldata.MetricsToOC(m) {
+			nodeNames[md.Resource.Labels["k8s.node.name"]]++
+		}
+	}
+	require.Len(t, nodeNames, 2)
+	require.Equal(t, nodeNames["node-1"], nodeNames["node-2"])
+
+	// Deleted nodes are forgotten and the clients of known nodes are reused.
+	err = client.CoreV1().Nodes().Delete(context.Background(), "node-2", metav1.DeleteOptions{})
+	require.NoError(t, err)
+	consumer.Reset()
+	err = r.Run()
+	require.NoError(t, err)
+	require.Equal(t, dataLen, consumer.MetricsCount())
+	require.Len(t, r.nodeRunnables, 1)
+	require.Len(t, scrapedNodes, 2)
+}
+
+func TestRunnableWithDiscoveredNodesListError(t *testing.T) {
+	core, observedLogs := observer.New(zap.ErrorLevel)
+	client := fake.NewSimpleClientset(getNode("node-1"))
+	client.PrependReactor("list", "nodes", func(k8stesting.Action) (bool, runtime.Object, error) {
+		return true, nil, errors.New("failed")
+	})
+	consumer := &exportertest.SinkMetricsExporter{}
+	r := newRunnable(
+		context.Background(),
+		consumer,
+		nil,
+		zap.New(core),
+		&receiverOptions{
+			metricGroupsToCollect: allMetricGroups,
+			k8sAPIClient:          client,
+			discoverNodes:         true,
+		},
+	)
+	err := r.Setup()
+	require.NoError(t, err)
+	err = r.Run()
+	require.NoError(t, err)
+	require.Equal(t, 0, consumer.MetricsCount())
+	require.Equal(t, 1, observedLogs.FilterMessage("failed to list nodes").Len())
+}
+
 func TestRunnableWithMetadata(t *testing.T) {
 	tests := []struct {
 		name           string
@@ -186,6 +299,21 @@ func TestRunnableWithMetricGroups(t *testing.T) {
 			},
 			dataLen: numNodes*nodeMetrics + numPods*podMetrics,
 		},
+		{
+			name: "only utilization group",
+			metricGroups: map[kubelet.MetricGroup]bool{
+				kubelet.UtilizationMetricGroup: true,
+			},
+			dataLen: utilizationMetrics,
+		},
+		{
+			name: "container and utilization groups",
+			metricGroups: map[kubelet.MetricGroup]bool{
+				kubelet.ContainerMetricGroup:   true,
+				kubelet.UtilizationMetricGroup: true,
+			},
+			dataLen: numContainers*containerMetrics + utilizationMetrics,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -406,6 +534,8 @@ func TestClientErrors(t *testing.T) {
 		name                  string
 		statsSummaryFail      bool
 		podsFail              bool
+		cadvisorFail          bool
+		dataSource            kubelet.DataSource
 		extraMetadataLabels   []kubelet.MetadataLabel
 		metricGroupsToCollect map[kubelet.MetricGroup]bool
 		numLogs               int
@@ -442,6 +572,20 @@ func TestClientErrors(t *testing.T) {
 			metricGroupsToCollect: allMetricGroups,
 			numLogs:               1,
 		},
+		{
+			name:                  "cadvisor_endpoint_error",
+			cadvisorFail:          true,
+			dataSource:            kubelet.CadvisorDataSource,
+			metricGroupsToCollect: cadvisorMetricGroups,
+			numLogs:               1,
+		},
+		{
+			name:                  "cadvisor_pods_endpoint_error",
+			podsFail:              true,
+			dataSource:            kubelet.CadvisorDataSource,
+			metricGroupsToCollect: cadvisorMetricGroups,
+			numLogs:               1,
+		},
 	}
 	for _, test := range tests {
 		t.Run(test.name, func(t *testing.T) {
@@ -449,6 +593,7 @@ func TestClientErrors(t *testing.T) {
 			options := &receiverOptions{
 				extraMetadataLabels:   test.extraMetadataLabels,
 				metricGroupsToCollect: test.metricGroupsToCollect,
+				dataSource:            test.dataSource,
 			}
 			r := newRunnable(
 				context.Background(),
@@ -456,6 +601,7 @@ func TestClientErrors(t *testing.T) {
 				&fakeRestClient{
 					statsSummaryFail: test.statsSummaryFail,
 					podsFail:         test.podsFail,
+					cadvisorFail:     test.cadvisorFail,
 				},
 				zap.New(core),
 				options,
@@ -510,6 +656,7 @@ var _ kubelet.RestClient = (*fakeRestClient)(nil)
 type fakeRestClient struct {
 	statsSummaryFail bool
 	podsFail         bool
+	cadvisorFail     bool
 }
 
 func (f *fakeRestClient) StatsSummary() ([]byte, error) {
@@ -525,3 +672,10 @@ func (f *fakeRestClient) Pods() ([]byte, error) {
 	}
 	return ioutil.ReadFile("testdata/pods.json")
 }
+
+func (f *fakeRestClient) Cadvisor() ([]byte, error) {
+	if f.cadvisorFail {
+		return nil, errors.New("")
+	}
+	return ioutil.ReadFile("testdata/cadvisor.txt")
+}
@@ -54,6 +54,10 @@ type receiverTemplate struct {
 	// based on receiverTemplate.
 	Rule string `mapstructure:"rule"`
 	rule rule
+	// ResourceAttributes are added to the resources of data emitted by the receiver. They
+	// override the receiver_creator resource attributes of the same name for the matched
+	// endpoint type.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
 }
 
 // newReceiverTemplate creates a receiverTemplate instance from the full name of a subreceiver
@@ -79,8 +83,15 @@ type Config struct {
 	receiverTemplates             map[string]receiverTemplate
 	// WatchObservers are the extensions to listen to endpoints from.
 	WatchObservers []configmodels.Type `mapstructure:"watch_observers"`
+	// ResourceAttributes is a map of endpoint type (e.g. pod or port) to the resource attributes
+	// added to data emitted by receivers started for endpoints of that type. Values may contain
+	// expressions in backticks that are evaluated against the endpoint.
+	ResourceAttributes resourceAttributes `mapstructure:"resource_attributes"`
 }
 
+// resourceAttributes maps an endpoint type to resource attribute names and values.
+type resourceAttributes map[string]map[string]string
+
 // Copied from the Viper but changed to use the same delimiter.
 // See https://github.com/spf13/viper/issues/871
 func viperSub(v *viper.Viper, key string) *viper.Viper {
@@ -128,3 +128,23 @@ func expandMap(cfg map[string]interface{}, env observer.EndpointEnv) (map[string
 
 	return resolved, nil
 }
+
+// expandResourceAttributes expands any expressions in backticks inside the values of attrs using
+// env as variables available within the expression. Attributes that expand to nil or an empty
+// string are omitted.
+func expandResourceAttributes(attrs map[string]string, env observer.EndpointEnv) (map[string]string, error) {
+	resolved := map[string]string{}
+	for k, v := range attrs {
+		res, err := evalBackticksInConfigValue(v, env)
+		if err != nil {
+			return nil, fmt.Errorf("failed evaluating resource attribute expression for key %q: %v", k, err)
+		}
+		if res == nil {
+			continue
+		}
+		if val := fmt.Sprintf("%v", res); val != "" {
+			resolved[k] = val
+		}
+	}
+	return resolved, nil
+}
@@ -86,4 +86,8 @@ func TestLoadConfig(t *testing.T) {
 		endpointConfigKey: "localhost:12345",
 	}, r1.receiverTemplates["examplereceiver/1"].config)
 	assert.Equal(t, []configmodels.Type{"mock_observer"}, r1.WatchObservers)
+	assert.Equal(t, resourceAttributes{
+		"port": {"k8s.pod.name": "`pod.name`"},
+	}, r1.ResourceAttributes)
+	assert.Equal(t, map[string]string{"source": "example"}, r1.receiverTemplates["examplereceiver/1"].ResourceAttributes)
 }
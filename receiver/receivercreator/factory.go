@@ -17,6 +17,7 @@ package receivercreator
 import (
 	"context"
 	"fmt"
+	"sync"
 
 	"github.com/spf13/viper"
 	"go.opentelemetry.io/collector/component"
@@ -37,7 +38,9 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithCustomUnmarshaler(customUnmarshaler),
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithTraces(createTraceReceiver),
+		receiverhelper.WithLogs(createLogsReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -50,15 +53,66 @@ func createDefaultConfig() configmodels.Receiver {
 	}
 }
 
+// getOrCreateReceiverCreator returns the receiver_creator instance for the given config, creating
+// it if necessary. The same instance is returned for every data type so that subreceivers
+// supporting several data types are only started once.
+func getOrCreateReceiverCreator(params component.ReceiverCreateParams, cfg *Config) *receiverCreator {
+	receiversLock.Lock()
+	defer receiversLock.Unlock()
+
+	rc := receivers[cfg]
+	if rc == nil {
+		rc = newReceiverCreator(params.Logger, cfg)
+		receivers[cfg] = rc
+	}
+	return rc
+}
+
 func createMetricsReceiver(
 	ctx context.Context,
 	params component.ReceiverCreateParams,
 	cfg configmodels.Receiver,
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
-	return newReceiverCreator(params.Logger, consumer, cfg.(*Config))
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+	rc := getOrCreateReceiverCreator(params, cfg.(*Config))
+	rc.registerMetricsConsumer(consumer)
+	return rc, nil
 }
 
+func createTraceReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+	rc := getOrCreateReceiverCreator(params, cfg.(*Config))
+	rc.registerTracesConsumer(consumer)
+	return rc, nil
+}
+
+func createLogsReceiver(
+	ctx context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.LogsConsumer,
+) (component.LogsReceiver, error) {
+	if consumer == nil {
+		return nil, errNilNextConsumer
+	}
+	rc := getOrCreateReceiverCreator(params, cfg.(*Config))
+	rc.registerLogsConsumer(consumer)
+	return rc, nil
+}
+
+var receiversLock sync.Mutex
+var receivers = map[*Config]*receiverCreator{}
+
 func customUnmarshaler(sourceViperSection *viper.Viper, intoCfg interface{}) error {
 	if sourceViperSection == nil {
 		// Nothing to do if there is no config given.
@@ -20,7 +20,7 @@ import (
 
 	"github.com/stretchr/testify/assert"
 	"go.opentelemetry.io/collector/component"
-	"go.opentelemetry.io/collector/config/configerror"
+	"go.opentelemetry.io/collector/exporter/exportertest"
 	"go.uber.org/zap"
 )
 
@@ -37,8 +37,15 @@ func TestCreateReceiver(t *testing.T) {
 	assert.NoError(t, err, "receiver creation failed")
 	assert.NotNil(t, tReceiver, "receiver creation failed")
 
-	mReceiver, err := factory.CreateTraceReceiver(context.Background(), params, cfg, nil)
-	assert.Error(t, err)
-	assert.Equal(t, err, configerror.ErrDataTypeIsNotSupported)
-	assert.Nil(t, mReceiver)
+	trReceiver, err := factory.CreateTraceReceiver(context.Background(), params, cfg, nil)
+	assert.Equal(t, errNilNextConsumer, err)
+	assert.Nil(t, trReceiver)
+
+	trReceiver, err = factory.CreateTraceReceiver(context.Background(), params, cfg, new(exportertest.SinkTraceExporter))
+	assert.NoError(t, err, "receiver creation failed")
+	assert.Same(t, tReceiver, trReceiver, "receivers for the same config should be shared")
+
+	lReceiver, err := factory.CreateLogsReceiver(context.Background(), params, cfg, new(exportertest.SinkLogsExporter))
+	assert.NoError(t, err, "receiver creation failed")
+	assert.Same(t, tReceiver, lReceiver, "receivers for the same config should be shared")
 }
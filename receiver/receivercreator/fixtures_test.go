@@ -39,10 +39,53 @@ var portEndpoint = observer.Endpoint{
 	ID:     "port-1",
 	Target: "localhost:1234",
 	Details: observer.Port{
-		Name:      "http",
-		Pod:       pod,
-		Port:      1234,
-		Transport: observer.ProtocolTCP,
+		Name:           "http",
+		Pod:            pod,
+		ContainerName:  "redis",
+		ContainerImage: "redis:6",
+		Port:           1234,
+		Transport:      observer.ProtocolTCP,
+	},
+}
+
+var serviceEndpoint = observer.Endpoint{
+	ID:     "service-1",
+	Target: "10.0.0.10:6379",
+	Details: observer.K8sService{
+		Name:        "redis",
+		Namespace:   "default",
+		Labels:      map[string]string{"app": "redis"},
+		ClusterIP:   "10.0.0.10",
+		ServiceType: "ClusterIP",
+		PortName:    "redis",
+		Port:        6379,
+		Transport:   observer.ProtocolTCP,
+	},
+}
+
+var nodeEndpoint = observer.Endpoint{
+	ID:     "node-1",
+	Target: "192.168.1.10",
+	Details: observer.K8sNode{
+		Name:                "node-1",
+		Labels:              map[string]string{"kubernetes.io/os": "linux"},
+		InternalIP:          "192.168.1.10",
+		KubeletEndpointPort: 10250,
+	},
+}
+
+var containerEndpoint = observer.Endpoint{
+	ID:     "container-1",
+	Target: "172.17.0.2:6379",
+	Details: observer.Container{
+		Name:        "redis-1",
+		Image:       "redis",
+		Tag:         "6.0",
+		Port:        6379,
+		ContainerID: "abcdef",
+		Host:        "172.17.0.2",
+		Transport:   observer.ProtocolTCP,
+		Labels:      map[string]string{"app": "cache"},
 	},
 }
 
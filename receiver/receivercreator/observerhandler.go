@@ -32,6 +32,8 @@ type observerHandler struct {
 	logger *zap.Logger
 	// receiverTemplates maps receiver template full name to a receiverTemplate value.
 	receiverTemplates map[string]receiverTemplate
+	// resourceAttributes are the resource attributes to add by endpoint type.
+	resourceAttributes resourceAttributes
 	// receiversByEndpointID is a map of endpoint IDs to a receiver instance.
 	receiversByEndpointID receiverMap
 	// runner starts and stops receiver instances.
@@ -73,76 +75,158 @@ func (obs *observerHandler) OnAdd(added []observer.Endpoint) {
 		}
 
 		for _, template := range obs.receiverTemplates {
-			if matches, err := template.rule.eval(env); err != nil {
-				obs.logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(err))
-				continue
-			} else if !matches {
+			entry, matched := obs.resolveTemplate(template, e, env)
+			if !matched {
 				continue
 			}
+			obs.startReceiver(e, entry)
+		}
+	}
+}
 
-			obs.logger.Info("starting receiver",
-				zap.String("name", template.fullName),
-				zap.String("type", string(template.typeStr)),
-				zap.String("endpoint", e.Target),
-				zap.String("endpoint_id", string(e.ID)))
+// OnRemove responds to endpoint removal notifications.
+func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
+	obs.Lock()
+	defer obs.Unlock()
 
-			resolvedConfig, err := expandMap(template.config, env)
-			if err != nil {
-				obs.logger.Error("unable to resolve template config", zap.String("receiver", template.fullName), zap.Error(err))
-				continue
-			}
+	for _, e := range removed {
+		for _, entry := range obs.receiversByEndpointID.Get(e.ID) {
+			obs.stopReceiver(e, entry)
+		}
+		obs.receiversByEndpointID.RemoveAll(e.ID)
+	}
+}
 
-			discoveredConfig := userConfigMap{}
+// OnChange responds to endpoint change notifications. Only receivers whose rule
+// result or resolved config changed are stopped and/or started.
+func (obs *observerHandler) OnChange(changed []observer.Endpoint) {
+	obs.Lock()
+	defer obs.Unlock()
 
-			// If user didn't set endpoint set to default value.
-			if _, ok := resolvedConfig[endpointConfigKey]; !ok {
-				discoveredConfig[endpointConfigKey] = e.Target
-			}
+	for _, e := range changed {
+		env, err := observer.EndpointToEnv(e)
+		if err != nil {
+			obs.logger.Error("unable to convert endpoint to environment map", zap.String("endpoint", string(e.ID)), zap.Error(err))
+			continue
+		}
 
-			resolvedDiscoveredConfig, err := expandMap(discoveredConfig, env)
+		running := map[string]receiverEntry{}
+		for _, entry := range obs.receiversByEndpointID.Get(e.ID) {
+			running[entry.templateName] = entry
+		}
 
-			if err != nil {
-				obs.logger.Error("unable to resolve discovered config", zap.String("receiver", template.fullName), zap.Error(err))
+		for _, template := range obs.receiverTemplates {
+			entry, matched := obs.resolveTemplate(template, e, env)
+			existing, isRunning := running[template.fullName]
+
+			switch {
+			case matched && isRunning && entry.sameConfig(existing):
+				obs.logger.Debug("receiver config unchanged, not restarting",
+					zap.String("name", template.fullName),
+					zap.String("endpoint_id", string(e.ID)))
 				continue
+			case isRunning:
+				obs.stopReceiver(e, existing)
+				obs.receiversByEndpointID.Remove(e.ID, template.fullName)
 			}
 
-			rcvr, err := obs.runner.start(receiverConfig{
-				fullName: template.fullName,
-				typeStr:  template.typeStr,
-				config:   resolvedConfig,
-			}, resolvedDiscoveredConfig)
-
-			if err != nil {
-				obs.logger.Error("failed to start receiver", zap.String("receiver", template.fullName))
-				continue
+			if matched {
+				obs.startReceiver(e, entry)
 			}
-
-			obs.receiversByEndpointID.Put(e.ID, rcvr)
 		}
 	}
 }
 
-// OnRemove responds to endpoint removal notifications.
-func (obs *observerHandler) OnRemove(removed []observer.Endpoint) {
-	obs.Lock()
-	defer obs.Unlock()
+// resolveTemplate evaluates the template rule against env and if it matches returns
+// a receiverEntry containing the template config expanded against the endpoint.
+func (obs *observerHandler) resolveTemplate(template receiverTemplate, e observer.Endpoint, env observer.EndpointEnv) (receiverEntry, bool) {
+	if matches, err := template.rule.eval(env); err != nil {
+		obs.logger.Error("failed matching rule", zap.String("rule", template.Rule), zap.Error(err))
+		return receiverEntry{}, false
+	} else if !matches {
+		return receiverEntry{}, false
+	}
 
-	for _, e := range removed {
-		for _, rcvr := range obs.receiversByEndpointID.Get(e.ID) {
-			obs.logger.Info("stopping receiver", zap.Reflect("receiver", rcvr), zap.String("endpoint_id", string(e.ID)))
+	resolvedConfig, err := expandMap(template.config, env)
+	if err != nil {
+		obs.logger.Error("unable to resolve template config", zap.String("receiver", template.fullName), zap.Error(err))
+		return receiverEntry{}, false
+	}
 
-			if err := obs.runner.shutdown(rcvr); err != nil {
-				obs.logger.Error("failed to stop receiver", zap.Reflect("receiver", rcvr))
-				continue
-			}
+	discoveredConfig := userConfigMap{}
+
+	// If user didn't set endpoint set to default value.
+	if _, ok := resolvedConfig[endpointConfigKey]; !ok {
+		discoveredConfig[endpointConfigKey] = e.Target
+	}
+
+	resolvedDiscoveredConfig, err := expandMap(discoveredConfig, env)
+
+	if err != nil {
+		obs.logger.Error("unable to resolve discovered config", zap.String("receiver", template.fullName), zap.Error(err))
+		return receiverEntry{}, false
+	}
+
+	attrs := map[string]string{}
+	for k, v := range obs.resourceAttributes[endpointType(env)] {
+		attrs[k] = v
+	}
+	for k, v := range template.ResourceAttributes {
+		attrs[k] = v
+	}
+	resolvedAttrs, err := expandResourceAttributes(attrs, env)
+	if err != nil {
+		obs.logger.Error("unable to resolve resource attributes", zap.String("receiver", template.fullName), zap.Error(err))
+		return receiverEntry{}, false
+	}
+
+	return receiverEntry{
+		templateName: template.fullName,
+		config: receiverConfig{
+			fullName: template.fullName,
+			typeStr:  template.typeStr,
+			config:   resolvedConfig,
+		},
+		discoveredConfig:   resolvedDiscoveredConfig,
+		resourceAttributes: resolvedAttrs,
+	}, true
+}
+
+// endpointType returns the type of endpoint env was created from, e.g. pod or port.
+func endpointType(env observer.EndpointEnv) string {
+	types, _ := env["type"].(map[string]interface{})
+	for typ, isType := range types {
+		if is, _ := isType.(bool); is {
+			return typ
 		}
-		obs.receiversByEndpointID.RemoveAll(e.ID)
 	}
+	return ""
 }
 
-// OnChange responds to endpoint change notifications.
-func (obs *observerHandler) OnChange(changed []observer.Endpoint) {
-	// TODO: optimize to only restart if effective config has changed.
-	obs.OnRemove(changed)
-	obs.OnAdd(changed)
+// startReceiver starts the resolved receiver and records it for endpoint e.
+func (obs *observerHandler) startReceiver(e observer.Endpoint, entry receiverEntry) {
+	obs.logger.Info("starting receiver",
+		zap.String("name", entry.config.fullName),
+		zap.String("type", string(entry.config.typeStr)),
+		zap.String("endpoint", e.Target),
+		zap.String("endpoint_id", string(e.ID)))
+
+	rcvr, err := obs.runner.start(entry.config, entry.discoveredConfig, entry.resourceAttributes)
+
+	if err != nil {
+		obs.logger.Error("failed to start receiver", zap.String("receiver", entry.config.fullName), zap.Error(err))
+		return
+	}
+
+	entry.receiver = rcvr
+	obs.receiversByEndpointID.Put(e.ID, entry)
+}
+
+// stopReceiver shuts down a receiver started for endpoint e.
+func (obs *observerHandler) stopReceiver(e observer.Endpoint, entry receiverEntry) {
+	obs.logger.Info("stopping receiver", zap.Reflect("receiver", entry.receiver), zap.String("endpoint_id", string(e.ID)))
+
+	if err := obs.runner.shutdown(entry.receiver); err != nil {
+		obs.logger.Error("failed to stop receiver", zap.Reflect("receiver", entry.receiver))
+	}
 }
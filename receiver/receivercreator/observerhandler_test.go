@@ -31,8 +31,8 @@ type mockRunner struct {
 	mock.Mock
 }
 
-func (run *mockRunner) start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error) {
-	args := run.Called(receiver, discoveredConfig)
+func (run *mockRunner) start(receiver receiverConfig, discoveredConfig userConfigMap, resourceAttributes map[string]string) (component.Receiver, error) {
+	args := run.Called(receiver, discoveredConfig, resourceAttributes)
 	return args.Get(0).(component.Receiver), args.Error(1)
 }
 
@@ -49,13 +49,13 @@ func TestOnAdd(t *testing.T) {
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
-			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`)},
+			"name/1": {receiverConfig: rcvrCfg, rule: newRuleOrPanic(`type.port`)},
 		},
 		receiversByEndpointID: receiverMap{},
 		runner:                runner,
 	}
 
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, map[string]string{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
 
 	handler.OnAdd([]observer.Endpoint{
 		portEndpoint,
@@ -75,7 +75,7 @@ func TestOnRemove(t *testing.T) {
 		runner:                runner,
 	}
 
-	handler.receiversByEndpointID.Put("port-1", rcvr)
+	handler.receiversByEndpointID.Put("port-1", receiverEntry{templateName: "name/1", receiver: rcvr})
 
 	runner.On("shutdown", rcvr).Return(nil)
 
@@ -93,22 +93,99 @@ func TestOnChange(t *testing.T) {
 	handler := &observerHandler{
 		logger: zap.NewNop(),
 		receiverTemplates: map[string]receiverTemplate{
-			"name/1": {rcvrCfg, "", newRuleOrPanic(`type.port`)},
+			"name/1": {receiverConfig: rcvrCfg, rule: newRuleOrPanic(`type.port`)},
 		},
 		receiversByEndpointID: receiverMap{},
 		runner:                runner,
 	}
 
-	handler.receiversByEndpointID.Put("port-1", oldRcvr)
+	// Running receiver was started with a different endpoint.
+	handler.receiversByEndpointID.Put("port-1", receiverEntry{
+		templateName:     "name/1",
+		config:           rcvrCfg,
+		discoveredConfig: userConfigMap{endpointConfigKey: "localhost:4321"},
+		receiver:         oldRcvr,
+	})
 
 	runner.On("shutdown", oldRcvr).Return(nil)
-	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}).Return(newRcvr, nil)
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, map[string]string{}).Return(newRcvr, nil)
 
 	handler.OnChange([]observer.Endpoint{portEndpoint})
 
 	runner.AssertExpectations(t)
 	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
-	assert.Same(t, newRcvr, handler.receiversByEndpointID.Get("port-1")[0])
+	assert.Same(t, newRcvr, handler.receiversByEndpointID.Get("port-1")[0].receiver)
+}
+
+func TestOnChangeUnchangedConfig(t *testing.T) {
+	runner := &mockRunner{}
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
+	rcvr := &componenttest.ExampleReceiverProducer{}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {receiverConfig: rcvrCfg, rule: newRuleOrPanic(`type.port`)},
+		},
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+	}
+
+	handler.receiversByEndpointID.Put("port-1", receiverEntry{
+		templateName:       "name/1",
+		config:             rcvrCfg,
+		discoveredConfig:   userConfigMap{endpointConfigKey: "localhost:1234"},
+		resourceAttributes: map[string]string{},
+		receiver:           rcvr,
+	})
+
+	// Label changes that don't affect the rule or config must not restart the receiver.
+	changed := portEndpoint
+	port := changed.Details.(observer.Port)
+	port.Pod.Labels = map[string]string{"app": "redis", "region": "west-2"}
+	changed.Details = port
+
+	handler.OnChange([]observer.Endpoint{changed})
+
+	runner.AssertExpectations(t)
+	runner.AssertNotCalled(t, "start", mock.Anything, mock.Anything, mock.Anything)
+	runner.AssertNotCalled(t, "shutdown", mock.Anything)
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+	assert.Same(t, rcvr, handler.receiversByEndpointID.Get("port-1")[0].receiver)
+}
+
+func TestOnChangeRuleNoLongerMatches(t *testing.T) {
+	runner := &mockRunner{}
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
+	rcvr := &componenttest.ExampleReceiverProducer{}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {receiverConfig: rcvrCfg, rule: newRuleOrPanic(`type.port && pod.labels["region"] == "west-1"`)},
+		},
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+	}
+
+	handler.receiversByEndpointID.Put("port-1", receiverEntry{
+		templateName:       "name/1",
+		config:             rcvrCfg,
+		discoveredConfig:   userConfigMap{endpointConfigKey: "localhost:1234"},
+		resourceAttributes: map[string]string{},
+		receiver:           rcvr,
+	})
+
+	changed := portEndpoint
+	port := changed.Details.(observer.Port)
+	port.Pod.Labels = map[string]string{"app": "redis", "region": "west-2"}
+	changed.Details = port
+
+	runner.On("shutdown", rcvr).Return(nil)
+
+	handler.OnChange([]observer.Endpoint{changed})
+
+	runner.AssertExpectations(t)
+	runner.AssertNotCalled(t, "start", mock.Anything, mock.Anything, mock.Anything)
+	assert.Equal(t, 0, handler.receiversByEndpointID.Size())
 }
 
 func TestDynamicConfig(t *testing.T) {
@@ -129,10 +206,49 @@ func TestDynamicConfig(t *testing.T) {
 		fullName: "name/1",
 		typeStr:  "name",
 		config:   userConfigMap{endpointConfigKey: "localhost:6379"},
-	}, userConfigMap{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+	}, userConfigMap{}, map[string]string{}).Return(&componenttest.ExampleReceiverProducer{}, nil)
 	handler.OnAdd([]observer.Endpoint{
 		podEndpoint,
 	})
 
 	runner.AssertExpectations(t)
 }
+
+func TestResourceAttributes(t *testing.T) {
+	runner := &mockRunner{}
+	rcvrCfg := receiverConfig{typeStr: configmodels.Type("name"), config: userConfigMap{"foo": "bar"}, fullName: "name/1"}
+	handler := &observerHandler{
+		logger: zap.NewNop(),
+		resourceAttributes: resourceAttributes{
+			"pod": {"k8s.pod.name": "`name`"},
+			"port": {
+				"k8s.pod.name": "`pod.name`",
+				"source":       "receiver_creator",
+				"missing":      "`pod.labels[\"missing\"]`",
+			},
+		},
+		receiverTemplates: map[string]receiverTemplate{
+			"name/1": {
+				receiverConfig: rcvrCfg,
+				rule:           newRuleOrPanic(`type.port`),
+				ResourceAttributes: map[string]string{
+					"source": "template",
+					"region": "`pod.labels[\"region\"]`",
+				},
+			},
+		},
+		receiversByEndpointID: receiverMap{},
+		runner:                runner,
+	}
+
+	runner.On("start", rcvrCfg, userConfigMap{endpointConfigKey: "localhost:1234"}, map[string]string{
+		"k8s.pod.name": "pod-1",
+		"source":       "template",
+		"region":       "west-1",
+	}).Return(&componenttest.ExampleReceiverProducer{}, nil)
+
+	handler.OnAdd([]observer.Endpoint{portEndpoint})
+
+	runner.AssertExpectations(t)
+	assert.Equal(t, 1, handler.receiversByEndpointID.Size())
+}
@@ -18,6 +18,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"sync"
 
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configmodels"
@@ -32,27 +33,52 @@ var (
 )
 
 var _ component.MetricsReceiver = (*receiverCreator)(nil)
+var _ component.TraceReceiver = (*receiverCreator)(nil)
+var _ component.LogsReceiver = (*receiverCreator)(nil)
 
-// receiverCreator implements consumer.MetricsConsumer.
+// receiverCreator implements component.MetricsReceiver, component.TraceReceiver and
+// component.LogsReceiver. A single instance is shared by all pipelines using the same config.
 type receiverCreator struct {
-	nextConsumer    consumer.MetricsConsumer
-	logger          *zap.Logger
-	cfg             *Config
-	observerHandler observerHandler
+	sync.Mutex
+	nextMetricsConsumer consumer.MetricsConsumer
+	nextTracesConsumer  consumer.TraceConsumer
+	nextLogsConsumer    consumer.LogsConsumer
+	logger              *zap.Logger
+	cfg                 *Config
+	observerHandler     observerHandler
+	observables         []observer.Observable
 }
 
 // newReceiverCreator creates the receiver_creator with the given parameters.
-func newReceiverCreator(logger *zap.Logger, nextConsumer consumer.MetricsConsumer, cfg *Config) (component.MetricsReceiver, error) {
-	if nextConsumer == nil {
-		return nil, errNilNextConsumer
+func newReceiverCreator(logger *zap.Logger, cfg *Config) *receiverCreator {
+	return &receiverCreator{
+		logger: logger,
+		cfg:    cfg,
 	}
+}
 
-	r := &receiverCreator{
-		logger:       logger,
-		nextConsumer: nextConsumer,
-		cfg:          cfg,
-	}
-	return r, nil
+// registerMetricsConsumer sets the consumer that subreceivers will send metrics to.
+func (rc *receiverCreator) registerMetricsConsumer(mc consumer.MetricsConsumer) {
+	rc.Lock()
+	defer rc.Unlock()
+
+	rc.nextMetricsConsumer = mc
+}
+
+// registerTracesConsumer sets the consumer that subreceivers will send traces to.
+func (rc *receiverCreator) registerTracesConsumer(tc consumer.TraceConsumer) {
+	rc.Lock()
+	defer rc.Unlock()
+
+	rc.nextTracesConsumer = tc
+}
+
+// registerLogsConsumer sets the consumer that subreceivers will send logs to.
+func (rc *receiverCreator) registerLogsConsumer(lc consumer.LogsConsumer) {
+	rc.Lock()
+	defer rc.Unlock()
+
+	rc.nextLogsConsumer = lc
 }
 
 // loggingHost provides a safer version of host that logs errors instead of exiting the process.
@@ -70,14 +96,24 @@ var _ component.Host = (*loggingHost)(nil)
 
 // Start receiver_creator.
 func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error {
+	rc.Lock()
+	defer rc.Unlock()
+
+	if rc.nextMetricsConsumer == nil && rc.nextTracesConsumer == nil && rc.nextLogsConsumer == nil {
+		return errNilNextConsumer
+	}
+
 	rc.observerHandler = observerHandler{
 		logger:                rc.logger,
 		receiverTemplates:     rc.cfg.receiverTemplates,
+		resourceAttributes:    rc.cfg.ResourceAttributes,
 		receiversByEndpointID: receiverMap{},
 		runner: &receiverRunner{
-			logger:       rc.logger,
-			nextConsumer: rc.nextConsumer,
-			idNamespace:  rc.cfg.Name(),
+			logger:              rc.logger,
+			nextMetricsConsumer: rc.nextMetricsConsumer,
+			nextTracesConsumer:  rc.nextTracesConsumer,
+			nextLogsConsumer:    rc.nextLogsConsumer,
+			idNamespace:         rc.cfg.Name(),
 			// TODO: not really sure what context should be used here for starting subreceivers
 			// as don't think it makes sense to use Start context as the lifetimes are different.
 			ctx:  context.Background(),
@@ -116,6 +152,7 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 	// Start all configured watchers.
 	for _, observable := range observers {
 		observable.ListAndWatch(&rc.observerHandler)
+		rc.observables = append(rc.observables, observable)
 	}
 
 	return nil
@@ -123,5 +160,8 @@ func (rc *receiverCreator) Start(ctx context.Context, host component.Host) error
 
 // Shutdown stops the receiver_creator and all its receivers started at runtime.
 func (rc *receiverCreator) Shutdown(ctx context.Context) error {
+	for _, observable := range rc.observables {
+		observable.Unsubscribe(&rc.observerHandler)
+	}
 	return rc.observerHandler.Shutdown()
 }
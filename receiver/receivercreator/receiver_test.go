@@ -77,6 +77,9 @@ func (m *mockObserver) ListAndWatch(notify observer.Notify) {
 	notify.OnAdd([]observer.Endpoint{portEndpoint})
 }
 
+func (m *mockObserver) Unsubscribe(observer.Notify) {
+}
+
 var _ observer.Observable = (*mockObserver)(nil)
 
 func TestMockedEndToEnd(t *testing.T) {
@@ -111,7 +114,7 @@ func TestMockedEndToEnd(t *testing.T) {
 
 	// Test that we can send metrics.
 	for _, receiver := range dyn.observerHandler.receiversByEndpointID.Values() {
-		example := receiver.(*componenttest.ExampleReceiverProducer)
+		example := receiver.(*wrappedReceiver).metrics.(*componenttest.ExampleReceiverProducer)
 		md := internaldata.OCToMetrics(consumerdata.MetricsData{
 			Node: &commonpb.Node{
 				ServiceInfo: &commonpb.ServiceInfo{Name: "dynamictest"},
@@ -147,7 +150,7 @@ func TestMockedEndToEnd(t *testing.T) {
 
 	shutdown()
 
-	assert.True(t, dyn.observerHandler.receiversByEndpointID.Values()[0].(*componenttest.ExampleReceiverProducer).Stopped)
+	assert.True(t, dyn.observerHandler.receiversByEndpointID.Values()[0].(*wrappedReceiver).metrics.(*componenttest.ExampleReceiverProducer).Stopped)
 }
 
 func TestLoggingHost(t *testing.T) {
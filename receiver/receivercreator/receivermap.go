@@ -15,25 +15,66 @@
 package receivercreator
 
 import (
+	"reflect"
+
 	"go.opentelemetry.io/collector/component"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/extension/observer"
 )
 
+// receiverEntry is a running receiver along with the resolved configuration
+// it was started with.
+type receiverEntry struct {
+	// templateName is the full name of the receiver template the receiver was created from.
+	templateName string
+	// config is the template config after expansion against the endpoint.
+	config receiverConfig
+	// discoveredConfig is the config discovered from the endpoint.
+	discoveredConfig userConfigMap
+	// resourceAttributes are the resource attributes after expansion against the endpoint.
+	resourceAttributes map[string]string
+	// receiver is the running receiver instance.
+	receiver component.Receiver
+}
+
+// sameConfig returns whether other was resolved to the same effective config.
+func (entry receiverEntry) sameConfig(other receiverEntry) bool {
+	return entry.templateName == other.templateName &&
+		entry.config.typeStr == other.config.typeStr &&
+		reflect.DeepEqual(entry.config.config, other.config.config) &&
+		reflect.DeepEqual(entry.discoveredConfig, other.discoveredConfig) &&
+		reflect.DeepEqual(entry.resourceAttributes, other.resourceAttributes)
+}
+
 // receiverMap is a multimap for mapping one id to many receivers. It does
 // not deduplicate the same value being associated with the same key.
-type receiverMap map[observer.EndpointID][]component.Receiver
+type receiverMap map[observer.EndpointID][]receiverEntry
 
-// Put rcvr into key id. If rcvr is a duplicate it will still be added.
-func (rm receiverMap) Put(id observer.EndpointID, rcvr component.Receiver) {
-	rm[id] = append(rm[id], rcvr)
+// Put entry into key id. If entry is a duplicate it will still be added.
+func (rm receiverMap) Put(id observer.EndpointID, entry receiverEntry) {
+	rm[id] = append(rm[id], entry)
 }
 
-// Get receivers by id.
-func (rm receiverMap) Get(id observer.EndpointID) []component.Receiver {
+// Get receiver entries by id.
+func (rm receiverMap) Get(id observer.EndpointID) []receiverEntry {
 	return rm[id]
 }
 
+// Remove the entries created from templateName by id.
+func (rm receiverMap) Remove(id observer.EndpointID, templateName string) {
+	var kept []receiverEntry
+	for _, entry := range rm[id] {
+		if entry.templateName != templateName {
+			kept = append(kept, entry)
+		}
+	}
+	if len(kept) == 0 {
+		delete(rm, id)
+		return
+	}
+	rm[id] = kept
+}
+
 // Remove all receivers by id.
 func (rm receiverMap) RemoveAll(id observer.EndpointID) {
 	delete(rm, id)
@@ -42,7 +83,9 @@ func (rm receiverMap) RemoveAll(id observer.EndpointID) {
 // Get all receivers in the map.
 func (rm receiverMap) Values() (out []component.Receiver) {
 	for _, m := range rm {
-		out = append(out, m...)
+		for _, entry := range m {
+			out = append(out, entry.receiver)
+		}
 	}
 	return
 }
@@ -26,9 +26,9 @@ func TestReceiverMap(t *testing.T) {
 	rm := receiverMap{}
 	assert.Equal(t, 0, rm.Size())
 
-	r1 := &componenttest.ExampleReceiverProducer{}
-	r2 := &componenttest.ExampleReceiverProducer{}
-	r3 := &componenttest.ExampleReceiverProducer{}
+	r1 := receiverEntry{templateName: "r/1", receiver: &componenttest.ExampleReceiverProducer{}}
+	r2 := receiverEntry{templateName: "r/2", receiver: &componenttest.ExampleReceiverProducer{}}
+	r3 := receiverEntry{templateName: "r/1", receiver: &componenttest.ExampleReceiverProducer{}}
 
 	rm.Put("a", r1)
 	assert.Equal(t, 1, rm.Size())
@@ -39,7 +39,7 @@ func TestReceiverMap(t *testing.T) {
 	rm.Put("b", r3)
 	assert.Equal(t, 3, rm.Size())
 
-	assert.Equal(t, []component.Receiver{r1, r2}, rm.Get("a"))
+	assert.Equal(t, []receiverEntry{r1, r2}, rm.Get("a"))
 	assert.Nil(t, rm.Get("missing"))
 
 	rm.RemoveAll("missing")
@@ -54,5 +54,26 @@ func TestReceiverMap(t *testing.T) {
 	rm.Put("a", r1)
 	rm.Put("b", r2)
 	assert.Equal(t, 2, rm.Size())
-	assert.Equal(t, []component.Receiver{r1, r2}, rm.Values())
+	assert.ElementsMatch(t, []component.Receiver{r1.receiver, r2.receiver}, rm.Values())
+}
+
+func TestReceiverMapRemove(t *testing.T) {
+	rm := receiverMap{}
+
+	r1 := receiverEntry{templateName: "r/1", receiver: &componenttest.ExampleReceiverProducer{}}
+	r2 := receiverEntry{templateName: "r/2", receiver: &componenttest.ExampleReceiverProducer{}}
+
+	rm.Put("a", r1)
+	rm.Put("a", r2)
+
+	rm.Remove("a", "missing")
+	assert.Equal(t, 2, rm.Size())
+
+	rm.Remove("a", "r/1")
+	assert.Equal(t, []receiverEntry{r2}, rm.Get("a"))
+
+	rm.Remove("a", "r/2")
+	assert.Equal(t, 0, rm.Size())
+	_, ok := rm["a"]
+	assert.False(t, ok)
 }
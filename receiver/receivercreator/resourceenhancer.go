@@ -0,0 +1,86 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/consumer/pdata"
+)
+
+var _ consumer.MetricsConsumer = (*resourceEnhancer)(nil)
+var _ consumer.TraceConsumer = (*resourceEnhancer)(nil)
+var _ consumer.LogsConsumer = (*resourceEnhancer)(nil)
+
+// resourceEnhancer adds the resource attributes resolved from an endpoint to
+// every resource of the data a subreceiver emits before passing it on to the
+// next consumer. Attributes already set by the subreceiver are not overridden.
+type resourceEnhancer struct {
+	attrs       map[string]string
+	nextMetrics consumer.MetricsConsumer
+	nextTraces  consumer.TraceConsumer
+	nextLogs    consumer.LogsConsumer
+}
+
+// enhance inserts the configured attributes into resource.
+func (r *resourceEnhancer) enhance(resource pdata.Resource) {
+	if resource.IsNil() {
+		resource.InitEmpty()
+	}
+	attrs := resource.Attributes()
+	for k, v := range r.attrs {
+		attrs.InsertString(k, v)
+	}
+}
+
+// ConsumeMetrics adds the resource attributes to md and passes it to the next metrics consumer.
+func (r *resourceEnhancer) ConsumeMetrics(ctx context.Context, md pdata.Metrics) error {
+	rms := md.ResourceMetrics()
+	for i := 0; i < rms.Len(); i++ {
+		rm := rms.At(i)
+		if rm.IsNil() {
+			continue
+		}
+		r.enhance(rm.Resource())
+	}
+	return r.nextMetrics.ConsumeMetrics(ctx, md)
+}
+
+// ConsumeTraces adds the resource attributes to td and passes it to the next traces consumer.
+func (r *resourceEnhancer) ConsumeTraces(ctx context.Context, td pdata.Traces) error {
+	rss := td.ResourceSpans()
+	for i := 0; i < rss.Len(); i++ {
+		rs := rss.At(i)
+		if rs.IsNil() {
+			continue
+		}
+		r.enhance(rs.Resource())
+	}
+	return r.nextTraces.ConsumeTraces(ctx, td)
+}
+
+// ConsumeLogs adds the resource attributes to ld and passes it to the next logs consumer.
+func (r *resourceEnhancer) ConsumeLogs(ctx context.Context, ld pdata.Logs) error {
+	rls := ld.ResourceLogs()
+	for i := 0; i < rls.Len(); i++ {
+		rl := rls.At(i)
+		if rl.IsNil() {
+			continue
+		}
+		r.enhance(rl.Resource())
+	}
+	return r.nextLogs.ConsumeLogs(ctx, ld)
+}
@@ -0,0 +1,68 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package receivercreator
+
+import (
+	"context"
+	"testing"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/consumerdata"
+	"go.opentelemetry.io/collector/translator/internaldata"
+)
+
+func TestResourceEnhancerMetrics(t *testing.T) {
+	next := &mockMetricsConsumer{}
+	enhancer := &resourceEnhancer{
+		attrs: map[string]string{
+			"k8s.pod.name": "pod-1",
+			"existing":     "overridden",
+		},
+		nextMetrics: next,
+	}
+
+	md := internaldata.OCToMetrics(consumerdata.MetricsData{
+		Resource: &resourcepb.Resource{
+			Labels: map[string]string{"existing": "original"},
+		},
+		Metrics: []*metricspb.Metric{
+			{
+				MetricDescriptor: &metricspb.MetricDescriptor{
+					Name: "my-metric",
+					Type: metricspb.MetricDescriptor_GAUGE_INT64,
+				},
+			},
+		},
+	})
+
+	require.NoError(t, enhancer.ConsumeMetrics(context.Background(), md))
+	require.Len(t, next.Metrics, 1)
+
+	rms := next.Metrics[0].ResourceMetrics()
+	require.Equal(t, 1, rms.Len())
+	attrs := rms.At(0).Resource().Attributes()
+
+	podName, ok := attrs.Get("k8s.pod.name")
+	require.True(t, ok)
+	assert.Equal(t, "pod-1", podName.StringVal())
+
+	// Attributes set by the subreceiver take precedence.
+	existing, ok := attrs.Get("existing")
+	require.True(t, ok)
+	assert.Equal(t, "original", existing.StringVal())
+}
@@ -47,6 +47,12 @@ func Test_ruleEval(t *testing.T) {
 		{"basic port", args{`type.port && name == "http" && pod.labels["app"] == "redis"`, portEndpoint}, true, false},
 		{"basic pod", args{`type.pod && labels["region"] == "west-1"`, podEndpoint}, true, false},
 		{"annotations", args{`type.pod && annotations["scrape"] == "true"`, podEndpoint}, true, false},
+		{"container image", args{`type.port && container.image matches "^redis"`, portEndpoint}, true, false},
+		{"basic service", args{`type.service && port == 6379 && labels["app"] == "redis"`, serviceEndpoint}, true, false},
+		{"service is not a port", args{`type.port && port == 6379`, serviceEndpoint}, false, false},
+		{"basic node", args{`type.node && labels["kubernetes.io/os"] == "linux"`, nodeEndpoint}, true, false},
+		{"basic container", args{`type.container && image matches "redis" && labels["app"] == "cache"`, containerEndpoint}, true, false},
+		{"container is not a port", args{`type.port && port == 6379`, containerEndpoint}, false, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -82,6 +88,9 @@ func Test_newRule(t *testing.T) {
 		{"does not start with type", args{"port == 1234"}, true},
 		{"invalid syntax", args{"port =="}, true},
 		{"valid", args{`type.port && port_name == "http"`}, false},
+		{"valid service", args{`type.service && port == 6379`}, false},
+		{"valid node", args{`type.node`}, false},
+		{"valid container", args{`type.container`}, false},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -19,7 +19,9 @@ import (
 	"fmt"
 
 	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
 	"go.opentelemetry.io/collector/config"
+	"go.opentelemetry.io/collector/config/configerror"
 	"go.opentelemetry.io/collector/config/configmodels"
 	"go.opentelemetry.io/collector/consumer"
 	"go.uber.org/zap"
@@ -27,25 +29,28 @@ import (
 
 // runner starts and stops receiver instances.
 type runner interface {
-	// start a receiver instance from its static config and discovered config.
-	start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error)
+	// start a receiver instance from its static config and discovered config. resourceAttributes
+	// are added to the resources of all data emitted by the receiver.
+	start(receiver receiverConfig, discoveredConfig userConfigMap, resourceAttributes map[string]string) (component.Receiver, error)
 	// shutdown a receiver.
 	shutdown(rcvr component.Receiver) error
 }
 
 // receiverRunner handles starting/stopping of a concrete subreceiver instance.
 type receiverRunner struct {
-	logger       *zap.Logger
-	nextConsumer consumer.MetricsConsumer
-	idNamespace  string
-	ctx          context.Context
-	host         component.Host
+	logger              *zap.Logger
+	nextMetricsConsumer consumer.MetricsConsumer
+	nextTracesConsumer  consumer.TraceConsumer
+	nextLogsConsumer    consumer.LogsConsumer
+	idNamespace         string
+	ctx                 context.Context
+	host                component.Host
 }
 
 var _ runner = (*receiverRunner)(nil)
 
 // start a receiver instance from its static config and discovered config.
-func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userConfigMap) (component.Receiver, error) {
+func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userConfigMap, resourceAttributes map[string]string) (component.Receiver, error) {
 	factory := run.host.GetFactory(component.KindReceiver, receiver.typeStr)
 
 	if factory == nil {
@@ -58,7 +63,7 @@ func (run *receiverRunner) start(receiver receiverConfig, discoveredConfig userC
 	if err != nil {
 		return nil, err
 	}
-	recvr, err := run.createRuntimeReceiver(receiverFactory, cfg)
+	recvr, err := run.createRuntimeReceiver(receiverFactory, cfg, resourceAttributes)
 	if err != nil {
 		return nil, err
 	}
@@ -104,8 +109,124 @@ func (run *receiverRunner) loadRuntimeReceiverConfig(
 	return receiverConfig, nil
 }
 
-// createRuntimeReceiver creates a receiver that is discovered at runtime.
-func (run *receiverRunner) createRuntimeReceiver(factory component.ReceiverFactory, cfg configmodels.Receiver) (component.MetricsReceiver, error) {
+// createRuntimeReceiver creates a receiver that is discovered at runtime. A receiver instance is
+// created for every data type that both the subreceiver factory supports and receiver_creator has
+// a consumer for.
+func (run *receiverRunner) createRuntimeReceiver(
+	factory component.ReceiverFactory,
+	cfg configmodels.Receiver,
+	resourceAttributes map[string]string,
+) (component.Receiver, error) {
 	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
-	return factory.CreateMetricsReceiver(context.Background(), params, cfg, run.nextConsumer)
+	wr := &wrappedReceiver{}
+	ctx := context.Background()
+
+	nextMetricsConsumer := run.nextMetricsConsumer
+	nextTracesConsumer := run.nextTracesConsumer
+	nextLogsConsumer := run.nextLogsConsumer
+
+	if len(resourceAttributes) > 0 {
+		enhancer := &resourceEnhancer{
+			attrs:       resourceAttributes,
+			nextMetrics: run.nextMetricsConsumer,
+			nextTraces:  run.nextTracesConsumer,
+			nextLogs:    run.nextLogsConsumer,
+		}
+		if nextMetricsConsumer != nil {
+			nextMetricsConsumer = enhancer
+		}
+		if nextTracesConsumer != nil {
+			nextTracesConsumer = enhancer
+		}
+		if nextLogsConsumer != nil {
+			nextLogsConsumer = enhancer
+		}
+	}
+
+	if nextMetricsConsumer != nil {
+		metrics, err := factory.CreateMetricsReceiver(ctx, params, cfg, nextMetricsConsumer)
+		if err != nil && err != configerror.ErrDataTypeIsNotSupported {
+			return nil, fmt.Errorf("failed creating metrics receiver %s: %v", cfg.Name(), err)
+		}
+		wr.metrics = metrics
+	}
+
+	if nextTracesConsumer != nil {
+		traces, err := factory.CreateTraceReceiver(ctx, params, cfg, nextTracesConsumer)
+		if err != nil && err != configerror.ErrDataTypeIsNotSupported {
+			return nil, fmt.Errorf("failed creating traces receiver %s: %v", cfg.Name(), err)
+		}
+		wr.traces = traces
+	}
+
+	if nextLogsConsumer != nil {
+		logs, err := factory.CreateLogsReceiver(ctx, params, cfg, nextLogsConsumer)
+		if err != nil && err != configerror.ErrDataTypeIsNotSupported {
+			return nil, fmt.Errorf("failed creating logs receiver %s: %v", cfg.Name(), err)
+		}
+		wr.logs = logs
+	}
+
+	if len(wr.components()) == 0 {
+		return nil, fmt.Errorf("receiver %s does not support any of the data types receiver_creator is configured for", cfg.Name())
+	}
+
+	return wr, nil
+}
+
+// wrappedReceiver groups the receivers created for each data type from a single subreceiver config
+// so that they can be started and stopped as one.
+type wrappedReceiver struct {
+	metrics component.MetricsReceiver
+	traces  component.TraceReceiver
+	logs    component.LogsReceiver
+}
+
+var _ component.Receiver = (*wrappedReceiver)(nil)
+
+// components returns the unique non-nil receivers. Factories that support multiple data types
+// usually return the same instance for each one so it must only be started and stopped once.
+func (wr *wrappedReceiver) components() []component.Receiver {
+	var out []component.Receiver
+	for _, rcvr := range []component.Receiver{wr.metrics, wr.traces, wr.logs} {
+		if rcvr == nil {
+			continue
+		}
+		duplicate := false
+		for _, existing := range out {
+			if existing == rcvr {
+				duplicate = true
+				break
+			}
+		}
+		if !duplicate {
+			out = append(out, rcvr)
+		}
+	}
+	return out
+}
+
+// Start all wrapped receivers. If one fails to start, the ones already started are shut down.
+func (wr *wrappedReceiver) Start(ctx context.Context, host component.Host) error {
+	components := wr.components()
+	for i, rcvr := range components {
+		if err := rcvr.Start(ctx, host); err != nil {
+			for _, started := range components[:i] {
+				_ = started.Shutdown(ctx)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown all wrapped receivers.
+func (wr *wrappedReceiver) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, rcvr := range wr.components() {
+		if err := rcvr.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
 }
@@ -15,16 +15,28 @@
 package receivercreator
 
 import (
+	"context"
+	"errors"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/receiver/receiverhelper"
 	"go.uber.org/zap"
 )
 
 func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
-	run := &receiverRunner{logger: zap.NewNop(), nextConsumer: &mockMetricsConsumer{}, idNamespace: "receiver_creator/1"}
+	run := &receiverRunner{
+		logger:              zap.NewNop(),
+		nextMetricsConsumer: &mockMetricsConsumer{},
+		nextTracesConsumer:  new(exportertest.SinkTraceExporter),
+		idNamespace:         "receiver_creator/1",
+	}
 	exampleFactory := &componenttest.ExampleReceiverFactory{}
 	template, err := newReceiverTemplate("examplereceiver/1", nil)
 	require.NoError(t, err)
@@ -41,10 +53,74 @@ func Test_loadAndCreateRuntimeReceiver(t *testing.T) {
 
 	// Test that metric receiver can be created from loaded config.
 	t.Run("test create receiver from loaded config", func(t *testing.T) {
-		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig)
+		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, nil)
 		require.NoError(t, err)
 		assert.NotNil(t, recvr)
-		exampleReceiver := recvr.(*componenttest.ExampleReceiverProducer)
-		assert.Equal(t, run.nextConsumer, exampleReceiver.MetricsConsumer)
+		wr := recvr.(*wrappedReceiver)
+		assert.Nil(t, wr.logs, "logs receiver should not be created without a logs consumer")
+		exampleReceiver := wr.metrics.(*componenttest.ExampleReceiverProducer)
+		assert.Equal(t, run.nextMetricsConsumer, exampleReceiver.MetricsConsumer)
+		assert.Equal(t, run.nextTracesConsumer, exampleReceiver.TraceConsumer)
+		// The example factory shares a single receiver for all data types.
+		assert.Len(t, wr.components(), 1)
 	})
+
+	t.Run("test create receiver with resource attributes", func(t *testing.T) {
+		attrs := map[string]string{"k8s.pod.name": "pod-1"}
+		recvr, err := run.createRuntimeReceiver(exampleFactory, loadedConfig, attrs)
+		require.NoError(t, err)
+		exampleReceiver := recvr.(*wrappedReceiver).metrics.(*componenttest.ExampleReceiverProducer)
+		enhancer, ok := exampleReceiver.MetricsConsumer.(*resourceEnhancer)
+		require.True(t, ok, "metrics consumer should add resource attributes")
+		assert.Equal(t, attrs, enhancer.attrs)
+		assert.Equal(t, run.nextMetricsConsumer, enhancer.nextMetrics)
+	})
+}
+
+func Test_createRuntimeReceiverUnsupportedType(t *testing.T) {
+	run := &receiverRunner{logger: zap.NewNop(), nextLogsConsumer: new(exportertest.SinkLogsExporter), idNamespace: "receiver_creator/1"}
+	factory := receiverhelper.NewFactory("metricsonly", func() configmodels.Receiver {
+		return &configmodels.ReceiverSettings{TypeVal: "metricsonly", NameVal: "metricsonly"}
+	}, receiverhelper.WithMetrics(func(
+		context.Context, component.ReceiverCreateParams, configmodels.Receiver, consumer.MetricsConsumer,
+	) (component.MetricsReceiver, error) {
+		return &componenttest.ExampleReceiverProducer{}, nil
+	}))
+
+	recvr, err := run.createRuntimeReceiver(factory, factory.CreateDefaultConfig(), nil)
+	assert.Error(t, err)
+	assert.Nil(t, recvr)
+}
+
+func TestWrappedReceiver(t *testing.T) {
+	shared := &componenttest.ExampleReceiverProducer{}
+	traces := &componenttest.ExampleReceiverProducer{}
+	wr := &wrappedReceiver{metrics: shared, logs: shared, traces: traces}
+	require.Len(t, wr.components(), 2)
+
+	require.NoError(t, wr.Start(context.Background(), componenttest.NewNopHost()))
+	assert.True(t, shared.Started)
+	assert.True(t, traces.Started)
+
+	require.NoError(t, wr.Shutdown(context.Background()))
+	assert.True(t, shared.Stopped)
+	assert.True(t, traces.Stopped)
+}
+
+type failingReceiver struct {
+	componenttest.ExampleReceiverProducer
+}
+
+func (f *failingReceiver) Start(context.Context, component.Host) error {
+	return errors.New("failed to start")
+}
+
+func TestWrappedReceiverStartFailure(t *testing.T) {
+	metrics := &componenttest.ExampleReceiverProducer{}
+	wr := &wrappedReceiver{metrics: metrics, traces: &failingReceiver{}}
+
+	require.Error(t, wr.Start(context.Background(), componenttest.NewNopHost()))
+	// The receivers already started are shut down.
+	assert.True(t, metrics.Started)
+	assert.True(t, metrics.Stopped)
 }
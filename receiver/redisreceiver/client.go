@@ -22,9 +22,18 @@ import (
 type client interface {
 	// retrieves a string of key/value pairs of redis metadata
 	retrieveInfo() (string, error)
+	// retrieves a string of key/value pairs of the given INFO section, for the
+	// sections that INFO doesn't return by default, e.g. commandstats
+	retrieveInfoSection(section string) (string, error)
+	// retrieves a string of key/value pairs of the CLUSTER INFO command
+	retrieveClusterInfo() (string, error)
+	// retrieves the latest latency spikes of the LATENCY LATEST command
+	retrieveLatencyLatest() ([]latencyEvent, error)
 	// line delimiter
 	// redis lines are delimited by \r\n, files (for testing) by \n
 	delimiter() string
+	// closes the connections to redis
+	close() error
 }
 
 // Wraps a real Redis client, implements `client` interface.
@@ -50,3 +59,27 @@ func (c *redisClient) delimiter() string {
 func (c *redisClient) retrieveInfo() (string, error) {
 	return c.client.Info().Result()
 }
+
+// Retrieve a single Redis INFO section.
+func (c *redisClient) retrieveInfoSection(section string) (string, error) {
+	return c.client.Info(section).Result()
+}
+
+// Retrieve Redis CLUSTER INFO.
+func (c *redisClient) retrieveClusterInfo() (string, error) {
+	return c.client.ClusterInfo().Result()
+}
+
+// Retrieve Redis LATENCY LATEST.
+func (c *redisClient) retrieveLatencyLatest() ([]latencyEvent, error) {
+	res, err := c.client.Do("latency", "latest").Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseLatencyLatest(res)
+}
+
+// Close the connections of the Redis client.
+func (c *redisClient) close() error {
+	return c.client.Close()
+}
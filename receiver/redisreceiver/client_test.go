@@ -39,6 +39,25 @@ func (fakeClient) retrieveInfo() (string, error) {
 	return readFile("info")
 }
 
+func (fakeClient) retrieveInfoSection(section string) (string, error) {
+	return readFile(section)
+}
+
+func (fakeClient) retrieveClusterInfo() (string, error) {
+	return readFile("cluster_info")
+}
+
+func (fakeClient) retrieveLatencyLatest() ([]latencyEvent, error) {
+	return []latencyEvent{
+		{name: "command", latest: 12, max: 40},
+		{name: "fork", latest: 3, max: 3},
+	}, nil
+}
+
+func (fakeClient) close() error {
+	return nil
+}
+
 func readFile(fname string) (string, error) {
 	file, err := ioutil.ReadFile(path.Join("testdata", fname+".txt"))
 	if err != nil {
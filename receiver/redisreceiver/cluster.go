@@ -0,0 +1,98 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// Builds proto metrics from the output of CLUSTER INFO, which has the same
+// format as INFO. Returns proto metrics and parsing errors, to be treated as
+// warnings, if there were any.
+func (i info) buildClusterProtoMetrics(t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	var stateOK int64
+	if i["cluster_state"] == "ok" {
+		stateOK = 1
+	}
+	protoMetrics = append(protoMetrics, newProtoMetric(&redisMetric{
+		name:   "redis/cluster/state",
+		desc:   "Whether the cluster state is ok, i.e. all the slots are served",
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: stateOK}}, t))
+
+	clusterMetrics, warnings := i.buildFixedProtoMetrics(getClusterRedisMetrics(), t)
+	return append(protoMetrics, clusterMetrics...), warnings
+}
+
+// Returns the metrics extracted from CLUSTER INFO.
+func getClusterRedisMetrics() []*redisMetric {
+	return []*redisMetric{
+		clusterSlots("cluster_slots_assigned", "assigned"),
+		clusterSlots("cluster_slots_ok", "ok"),
+		clusterSlots("cluster_slots_pfail", "pfail"),
+		clusterSlots("cluster_slots_fail", "fail"),
+		{
+			key:    "cluster_known_nodes",
+			name:   "redis/cluster/known_nodes",
+			desc:   "Number of nodes in the cluster, including nodes in handshake state",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			key:    "cluster_size",
+			name:   "redis/cluster/size",
+			desc:   "Number of master nodes serving at least one slot",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			key:    "cluster_current_epoch",
+			name:   "redis/cluster/current_epoch",
+			desc:   "The current epoch of the cluster",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			key:    "cluster_my_epoch",
+			name:   "redis/cluster/my_epoch",
+			desc:   "The config epoch of the node",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		},
+		{
+			key:    "cluster_stats_messages_sent",
+			name:   "redis/cluster/messages",
+			desc:   "Number of messages sent and received through the cluster bus",
+			labels: map[string]string{"direction": "sent"},
+			mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+		{
+			key:    "cluster_stats_messages_received",
+			name:   "redis/cluster/messages",
+			desc:   "Number of messages sent and received through the cluster bus",
+			labels: map[string]string{"direction": "received"},
+			mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+		},
+	}
+}
+
+func clusterSlots(key, state string) *redisMetric {
+	return &redisMetric{
+		key:    key,
+		name:   "redis/cluster/slots",
+		desc:   "Number of slots by state",
+		labels: map[string]string{"state": state},
+		mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+	}
+}
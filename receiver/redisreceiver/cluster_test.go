@@ -0,0 +1,53 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestClusterProtoMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	clusterInfo, err := svc.clusterInfo()
+	require.Nil(t, err)
+
+	metrics, warnings := clusterInfo.buildClusterProtoMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, len(getClusterRedisMetrics())+1, len(metrics))
+
+	require.Equal(t, "redis/cluster/state", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, int64(1), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/cluster/slots", metrics[1].MetricDescriptor.Name)
+	require.Equal(t, "assigned", labelValue(metrics[1], "state"))
+	require.Equal(t, int64(16384), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+
+	messages := metrics[len(metrics)-1]
+	require.Equal(t, "redis/cluster/messages", messages.MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, messages.MetricDescriptor.Type)
+	require.Equal(t, "received", labelValue(messages, "direction"))
+}
+
+func TestClusterProtoMetricsStateFail(t *testing.T) {
+	clusterInfo := info{"cluster_state": "fail"}
+	metrics, warnings := clusterInfo.buildClusterProtoMetrics(newTimeBundle(time.Now(), 100))
+	// All the other fields are missing.
+	require.Equal(t, len(getClusterRedisMetrics()), len(warnings))
+	require.Equal(t, 1, len(metrics))
+	require.Equal(t, int64(0), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+}
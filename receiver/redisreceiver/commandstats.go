@@ -0,0 +1,93 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+const commandstatsPrefix = "cmdstat_"
+
+// Builds proto metrics from the 'commandstats' INFO section:
+// e.g. "cmdstat_get:calls=21,usec=175,usec_per_call=8.33". Returns proto
+// metrics and parsing errors, to be treated as warnings, if there were any.
+func (i info) buildCommandstatsProtoMetrics(t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	for _, key := range i.sortedKeys() {
+		if !strings.HasPrefix(key, commandstatsPrefix) {
+			continue
+		}
+		cmd := strings.TrimPrefix(key, commandstatsPrefix)
+		fields, err := parseFields(i[key])
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("unexpected commandstats for %s: %w", cmd, err))
+			continue
+		}
+		labels := map[string]string{"cmd": cmd}
+		for _, m := range []struct {
+			field string
+			name  string
+			units string
+			desc  string
+		}{
+			{"calls", "redis/commands/calls", "", "Number of calls of the command"},
+			{"usec", "redis/commands/usec", "us", "Total CPU time consumed by the command"},
+		} {
+			val, ok := fields[m.field]
+			if !ok {
+				continue
+			}
+			metric := &redisMetric{
+				name:   m.name,
+				units:  m.units,
+				desc:   m.desc,
+				labels: labels,
+				mdType: metricspb.MetricDescriptor_CUMULATIVE_INT64,
+			}
+			protoMetric, parsingError := metric.parseMetric(val, t)
+			if parsingError != nil {
+				warnings = append(warnings, parsingError)
+				continue
+			}
+			protoMetrics = append(protoMetrics, protoMetric)
+		}
+	}
+	return protoMetrics, warnings
+}
+
+// Parses comma separated key=value fields, e.g. "calls=21,usec=175".
+func parseFields(str string) (map[string]string, error) {
+	fields := map[string]string{}
+	for _, pairStr := range strings.Split(str, ",") {
+		pair := strings.SplitN(pairStr, "=", 2)
+		if len(pair) != 2 {
+			return nil, fmt.Errorf("unexpected pair '%s'", pairStr)
+		}
+		fields[pair[0]] = pair[1]
+	}
+	return fields, nil
+}
+
+// Parses a numeric field, returning false if it is missing or not numeric.
+func parseIntField(fields map[string]string, key string) (int64, bool) {
+	val, err := strconv.ParseInt(fields[key], 10, 64)
+	return val, err == nil
+}
@@ -0,0 +1,55 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCommandstatsProtoMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	commandstats, err := svc.infoSection("commandstats")
+	require.Nil(t, err)
+
+	metrics, warnings := commandstats.buildCommandstatsProtoMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 6, len(metrics))
+
+	// Commands are sorted by name.
+	require.Equal(t, "redis/commands/calls", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_INT64, metrics[0].MetricDescriptor.Type)
+	require.Equal(t, "get", metrics[0].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, int64(21), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/commands/usec", metrics[1].MetricDescriptor.Name)
+	require.Equal(t, "us", metrics[1].MetricDescriptor.Unit)
+	require.Equal(t, int64(175), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "info", metrics[2].Timeseries[0].LabelValues[0].Value)
+	require.Equal(t, "set", metrics[4].Timeseries[0].LabelValues[0].Value)
+}
+
+func TestCommandstatsProtoMetricsInvalid(t *testing.T) {
+	commandstats := info{
+		"cmdstat_get": "calls=x,usec=175",
+		"cmdstat_set": "invalid",
+	}
+	metrics, warnings := commandstats.buildCommandstatsProtoMetrics(newTimeBundle(time.Now(), 100))
+	require.Equal(t, 2, len(warnings))
+	require.Equal(t, 1, len(metrics))
+	require.Equal(t, "redis/commands/usec", metrics[0].MetricDescriptor.Name)
+}
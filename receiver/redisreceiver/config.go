@@ -15,15 +15,19 @@
 package redisreceiver
 
 import (
+	"crypto/tls"
+	"errors"
+	"fmt"
 	"time"
 
+	"github.com/go-redis/redis/v7"
 	"go.opentelemetry.io/collector/config/configmodels"
+	"go.opentelemetry.io/collector/config/configtls"
 )
 
 type config struct {
 	configmodels.ReceiverSettings `mapstructure:",squash"`
-	// TODO: Use one of the configs from core.
-	// The target endpoint.
+	// The target endpoint, or the endpoint of a Sentinel when Sentinel is set.
 	Endpoint string `mapstructure:"endpoint"`
 	// The duration between Redis metric fetches.
 	CollectionInterval time.Duration `mapstructure:"collection_interval"`
@@ -31,9 +35,92 @@ type config struct {
 	// "service.name" Resource label.
 	ServiceName string `mapstructure:"service_name"`
 
-	// TODO allow users to add additional resource key value pairs?
+	// Additional Resource labels added to the metrics.
+	ResourceAttributes map[string]string `mapstructure:"resource_attributes"`
+
+	// Optional username of a Redis 6 ACL user. When unset, the password
+	// authenticates the default user.
+	Username string `mapstructure:"username"`
 
 	// Optional password. Must match the password specified in the
 	// requirepass server configuration option.
 	Password string `mapstructure:"password"`
+
+	// The TLS settings used to connect to Redis. No TLS is used when unset.
+	TLS *configtls.TLSClientSetting `mapstructure:"tls"`
+
+	// Optional Sentinel discovery. When set, the master and the replicas
+	// monitored by the Sentinel at Endpoint are discovered and scraped.
+	Sentinel *sentinelConfig `mapstructure:"sentinel"`
+
+	// Optional groups of metrics collected in addition to the INFO metrics:
+	// "commandstats", "replication", "cluster" and "latency".
+	MetricGroups []string `mapstructure:"metric_groups"`
+}
+
+type sentinelConfig struct {
+	// The name of the master monitored by the Sentinel.
+	MasterName string `mapstructure:"master_name"`
+	// Optional password of the Sentinel, when it differs from the password of
+	// the Redis instances.
+	Password string `mapstructure:"password"`
+}
+
+const (
+	// Per-command calls and CPU time, from INFO commandstats.
+	commandstatsMetricGroup = "commandstats"
+	// Per-replica lag and state, or the master link state of replicas, from
+	// INFO replication.
+	replicationMetricGroup = "replication"
+	// Slot and state metrics from CLUSTER INFO, for cluster enabled nodes.
+	clusterMetricGroup = "cluster"
+	// Latency spikes from LATENCY LATEST.
+	latencyMetricGroup = "latency"
+)
+
+var validMetricGroups = map[string]bool{
+	commandstatsMetricGroup: true,
+	replicationMetricGroup:  true,
+	clusterMetricGroup:      true,
+	latencyMetricGroup:      true,
+}
+
+// Returns the set of metric groups to collect, or an error if any is invalid.
+func (cfg *config) metricGroups() (map[string]bool, error) {
+	groups := make(map[string]bool, len(cfg.MetricGroups))
+	for _, group := range cfg.MetricGroups {
+		if !validMetricGroups[group] {
+			return nil, fmt.Errorf("metric group %q is not supported", group)
+		}
+		groups[group] = true
+	}
+	return groups, nil
+}
+
+func (cfg *config) validate() error {
+	if cfg.Sentinel != nil && cfg.Sentinel.MasterName == "" {
+		return errors.New("config.Sentinel.MasterName must be specified")
+	}
+	_, err := cfg.metricGroups()
+	return err
+}
+
+// Returns the Resource labels of the metrics of the instance.
+func (cfg *config) resourceLabels() map[string]string {
+	labels := make(map[string]string, len(cfg.ResourceAttributes)+1)
+	for k, v := range cfg.ResourceAttributes {
+		labels[k] = v
+	}
+	labels["service.name"] = cfg.ServiceName
+	return labels
+}
+
+// Returns the options of the client of the Redis instance at addr.
+func (cfg *config) redisOptions(addr string, tlsConfig *tls.Config) *redis.Options {
+	return &redis.Options{
+		Addr:      addr,
+		Username:  cfg.Username,
+		Password:  cfg.Password,
+		TLSConfig: tlsConfig,
+	}
 }
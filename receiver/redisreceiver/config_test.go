@@ -0,0 +1,52 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestValidateConfig(t *testing.T) {
+	cfg := createDefaultConfig().(*config)
+	cfg.Endpoint = "localhost:26379"
+	require.NoError(t, cfg.validate())
+
+	cfg.Sentinel = &sentinelConfig{}
+	require.EqualError(t, cfg.validate(), "config.Sentinel.MasterName must be specified")
+	cfg.Sentinel.MasterName = "mymaster"
+	require.NoError(t, cfg.validate())
+
+	cfg.MetricGroups = []string{commandstatsMetricGroup, "keyspace"}
+	require.EqualError(t, cfg.validate(), `metric group "keyspace" is not supported`)
+}
+
+func TestResourceLabels(t *testing.T) {
+	cfg := createDefaultConfig().(*config)
+	cfg.ServiceName = "my-redis"
+	cfg.ResourceAttributes = map[string]string{"env": "prod", "service.name": "ignored"}
+	require.Equal(t, map[string]string{"env": "prod", "service.name": "my-redis"}, cfg.resourceLabels())
+}
+
+func TestRedisOptions(t *testing.T) {
+	cfg := createDefaultConfig().(*config)
+	cfg.Username = "otel"
+	cfg.Password = "secret"
+	options := cfg.redisOptions("localhost:6379", nil)
+	require.Equal(t, "localhost:6379", options.Addr)
+	require.Equal(t, "otel", options.Username)
+	require.Equal(t, "secret", options.Password)
+}
@@ -53,6 +53,9 @@ func createMetricsReceiver(
 	consumer consumer.MetricsConsumer,
 ) (component.MetricsReceiver, error) {
 	oCfg := cfg.(*config)
+	if err := oCfg.validate(); err != nil {
+		return nil, err
+	}
 
 	return newRedisReceiver(params.Logger, oCfg, consumer), nil
 }
@@ -17,6 +17,7 @@ package redisreceiver
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strconv"
 
 	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
@@ -85,3 +86,14 @@ func (i info) getUptimeInSeconds() (int, error) {
 	}
 	return strconv.Atoi(uptimeStr)
 }
+
+// Returns the keys of the info map in order, so that metrics built by
+// iterating over them are reproducible.
+func (i info) sortedKeys() []string {
+	keys := make([]string, 0, len(i))
+	for key := range i {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,79 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// A latency spike of the LATENCY LATEST command, for an event such as "command"
+// or "fast-command".
+type latencyEvent struct {
+	name string
+	// The latest and all time maximum latencies, in milliseconds.
+	latest int64
+	max    int64
+}
+
+// Converts the reply of LATENCY LATEST, an array with an array of the event
+// name, timestamp, latest and maximum latency per event.
+func parseLatencyLatest(reply interface{}) ([]latencyEvent, error) {
+	entries, ok := reply.([]interface{})
+	if !ok {
+		return nil, fmt.Errorf("unexpected latency reply %v", reply)
+	}
+	events := make([]latencyEvent, 0, len(entries))
+	for _, entry := range entries {
+		fields, ok := entry.([]interface{})
+		if !ok || len(fields) != 4 {
+			return nil, fmt.Errorf("unexpected latency entry %v", entry)
+		}
+		name, nameOk := fields[0].(string)
+		latest, latestOk := fields[2].(int64)
+		max, maxOk := fields[3].(int64)
+		if !nameOk || !latestOk || !maxOk {
+			return nil, fmt.Errorf("unexpected latency entry %v", entry)
+		}
+		events = append(events, latencyEvent{name: name, latest: latest, max: max})
+	}
+	return events, nil
+}
+
+// Builds proto metrics from the latency spikes of LATENCY LATEST.
+func buildLatencyProtoMetrics(events []latencyEvent, t *timeBundle) []*metricspb.Metric {
+	protoMetrics := make([]*metricspb.Metric, 0, 2*len(events))
+	for _, event := range events {
+		labels := map[string]string{"event": event.name}
+		protoMetrics = append(protoMetrics,
+			newProtoMetric(&redisMetric{
+				name:   "redis/latency/latest",
+				units:  "ms",
+				desc:   "Latency of the latest spike of the event",
+				labels: labels,
+				mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+			}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: event.latest}}, t),
+			newProtoMetric(&redisMetric{
+				name:   "redis/latency/max",
+				units:  "ms",
+				desc:   "Maximum latency of the event since the server start",
+				labels: labels,
+				mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+			}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: event.max}}, t),
+		)
+	}
+	return protoMetrics
+}
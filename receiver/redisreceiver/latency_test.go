@@ -0,0 +1,67 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseLatencyLatest(t *testing.T) {
+	events, err := parseLatencyLatest([]interface{}{
+		[]interface{}{"command", int64(1602000000), int64(12), int64(40)},
+		[]interface{}{"fork", int64(1602000010), int64(3), int64(3)},
+	})
+	require.Nil(t, err)
+	require.Equal(t, []latencyEvent{
+		{name: "command", latest: 12, max: 40},
+		{name: "fork", latest: 3, max: 3},
+	}, events)
+
+	events, err = parseLatencyLatest([]interface{}{})
+	require.Nil(t, err)
+	require.Equal(t, 0, len(events))
+}
+
+func TestParseLatencyLatestInvalid(t *testing.T) {
+	_, err := parseLatencyLatest("OK")
+	require.Error(t, err)
+	_, err = parseLatencyLatest([]interface{}{
+		[]interface{}{"command", int64(1602000000), int64(12)},
+	})
+	require.Error(t, err)
+	_, err = parseLatencyLatest([]interface{}{
+		[]interface{}{"command", int64(1602000000), "12", int64(40)},
+	})
+	require.Error(t, err)
+}
+
+func TestLatencyProtoMetrics(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	events, err := svc.latencyLatest()
+	require.Nil(t, err)
+
+	metrics := buildLatencyProtoMetrics(events, newTimeBundle(time.Now(), 100))
+	require.Equal(t, 4, len(metrics))
+	require.Equal(t, "redis/latency/latest", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, "ms", metrics[0].MetricDescriptor.Unit)
+	require.Equal(t, "command", labelValue(metrics[0], "event"))
+	require.Equal(t, int64(12), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/latency/max", metrics[1].MetricDescriptor.Name)
+	require.Equal(t, int64(40), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "fork", labelValue(metrics[2], "event"))
+}
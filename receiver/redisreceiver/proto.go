@@ -23,11 +23,11 @@ import (
 
 // Helper functions that produce protobuf
 
-func newMetricsData(protoMetrics []*metricspb.Metric, serviceName string) consumerdata.MetricsData {
+func newMetricsData(protoMetrics []*metricspb.Metric, resourceLabels map[string]string) consumerdata.MetricsData {
 	return consumerdata.MetricsData{
 		Resource: &resourcepb.Resource{
 			Type:   typeStr,
-			Labels: map[string]string{"service.name": serviceName},
+			Labels: resourceLabels,
 		},
 		Metrics: protoMetrics,
 	}
@@ -165,7 +165,7 @@ func getMetricDataErr(metric *redisMetric, serverName string) (consumerdata.Metr
 		return consumerdata.MetricsData{}, nil, err
 	}
 	protoMetrics, warnings := info.buildFixedProtoMetrics(redisMetrics, getDefaultTimeBundle())
-	md := newMetricsData(protoMetrics, serverName)
+	md := newMetricsData(protoMetrics, map[string]string{"service.name": serverName})
 	return md, warnings, nil
 }
 
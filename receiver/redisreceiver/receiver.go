@@ -16,6 +16,8 @@ package redisreceiver
 
 import (
 	"context"
+	"crypto/tls"
+	"fmt"
 
 	"github.com/go-redis/redis/v7"
 	"go.opentelemetry.io/collector/component"
@@ -30,6 +32,8 @@ type redisReceiver struct {
 	config         *config
 	consumer       consumer.MetricsConsumer
 	intervalRunner *interval.Runner
+	// Closes the connections to Redis on shutdown.
+	closeClients func() error
 }
 
 func newRedisReceiver(
@@ -46,12 +50,36 @@ func newRedisReceiver(
 
 // Set up and kick off the interval runner.
 func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
-	c := newRedisClient(&redis.Options{
-		Addr:     r.config.Endpoint,
-		Password: r.config.Password,
-	})
-	redisRunnable := newRedisRunnable(ctx, c, r.config.ServiceName, r.consumer, r.logger)
-	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, redisRunnable)
+	var tlsConfig *tls.Config
+	if r.config.TLS != nil {
+		var err error
+		if tlsConfig, err = r.config.TLS.LoadTLSConfig(); err != nil {
+			return fmt.Errorf("failed to load TLS config: %w", err)
+		}
+	}
+
+	// The metric groups are validated when the receiver is created.
+	metricGroups, _ := r.config.metricGroups()
+	var runnable interval.Runnable
+	if r.config.Sentinel != nil {
+		sentinel := newRedisSentinelClient(&redis.Options{
+			Addr:      r.config.Endpoint,
+			Password:  r.config.Sentinel.Password,
+			TLSConfig: tlsConfig,
+		})
+		newClient := func(addr string) client {
+			return newRedisClient(r.config.redisOptions(addr, tlsConfig))
+		}
+		sentinelRunnable := newSentinelRunnable(ctx, sentinel, r.config.Sentinel.MasterName, newClient,
+			r.config.resourceLabels(), metricGroups, r.consumer, r.logger)
+		runnable = sentinelRunnable
+		r.closeClients = sentinelRunnable.close
+	} else {
+		c := newRedisClient(r.config.redisOptions(r.config.Endpoint, tlsConfig))
+		runnable = newRedisRunnable(ctx, c, r.config.resourceLabels(), metricGroups, r.consumer, r.logger)
+		r.closeClients = c.close
+	}
+	r.intervalRunner = interval.NewRunner(r.config.CollectionInterval, runnable)
 
 	go func() {
 		if err := r.intervalRunner.Start(); err != nil {
@@ -64,5 +92,5 @@ func (r *redisReceiver) Start(ctx context.Context, host component.Host) error {
 
 func (r *redisReceiver) Shutdown(ctx context.Context) error {
 	r.intervalRunner.Stop()
-	return nil
+	return r.closeClients()
 }
@@ -16,8 +16,10 @@ package redisreceiver
 
 import (
 	"context"
+	"fmt"
 	"time"
 
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
 	"go.opentelemetry.io/collector/consumer"
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/internaldata"
@@ -37,19 +39,22 @@ type redisRunnable struct {
 	redisMetrics    []*redisMetric
 	logger          *zap.Logger
 	timeBundle      *timeBundle
-	serviceName     string
+	resourceLabels  map[string]string
+	metricGroups    map[string]bool
 }
 
 func newRedisRunnable(
 	ctx context.Context,
 	client client,
-	serviceName string,
+	resourceLabels map[string]string,
+	metricGroups map[string]bool,
 	metricsConsumer consumer.MetricsConsumer,
 	logger *zap.Logger,
 ) *redisRunnable {
 	return &redisRunnable{
 		ctx:             ctx,
-		serviceName:     serviceName,
+		resourceLabels:  resourceLabels,
+		metricGroups:    metricGroups,
 		redisSvc:        newRedisSvc(client),
 		metricsConsumer: metricsConsumer,
 		logger:          logger,
@@ -67,7 +72,8 @@ func (r *redisRunnable) Setup() error {
 // the next consumer. First builds 'fixed' metrics (non-keyspace metrics)
 // defined at startup time. Then builds 'keyspace' metrics if there are any
 // keyspace lines returned by Redis. There should be one keyspace line per
-// active Redis database, of which there can be 16.
+// active Redis database, of which there can be 16. Finally builds the metrics
+// of the configured metric groups.
 func (r *redisRunnable) Run() error {
 	const dataFormat = "redis"
 	const transport = "http" // todo verify this
@@ -108,7 +114,9 @@ func (r *redisRunnable) Run() error {
 		)
 	}
 
-	md := newMetricsData(metrics, r.serviceName)
+	metrics = append(metrics, r.buildMetricGroupsProtoMetrics(inf)...)
+
+	md := newMetricsData(metrics, r.resourceLabels)
 
 	err = r.metricsConsumer.ConsumeMetrics(r.ctx, internaldata.OCToMetrics(md))
 	numTimeSeries, numPoints := obsreport.CountMetricPoints(md)
@@ -116,3 +124,52 @@ func (r *redisRunnable) Run() error {
 
 	return nil
 }
+
+// Builds the metrics of the configured metric groups, querying Redis for the
+// ones that INFO doesn't return. Errors are logged as warnings so that the
+// metrics of the other groups are still reported.
+func (r *redisRunnable) buildMetricGroupsProtoMetrics(inf info) []*metricspb.Metric {
+	var metrics []*metricspb.Metric
+	var warnings []error
+	add := func(groupMetrics []*metricspb.Metric, groupWarnings []error) {
+		metrics = append(metrics, groupMetrics...)
+		warnings = append(warnings, groupWarnings...)
+	}
+
+	if r.metricGroups[commandstatsMetricGroup] {
+		if commandstats, err := r.redisSvc.infoSection("commandstats"); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to retrieve commandstats: %w", err))
+		} else {
+			add(commandstats.buildCommandstatsProtoMetrics(r.timeBundle))
+		}
+	}
+
+	if r.metricGroups[replicationMetricGroup] {
+		add(inf.buildReplicationProtoMetrics(r.timeBundle))
+	}
+
+	// CLUSTER INFO fails on nodes without cluster support.
+	if r.metricGroups[clusterMetricGroup] && inf["cluster_enabled"] == "1" {
+		if clusterInfo, err := r.redisSvc.clusterInfo(); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to retrieve cluster info: %w", err))
+		} else {
+			add(clusterInfo.buildClusterProtoMetrics(r.timeBundle))
+		}
+	}
+
+	if r.metricGroups[latencyMetricGroup] {
+		if events, err := r.redisSvc.latencyLatest(); err != nil {
+			warnings = append(warnings, fmt.Errorf("failed to retrieve latency: %w", err))
+		} else {
+			add(buildLatencyProtoMetrics(events, r.timeBundle), nil)
+		}
+	}
+
+	if warnings != nil {
+		r.logger.Warn(
+			"errors collecting metric groups",
+			zap.Errors("errors", warnings),
+		)
+	}
+	return metrics
+}
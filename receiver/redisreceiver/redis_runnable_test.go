@@ -26,7 +26,7 @@ import (
 func TestRedisRunnable(t *testing.T) {
 	consumer := &exportertest.SinkMetricsExporter{}
 	logger, _ := zap.NewDevelopment()
-	runner := newRedisRunnable(context.Background(), newFakeClient(), "", consumer, logger)
+	runner := newRedisRunnable(context.Background(), newFakeClient(), nil, nil, consumer, logger)
 	err := runner.Setup()
 	require.Nil(t, err)
 	err = runner.Run()
@@ -34,3 +34,23 @@ func TestRedisRunnable(t *testing.T) {
 	// + 6 because there are two keyspace entries each of which has three metrics
 	require.Equal(t, len(getDefaultRedisMetrics())+6, consumer.MetricsCount())
 }
+
+func TestRedisRunnableWithMetricGroups(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporter{}
+	logger, _ := zap.NewDevelopment()
+	metricGroups := map[string]bool{
+		commandstatsMetricGroup: true,
+		replicationMetricGroup:  true,
+		clusterMetricGroup:      true,
+		latencyMetricGroup:      true,
+	}
+	runner := newRedisRunnable(context.Background(), newFakeClient(), nil, metricGroups, consumer, logger)
+	err := runner.Setup()
+	require.Nil(t, err)
+	err = runner.Run()
+	require.Nil(t, err)
+	// + 6 commandstats metrics for three commands and + 4 latency metrics for
+	// two events. The fake server is a master without replicas nor cluster
+	// support, so there are no replication nor cluster metrics.
+	require.Equal(t, len(getDefaultRedisMetrics())+6+6+4, consumer.MetricsCount())
+}
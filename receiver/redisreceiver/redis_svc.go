@@ -39,16 +39,47 @@ func (p *redisSvc) info() (info, error) {
 	if err != nil {
 		return nil, err
 	}
+	return p.parseInfo(str), nil
+}
+
+// Calls the Redis INFO command for a single section on the client and returns
+// an `info` map.
+func (p *redisSvc) infoSection(section string) (info, error) {
+	str, err := p.client.retrieveInfoSection(section)
+	if err != nil {
+		return nil, err
+	}
+	return p.parseInfo(str), nil
+}
+
+// Calls the Redis CLUSTER INFO command on the client and returns an `info`
+// map, since its output has the same format as INFO.
+func (p *redisSvc) clusterInfo() (info, error) {
+	str, err := p.client.retrieveClusterInfo()
+	if err != nil {
+		return nil, err
+	}
+	return p.parseInfo(str), nil
+}
+
+// Calls the Redis LATENCY LATEST command on the client.
+func (p *redisSvc) latencyLatest() ([]latencyEvent, error) {
+	return p.client.retrieveLatencyLatest()
+}
+
+// Parses the key value pairs of INFO output, skipping section headers.
+func (p *redisSvc) parseInfo(str string) info {
 	lines := strings.Split(str, p.delimiter)
 	attrs := make(map[string]string)
 	for _, line := range lines {
 		if len(line) == 0 || strings.HasPrefix(line, "#") {
 			continue
 		}
-		pair := strings.Split(line, ":")
+		// Values may contain colons, e.g. the IPv6 addresses of replicas.
+		pair := strings.SplitN(line, ":", 2)
 		if len(pair) == 2 { // defensive, should always == 2
 			attrs[pair[0]] = pair[1]
 		}
 	}
-	return attrs, nil
+	return attrs
 }
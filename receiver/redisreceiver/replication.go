@@ -0,0 +1,121 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+)
+
+// Builds proto metrics from the 'replication' INFO section. On a master, the
+// lag, offset and state of each replica are reported from lines such as
+// "slave0:ip=10.0.0.2,port=6379,state=online,offset=1234,lag=0". On a replica,
+// the state of the link to its master is reported. Returns proto metrics and
+// parsing errors, to be treated as warnings, if there were any.
+func (i info) buildReplicationProtoMetrics(t *timeBundle) (
+	protoMetrics []*metricspb.Metric,
+	warnings []error,
+) {
+	for _, key := range i.sortedKeys() {
+		if !isReplicaKey(key) {
+			continue
+		}
+		fields, err := parseFields(i[key])
+		if err != nil {
+			warnings = append(warnings, fmt.Errorf("unexpected replica %s: %w", key, err))
+			continue
+		}
+		protoMetrics = append(protoMetrics, buildReplicaMetrics(fields, t)...)
+	}
+
+	if i["role"] == "slave" {
+		var linkUp int64
+		if i["master_link_status"] == "up" {
+			linkUp = 1
+		}
+		protoMetrics = append(protoMetrics, newProtoMetric(&redisMetric{
+			name:   "redis/replication/master_link_up",
+			desc:   "Whether the link to the master is up",
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: linkUp}}, t))
+
+		replicaMetrics, replicaWarnings := i.buildFixedProtoMetrics([]*redisMetric{
+			{
+				key:    "master_last_io_seconds_ago",
+				name:   "redis/replication/master_last_io",
+				units:  "s",
+				desc:   "Time since the last interaction with the master",
+				mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+			},
+			{
+				key:    "slave_repl_offset",
+				name:   "redis/replication/replica_offset",
+				units:  "By",
+				desc:   "The replication offset of the replica",
+				mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+			},
+		}, t)
+		protoMetrics = append(protoMetrics, replicaMetrics...)
+		warnings = append(warnings, replicaWarnings...)
+	}
+	return protoMetrics, warnings
+}
+
+// Returns whether the key is the one of a replica of a master, e.g. "slave0".
+func isReplicaKey(key string) bool {
+	if !strings.HasPrefix(key, "slave") {
+		return false
+	}
+	_, err := strconv.Atoi(strings.TrimPrefix(key, "slave"))
+	return err == nil
+}
+
+func buildReplicaMetrics(fields map[string]string, t *timeBundle) []*metricspb.Metric {
+	replica := net.JoinHostPort(fields["ip"], fields["port"])
+	labels := map[string]string{"replica": replica}
+
+	var metrics []*metricspb.Metric
+	if lag, ok := parseIntField(fields, "lag"); ok {
+		metrics = append(metrics, newProtoMetric(&redisMetric{
+			name:   "redis/replication/replica/lag",
+			units:  "s",
+			desc:   "Time since the last acknowledgement of the replica",
+			labels: labels,
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: lag}}, t))
+	}
+	if offset, ok := parseIntField(fields, "offset"); ok {
+		metrics = append(metrics, newProtoMetric(&redisMetric{
+			name:   "redis/replication/replica/offset",
+			units:  "By",
+			desc:   "The replication offset acknowledged by the replica",
+			labels: labels,
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: offset}}, t))
+	}
+	if state, ok := fields["state"]; ok {
+		metrics = append(metrics, newProtoMetric(&redisMetric{
+			name:   "redis/replication/replica/state",
+			desc:   "The state of the replica, e.g. online or wait_bgsave, always 1",
+			labels: map[string]string{"replica": replica, "state": state},
+			mdType: metricspb.MetricDescriptor_GAUGE_INT64,
+		}, &metricspb.Point{Value: &metricspb.Point_Int64Value{Int64Value: 1}}, t))
+	}
+	return metrics
+}
@@ -0,0 +1,80 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReplicationProtoMetricsMaster(t *testing.T) {
+	str, err := readFile("replication_master")
+	require.Nil(t, err)
+	replication := newFakeAPIParser().parseInfo(str)
+
+	metrics, warnings := replication.buildReplicationProtoMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 6, len(metrics))
+
+	require.Equal(t, "redis/replication/replica/lag", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, "10.0.0.2:6379", labelValue(metrics[0], "replica"))
+	require.Equal(t, int64(0), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/replication/replica/offset", metrics[1].MetricDescriptor.Name)
+	require.Equal(t, int64(3156), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/replication/replica/state", metrics[2].MetricDescriptor.Name)
+	require.Equal(t, "online", labelValue(metrics[2], "state"))
+
+	require.Equal(t, "[fe80::1]:6380", labelValue(metrics[3], "replica"))
+	require.Equal(t, int64(1), metrics[3].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "wait_bgsave", labelValue(metrics[5], "state"))
+}
+
+func TestReplicationProtoMetricsReplica(t *testing.T) {
+	str, err := readFile("replication_replica")
+	require.Nil(t, err)
+	replication := newFakeAPIParser().parseInfo(str)
+
+	metrics, warnings := replication.buildReplicationProtoMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 3, len(metrics))
+	require.Equal(t, "redis/replication/master_link_up", metrics[0].MetricDescriptor.Name)
+	require.Equal(t, int64(1), metrics[0].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/replication/master_last_io", metrics[1].MetricDescriptor.Name)
+	require.Equal(t, int64(3), metrics[1].Timeseries[0].Points[0].GetInt64Value())
+	require.Equal(t, "redis/replication/replica_offset", metrics[2].MetricDescriptor.Name)
+	require.Equal(t, int64(3156), metrics[2].Timeseries[0].Points[0].GetInt64Value())
+}
+
+func TestReplicationProtoMetricsNoReplicas(t *testing.T) {
+	svc := newRedisSvc(newFakeClient())
+	inf, err := svc.info()
+	require.Nil(t, err)
+
+	metrics, warnings := inf.buildReplicationProtoMetrics(newTimeBundle(time.Now(), 100))
+	require.Nil(t, warnings)
+	require.Equal(t, 0, len(metrics))
+}
+
+func labelValue(metric *metricspb.Metric, key string) string {
+	for i, labelKey := range metric.MetricDescriptor.LabelKeys {
+		if labelKey.Key == key {
+			return metric.Timeseries[0].LabelValues[i].Value
+		}
+	}
+	return ""
+}
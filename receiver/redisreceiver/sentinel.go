@@ -0,0 +1,286 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/go-redis/redis/v7"
+	"go.opentelemetry.io/collector/consumer"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/redisreceiver/interval"
+)
+
+const (
+	masterRole  = "master"
+	replicaRole = "replica"
+)
+
+// Interface for a Redis Sentinel client. Implementation can be faked for
+// testing.
+type sentinelClient interface {
+	// retrieves the address of the master monitored under the given name
+	retrieveMasterAddr(masterName string) (string, error)
+	// retrieves the addresses of the healthy replicas of the given master
+	retrieveReplicaAddrs(masterName string) ([]string, error)
+	// closes the connections to the sentinel
+	close() error
+}
+
+// Wraps a real Redis Sentinel client, implements `sentinelClient` interface.
+type redisSentinelClient struct {
+	client *redis.SentinelClient
+}
+
+var _ sentinelClient = (*redisSentinelClient)(nil)
+
+// Creates a new real Redis Sentinel client from the passed-in redis.Options.
+func newRedisSentinelClient(options *redis.Options) sentinelClient {
+	return &redisSentinelClient{
+		client: redis.NewSentinelClient(options),
+	}
+}
+
+// Retrieve the master address with SENTINEL get-master-addr-by-name.
+func (c *redisSentinelClient) retrieveMasterAddr(masterName string) (string, error) {
+	res, err := c.client.GetMasterAddrByName(masterName).Result()
+	if err != nil {
+		return "", err
+	}
+	if len(res) != 2 {
+		return "", fmt.Errorf("unexpected master address %v", res)
+	}
+	return net.JoinHostPort(res[0], res[1]), nil
+}
+
+// Retrieve the replica addresses with SENTINEL replicas.
+func (c *redisSentinelClient) retrieveReplicaAddrs(masterName string) ([]string, error) {
+	res, err := c.client.Slaves(masterName).Result()
+	if err != nil {
+		return nil, err
+	}
+	return parseSentinelReplicas(res)
+}
+
+// Close the connections of the Redis Sentinel client.
+func (c *redisSentinelClient) close() error {
+	return c.client.Close()
+}
+
+// Parses the reply of SENTINEL replicas, a list of flat lists of field names
+// and values per replica, into the addresses of the replicas that the
+// Sentinel doesn't consider down or disconnected.
+func parseSentinelReplicas(reply []interface{}) ([]string, error) {
+	var addrs []string
+	for _, entry := range reply {
+		values, ok := entry.([]interface{})
+		if !ok || len(values)%2 != 0 {
+			return nil, fmt.Errorf("unexpected replica %v", entry)
+		}
+		fields := make(map[string]string, len(values)/2)
+		for i := 0; i < len(values); i += 2 {
+			key, keyOk := values[i].(string)
+			val, valOk := values[i+1].(string)
+			if !keyOk || !valOk {
+				return nil, fmt.Errorf("unexpected replica %v", entry)
+			}
+			fields[key] = val
+		}
+		if fields["ip"] == "" || fields["port"] == "" {
+			return nil, fmt.Errorf("unexpected replica %v", entry)
+		}
+		if isReplicaDown(fields["flags"]) {
+			continue
+		}
+		addrs = append(addrs, net.JoinHostPort(fields["ip"], fields["port"]))
+	}
+	return addrs, nil
+}
+
+func isReplicaDown(flags string) bool {
+	for _, flag := range strings.Split(flags, ",") {
+		switch flag {
+		case "s_down", "o_down", "disconnected":
+			return true
+		}
+	}
+	return false
+}
+
+var _ interval.Runnable = (*sentinelRunnable)(nil)
+
+// Runs intermittently, discovering the master and the replicas monitored by a
+// Sentinel and running a redisRunnable for each of them.
+type sentinelRunnable struct {
+	ctx             context.Context
+	sentinel        sentinelClient
+	masterName      string
+	newClient       func(addr string) client
+	resourceLabels  map[string]string
+	metricGroups    map[string]bool
+	metricsConsumer consumer.MetricsConsumer
+	logger          *zap.Logger
+
+	// mu serializes Run and close, so that the instances aren't closed while
+	// being scraped or discovered again after being closed.
+	mu        sync.Mutex
+	instances map[string]*sentinelInstance
+	closed    bool
+}
+
+// A discovered Redis instance.
+type sentinelInstance struct {
+	role     string
+	client   client
+	runnable *redisRunnable
+}
+
+func newSentinelRunnable(
+	ctx context.Context,
+	sentinel sentinelClient,
+	masterName string,
+	newClient func(addr string) client,
+	resourceLabels map[string]string,
+	metricGroups map[string]bool,
+	metricsConsumer consumer.MetricsConsumer,
+	logger *zap.Logger,
+) *sentinelRunnable {
+	return &sentinelRunnable{
+		ctx:             ctx,
+		sentinel:        sentinel,
+		masterName:      masterName,
+		newClient:       newClient,
+		resourceLabels:  resourceLabels,
+		metricGroups:    metricGroups,
+		metricsConsumer: metricsConsumer,
+		logger:          logger,
+		instances:       map[string]*sentinelInstance{},
+	}
+}
+
+func (r *sentinelRunnable) Setup() error {
+	return nil
+}
+
+// Run is called periodically, discovering the instances behind the Sentinel
+// and then running each of them. When the discovery fails the previously
+// discovered instances are still run.
+func (r *sentinelRunnable) Run() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.closed {
+		return nil
+	}
+
+	roles, err := r.discover()
+	if err != nil {
+		r.logger.Warn(
+			"failed to discover Redis instances through Sentinel",
+			zap.String("master_name", r.masterName),
+			zap.Error(err),
+		)
+	} else {
+		r.updateInstances(roles)
+	}
+
+	for _, addr := range r.sortedAddrs() {
+		if err := r.instances[addr].runnable.Run(); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// Returns the role of each instance behind the Sentinel, by address.
+func (r *sentinelRunnable) discover() (map[string]string, error) {
+	master, err := r.sentinel.retrieveMasterAddr(r.masterName)
+	if err != nil {
+		return nil, err
+	}
+	replicas, err := r.sentinel.retrieveReplicaAddrs(r.masterName)
+	if err != nil {
+		return nil, err
+	}
+	roles := map[string]string{master: masterRole}
+	for _, replica := range replicas {
+		roles[replica] = replicaRole
+	}
+	return roles, nil
+}
+
+// Creates the runnables of new instances, and of instances whose role changed
+// e.g. after a failover, and closes the instances that are gone.
+func (r *sentinelRunnable) updateInstances(roles map[string]string) {
+	for addr, instance := range r.instances {
+		if roles[addr] != instance.role {
+			r.closeInstance(addr)
+		}
+	}
+	for addr, role := range roles {
+		if _, ok := r.instances[addr]; ok {
+			continue
+		}
+		r.instances[addr] = r.newInstance(addr, role)
+	}
+}
+
+func (r *sentinelRunnable) newInstance(addr, role string) *sentinelInstance {
+	labels := make(map[string]string, len(r.resourceLabels)+2)
+	for k, v := range r.resourceLabels {
+		labels[k] = v
+	}
+	labels["redis.endpoint"] = addr
+	labels["redis.role"] = role
+
+	c := r.newClient(addr)
+	runnable := newRedisRunnable(r.ctx, c, labels, r.metricGroups, r.metricsConsumer, r.logger)
+	// Setup can't fail, it only builds the metric definitions.
+	_ = runnable.Setup()
+	return &sentinelInstance{role: role, client: c, runnable: runnable}
+}
+
+func (r *sentinelRunnable) closeInstance(addr string) {
+	if err := r.instances[addr].client.close(); err != nil {
+		r.logger.Warn("failed to close Redis client", zap.String("endpoint", addr), zap.Error(err))
+	}
+	delete(r.instances, addr)
+}
+
+func (r *sentinelRunnable) sortedAddrs() []string {
+	addrs := make([]string, 0, len(r.instances))
+	for addr := range r.instances {
+		addrs = append(addrs, addr)
+	}
+	sort.Strings(addrs)
+	return addrs
+}
+
+// Closes the clients of the discovered instances and of the Sentinel, once
+// the Run in progress, if any, returns.
+func (r *sentinelRunnable) close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = true
+	for addr := range r.instances {
+		r.closeInstance(addr)
+	}
+	return r.sentinel.close()
+}
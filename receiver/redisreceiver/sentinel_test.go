@@ -0,0 +1,124 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package redisreceiver
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.uber.org/zap"
+)
+
+type fakeSentinelClient struct {
+	master   string
+	replicas []string
+	err      error
+	closed   bool
+}
+
+var _ sentinelClient = (*fakeSentinelClient)(nil)
+
+func (c *fakeSentinelClient) retrieveMasterAddr(string) (string, error) {
+	return c.master, c.err
+}
+
+func (c *fakeSentinelClient) retrieveReplicaAddrs(string) ([]string, error) {
+	return c.replicas, c.err
+}
+
+func (c *fakeSentinelClient) close() error {
+	c.closed = true
+	return nil
+}
+
+func TestParseSentinelReplicas(t *testing.T) {
+	addrs, err := parseSentinelReplicas([]interface{}{
+		[]interface{}{"name", "10.0.0.2:6379", "ip", "10.0.0.2", "port", "6379", "flags", "slave"},
+		[]interface{}{"name", "10.0.0.3:6379", "ip", "10.0.0.3", "port", "6379", "flags", "s_down,slave,disconnected"},
+		[]interface{}{"name", "[fe80::1]:6380", "ip", "fe80::1", "port", "6380", "flags", "slave"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{"10.0.0.2:6379", "[fe80::1]:6380"}, addrs)
+}
+
+func TestParseSentinelReplicasInvalid(t *testing.T) {
+	_, err := parseSentinelReplicas([]interface{}{"10.0.0.2:6379"})
+	require.Error(t, err)
+	_, err = parseSentinelReplicas([]interface{}{[]interface{}{"ip", "10.0.0.2", "port"}})
+	require.Error(t, err)
+	_, err = parseSentinelReplicas([]interface{}{[]interface{}{"ip", "10.0.0.2", "flags", "slave"}})
+	require.Error(t, err)
+}
+
+func TestSentinelRunnable(t *testing.T) {
+	consumer := &exportertest.SinkMetricsExporter{}
+	logger, _ := zap.NewDevelopment()
+	sentinel := &fakeSentinelClient{
+		master:   "10.0.0.1:6379",
+		replicas: []string{"10.0.0.2:6379", "10.0.0.3:6379"},
+	}
+	var clients []string
+	newClient := func(addr string) client {
+		clients = append(clients, addr)
+		return newFakeClient()
+	}
+	runner := newSentinelRunnable(context.Background(), sentinel, "mymaster", newClient,
+		map[string]string{"service.name": "redis", "env": "test"}, nil, consumer, logger)
+	require.NoError(t, runner.Setup())
+
+	require.NoError(t, runner.Run())
+	require.Equal(t, 3, len(consumer.AllMetrics()))
+	require.Equal(t, 3, len(clients))
+	require.Equal(t, masterRole, runner.instances["10.0.0.1:6379"].role)
+	require.Equal(t, replicaRole, runner.instances["10.0.0.2:6379"].role)
+	require.Equal(t, map[string]string{
+		"service.name":   "redis",
+		"env":            "test",
+		"redis.endpoint": "10.0.0.1:6379",
+		"redis.role":     masterRole,
+	}, runner.instances["10.0.0.1:6379"].runnable.resourceLabels)
+
+	// Failover: a replica is promoted and the former master is gone.
+	sentinel.master = "10.0.0.2:6379"
+	sentinel.replicas = []string{"10.0.0.3:6379"}
+	consumer.Reset()
+	require.NoError(t, runner.Run())
+	require.Equal(t, 2, len(consumer.AllMetrics()))
+	require.Equal(t, 2, len(runner.instances))
+	require.Equal(t, masterRole, runner.instances["10.0.0.2:6379"].role)
+	// Only the promoted replica got a new client.
+	require.Equal(t, 4, len(clients))
+
+	// The known instances are still scraped when the discovery fails.
+	sentinel.err = errors.New("connection refused")
+	consumer.Reset()
+	require.NoError(t, runner.Run())
+	require.Equal(t, 2, len(consumer.AllMetrics()))
+
+	require.NoError(t, runner.close())
+	require.Equal(t, 0, len(runner.instances))
+	require.True(t, sentinel.closed)
+
+	// Runs after the runnable is closed don't discover instances again.
+	sentinel.err = nil
+	consumer.Reset()
+	require.NoError(t, runner.Run())
+	require.Equal(t, 0, len(runner.instances))
+	require.Equal(t, 0, len(consumer.AllMetrics()))
+	require.Equal(t, 4, len(clients))
+}
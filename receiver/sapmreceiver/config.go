@@ -27,4 +27,8 @@ type Config struct {
 	confighttp.HTTPServerSettings `mapstructure:",squash"` // squash ensures fields are correctly decoded in embedded struct
 
 	splunk.AccessTokenPassthroughConfig `mapstructure:",squash"`
+
+	// Auth configures the validation of the access tokens of requests. Requests
+	// are accepted without validation when it isn't set.
+	Auth *splunk.AuthConfig `mapstructure:"auth"`
 }
@@ -17,6 +17,7 @@ package sapmreceiver
 import (
 	"path"
 	"testing"
+	"time"
 
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -42,7 +43,7 @@ func TestLoadConfig(t *testing.T) {
 
 	// The receiver `sapm/disabled` doesn't count because disabled receivers
 	// are excluded from the final list.
-	assert.Equal(t, len(cfg.Receivers), 4)
+	assert.Equal(t, len(cfg.Receivers), 5)
 
 	r0 := cfg.Receivers["sapm"]
 	assert.Equal(t, r0, factory.CreateDefaultConfig())
@@ -91,4 +92,27 @@ func TestLoadConfig(t *testing.T) {
 				AccessTokenPassthrough: true,
 			},
 		})
+
+	r4 := cfg.Receivers["sapm/auth"].(*Config)
+	assert.Equal(t, r4,
+		&Config{
+			ReceiverSettings: configmodels.ReceiverSettings{
+				TypeVal: typeStr,
+				NameVal: "sapm/auth",
+			},
+			HTTPServerSettings: confighttp.HTTPServerSettings{
+				Endpoint: ":7276",
+			},
+			Auth: &splunk.AuthConfig{
+				Tokens: []splunk.TokenConfig{
+					{Token: "Token0", Tenant: "acme"},
+				},
+				TokensFile: "/etc/otel/sapm_tokens",
+				Introspection: &splunk.IntrospectionConfig{
+					Endpoint: "https://auth.example.com/introspect",
+					Timeout:  2 * time.Second,
+					CacheTTL: 5 * time.Minute,
+				},
+			},
+		})
 }
@@ -56,14 +56,19 @@ type sapmReceiver struct {
 
 	nextConsumer consumer.TraceConsumer
 
+	// authenticator validates the access tokens of requests, if auth is
+	// configured.
+	authenticator splunk.Authenticator
+
 	// defaultResponse is a placeholder. For now this receiver returns an empty sapm response.
 	// This defaultResponse is an optimization so we don't have to proto.Marshal the response
 	// for every request. At some point this may be removed when there is actual content to return.
 	defaultResponse []byte
 }
 
-// handleRequest parses an http request containing sapm and passes the trace data to the next consumer
-func (sr *sapmReceiver) handleRequest(ctx context.Context, req *http.Request) error {
+// handleRequest parses an http request containing sapm and passes the trace data to the next consumer.
+// The tenant of the access token of the request, if any, is recorded on the resources of the spans.
+func (sr *sapmReceiver) handleRequest(ctx context.Context, req *http.Request, tenant string) error {
 	sapm, err := sapmprotocol.ParseTraceV2Request(req)
 	// errors processing the request should return http.StatusBadRequest
 	if err != nil {
@@ -92,6 +97,20 @@ func (sr *sapmReceiver) handleRequest(ctx context.Context, req *http.Request) er
 		}
 	}
 
+	if tenant != "" {
+		rSpans := td.ResourceSpans()
+		for i := 0; i < rSpans.Len(); i++ {
+			rSpan := rSpans.At(i)
+			if !rSpan.IsNil() {
+				resource := rSpan.Resource()
+				if resource.IsNil() {
+					resource.InitEmpty()
+				}
+				resource.Attributes().UpsertString(splunk.TenantLabel, tenant)
+			}
+		}
+	}
+
 	// pass the trace data to the next consumer
 	err = sr.nextConsumer.ConsumeTraces(ctx, td)
 	if err != nil {
@@ -107,8 +126,20 @@ func (sr *sapmReceiver) HTTPHandlerFunc(rw http.ResponseWriter, req *http.Reques
 	// create context with the receiver name from the request context
 	ctx := obsreport.ReceiverContext(req.Context(), sr.config.Name(), "http", "")
 
+	// validate the access token before the payload is parsed
+	var tenant string
+	if sr.authenticator != nil {
+		var err error
+		tenant, err = sr.authenticator.Authenticate(ctx, req.Header.Get(splunk.SFxAccessTokenHeader))
+		if err != nil {
+			sr.logger.Debug("SAPM request failed authentication", zap.Error(err))
+			rw.WriteHeader(splunk.AuthStatusCode(err))
+			return
+		}
+	}
+
 	// handle the request payload
-	err := sr.handleRequest(ctx, req)
+	err := sr.handleRequest(ctx, req, tenant)
 	if err != nil {
 		// TODO account for this error (throttled logging or metrics)
 		rw.WriteHeader(http.StatusBadRequest)
@@ -228,10 +259,17 @@ func New(
 	if err != nil {
 		return nil, fmt.Errorf("failed to marshal default response body for %s receiver: %v", config.Name(), err)
 	}
+	var authenticator splunk.Authenticator
+	if config.Auth != nil {
+		if authenticator, err = splunk.NewAuthenticator(*config.Auth); err != nil {
+			return nil, fmt.Errorf("failed to create the authenticator of %s receiver: %w", config.Name(), err)
+		}
+	}
 	return &sapmReceiver{
 		logger:          params.Logger,
 		config:          config,
 		nextConsumer:    nextConsumer,
+		authenticator:   authenticator,
 		defaultResponse: defaultResponseBytes,
 	}, nil
 }
@@ -390,3 +390,82 @@ func TestAccessTokenPassthrough(t *testing.T) {
 		})
 	}
 }
+
+func TestAuth(t *testing.T) {
+	tests := []struct {
+		name       string
+		token      string
+		wantStatus int
+		wantTenant string
+	}{
+		{
+			name:       "no token",
+			token:      "",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "invalid token",
+			token:      "OtherAccessToken",
+			wantStatus: http.StatusUnauthorized,
+		},
+		{
+			name:       "valid token",
+			token:      "MyAccessToken",
+			wantStatus: http.StatusOK,
+			wantTenant: "acme",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := &Config{
+				HTTPServerSettings: confighttp.HTTPServerSettings{
+					Endpoint: defaultEndpoint,
+				},
+				Auth: &splunk.AuthConfig{
+					Tokens: []splunk.TokenConfig{
+						{Token: "MyAccessToken", Tenant: "acme"},
+					},
+				},
+			}
+
+			sapm := &splunksapm.PostSpansRequest{
+				Batches: []*model.Batch{grpcFixture(time.Now().UTC(), time.Minute*10, time.Second*2)},
+			}
+
+			sink := new(exportertest.SinkTraceExporter)
+			sr := setupReceiver(t, config, sink)
+			defer sr.Shutdown(context.Background())
+
+			resp, err := sendSapm(config.Endpoint, sapm, true, false, tt.token)
+			require.NoErrorf(t, err, "should not have failed when sending sapm %v", err)
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+
+			got := sink.AllTraces()
+			if tt.wantStatus != http.StatusOK {
+				assert.Equal(t, 0, len(got))
+				return
+			}
+			require.Equal(t, 1, len(got))
+
+			received := got[0].ResourceSpans()
+			for i := 0; i < received.Len(); i++ {
+				attrs := received.At(i).Resource().Attributes()
+				tenant, contains := attrs.Get(splunk.TenantLabel)
+				require.True(t, contains)
+				assert.Equal(t, tt.wantTenant, tenant.StringVal())
+			}
+		})
+	}
+}
+
+func TestNewWithInvalidAuth(t *testing.T) {
+	config := &Config{
+		HTTPServerSettings: confighttp.HTTPServerSettings{
+			Endpoint: defaultEndpoint,
+		},
+		Auth: &splunk.AuthConfig{},
+	}
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	_, err := New(context.Background(), params, config, new(exportertest.SinkTraceExporter))
+	assert.Error(t, err)
+}
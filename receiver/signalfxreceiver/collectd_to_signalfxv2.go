@@ -0,0 +1,184 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/collectdreceiver"
+)
+
+// collectdDimensionPrefix is the prefix of the query parameters of /v1/collectd
+// requests that are added as dimensions to all their datapoints.
+const collectdDimensionPrefix = "sfxdim_"
+
+// collectdRecord is a record of the JSON sent by the collectd write_http
+// plugin.
+type collectdRecord struct {
+	Dsnames        []*string      `json:"dsnames"`
+	Dstypes        []*string      `json:"dstypes"`
+	Host           *string        `json:"host"`
+	Plugin         *string        `json:"plugin"`
+	PluginInstance *string        `json:"plugin_instance"`
+	Time           *float64       `json:"time"`
+	TypeS          *string        `json:"type"`
+	TypeInstance   *string        `json:"type_instance"`
+	Values         []*json.Number `json:"values"`
+	Message        *string        `json:"message"`
+	Severity       *string        `json:"severity"`
+}
+
+// collectdToSignalFxV2Datapoints converts the JSON body of /v1/collectd to
+// datapoints named and typed the same way as the collectd receiver does:
+// "type.type_instance", followed by the name of the value for multi-value
+// types, with the plugin, plugin instance and host as dimensions. Notifications
+// are skipped.
+func collectdToSignalFxV2Datapoints(body []byte, defaultDimensions map[string]string) ([]*sfxpb.DataPoint, error) {
+	var records []*collectdRecord
+	if err := newJSONDecoder(body).Decode(&records); err != nil {
+		return nil, err
+	}
+
+	var datapoints []*sfxpb.DataPoint
+	for _, record := range records {
+		if record == nil || record.isNotification() {
+			continue
+		}
+		for i, dsname := range record.Dsnames {
+			if i >= len(record.Dstypes) || i >= len(record.Values) || record.Values[i] == nil {
+				continue
+			}
+			datum, err := jsonToDatum(*record.Values[i])
+			if err != nil {
+				return nil, fmt.Errorf("collectd type %q: %w", stringValue(record.TypeS), err)
+			}
+
+			dimensions := make(map[string]string, len(defaultDimensions)+4)
+			for k, v := range defaultDimensions {
+				dimensions[k] = v
+			}
+			metric := record.metricName(i, dimensions)
+			addDimension(dimensions, "plugin", stringValue(record.Plugin))
+			addNamedDimension(dimensions, "plugin_instance", record.PluginInstance)
+			addNamedDimension(dimensions, "host", record.Host)
+			if len(record.Dsnames) == 1 {
+				addDimension(dimensions, "dsname", stringValue(dsname))
+			}
+
+			metricType := collectdToMetricType(stringValue(record.Dstypes[i]))
+			datapoints = append(datapoints, &sfxpb.DataPoint{
+				Metric:     metric,
+				Timestamp:  record.timestamp(),
+				Value:      datum,
+				MetricType: &metricType,
+				Dimensions: jsonToDimensions(dimensions),
+			})
+		}
+	}
+	return datapoints, nil
+}
+
+// collectdDimensions returns the dimensions of the query parameters prefixed
+// by sfxdim_, e.g. sfxdim_env=prod.
+func collectdDimensions(query map[string][]string) map[string]string {
+	dimensions := make(map[string]string)
+	for key, values := range query {
+		if strings.HasPrefix(key, collectdDimensionPrefix) && len(values) > 0 {
+			addDimension(dimensions, strings.TrimPrefix(key, collectdDimensionPrefix), values[0])
+		}
+	}
+	return dimensions
+}
+
+func (r *collectdRecord) isNotification() bool {
+	return r.Time != nil && r.Severity != nil && r.Message != nil
+}
+
+// timestamp returns the time of the record in milliseconds.
+func (r *collectdRecord) timestamp() int64 {
+	if r.Time == nil {
+		return 0
+	}
+	return int64(*r.Time * 1e3)
+}
+
+// metricName joins the type, the type instance, and the name of the value for
+// multi-value types. The dimensions found in the type instance, as in
+// "instance[k=v]", are added to dimensions.
+func (r *collectdRecord) metricName(index int, dimensions map[string]string) string {
+	var parts []string
+	if typeS := stringValue(r.TypeS); typeS != "" {
+		parts = append(parts, typeS)
+	}
+	if typeInstance := stringValue(r.TypeInstance); typeInstance != "" {
+		name, nameDimensions := collectdreceiver.LabelsFromName(&typeInstance)
+		if name != "" {
+			parts = append(parts, name)
+		}
+		for k, v := range nameDimensions {
+			if _, ok := dimensions[k]; !ok {
+				addDimension(dimensions, k, v)
+			}
+		}
+	}
+	if len(r.Dsnames) > 1 {
+		if dsname := stringValue(r.Dsnames[index]); dsname != "" {
+			parts = append(parts, dsname)
+		}
+	}
+	return strings.Join(parts, ".")
+}
+
+// addNamedDimension adds the name of val as the key dimension, along with the
+// dimensions found in it, as in "name[k=v]".
+func addNamedDimension(dimensions map[string]string, key string, val *string) {
+	if val == nil {
+		return
+	}
+	name, nameDimensions := collectdreceiver.LabelsFromName(val)
+	for k, v := range nameDimensions {
+		if _, ok := dimensions[k]; !ok {
+			addDimension(dimensions, k, v)
+		}
+	}
+	addDimension(dimensions, key, name)
+}
+
+func addDimension(dimensions map[string]string, key, value string) {
+	if value != "" {
+		dimensions[key] = value
+	}
+}
+
+// collectdToMetricType returns the type of the collectd data source type:
+// counters and derives are cumulative, gauges and absolutes are gauges.
+func collectdToMetricType(dstype string) sfxpb.MetricType {
+	switch dstype {
+	case "counter", "derive":
+		return sfxpb.MetricType_CUMULATIVE_COUNTER
+	}
+	return sfxpb.MetricType_GAUGE
+}
+
+func stringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
@@ -0,0 +1,121 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_collectdToSignalFxV2Datapoints(t *testing.T) {
+	tests := []struct {
+		name              string
+		body              string
+		defaultDimensions map[string]string
+		want              []*sfxpb.DataPoint
+		wantErr           bool
+	}{
+		{
+			name: "multi_value",
+			body: `[{
+				"dsnames": ["rx", "tx"], "dstypes": ["derive", "derive"], "values": [10, 20],
+				"host": "host0", "plugin": "interface", "plugin_instance": "eth0",
+				"time": 1415062577.494, "type": "if_octets", "type_instance": ""
+			}]`,
+			defaultDimensions: map[string]string{"env": "test"},
+			want: []*sfxpb.DataPoint{
+				{
+					Metric:     "if_octets.rx",
+					Timestamp:  1415062577494,
+					Value:      sfxpb.Datum{IntValue: int64Ptr(10)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+					Dimensions: []*sfxpb.Dimension{
+						{Key: "env", Value: "test"},
+						{Key: "host", Value: "host0"},
+						{Key: "plugin", Value: "interface"},
+						{Key: "plugin_instance", Value: "eth0"},
+					},
+				},
+				{
+					Metric:     "if_octets.tx",
+					Timestamp:  1415062577494,
+					Value:      sfxpb.Datum{IntValue: int64Ptr(20)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+					Dimensions: []*sfxpb.Dimension{
+						{Key: "env", Value: "test"},
+						{Key: "host", Value: "host0"},
+						{Key: "plugin", Value: "interface"},
+						{Key: "plugin_instance", Value: "eth0"},
+					},
+				},
+			},
+		},
+		{
+			name: "single_value_with_dimensions_in_names",
+			body: `[{
+				"dsnames": ["value"], "dstypes": ["gauge"], "values": [0.5],
+				"host": "host0[region=east]", "plugin": "memory", "plugin_instance": "",
+				"time": 1415062577, "type": "memory", "type_instance": "free[unit=bytes]"
+			}]`,
+			want: []*sfxpb.DataPoint{
+				{
+					Metric:     "memory.free",
+					Timestamp:  1415062577000,
+					Value:      sfxpb.Datum{DoubleValue: float64Ptr(0.5)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+					Dimensions: []*sfxpb.Dimension{
+						{Key: "dsname", Value: "value"},
+						{Key: "host", Value: "host0"},
+						{Key: "plugin", Value: "memory"},
+						{Key: "region", Value: "east"},
+						{Key: "unit", Value: "bytes"},
+					},
+				},
+			},
+		},
+		{
+			name: "notification",
+			body: `[{"host": "host0", "message": "down", "severity": "FAILURE", "time": 1415062577}]`,
+		},
+		{
+			name:    "not_an_array",
+			body:    `{"dsnames": ["value"]}`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := collectdToSignalFxV2Datapoints([]byte(tt.body), tt.defaultDimensions)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_collectdDimensions(t *testing.T) {
+	got := collectdDimensions(map[string][]string{
+		"sfxdim_env":  {"prod", "test"},
+		"sfxdim_zone": {""},
+		"other":       {"v"},
+	})
+	assert.Equal(t, map[string]string{"env": "prod"}, got)
+}
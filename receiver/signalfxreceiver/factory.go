@@ -44,7 +44,8 @@ func NewFactory() component.ReceiverFactory {
 		typeStr,
 		createDefaultConfig,
 		receiverhelper.WithMetrics(createMetricsReceiver),
-		receiverhelper.WithLogs(createLogsReceiver))
+		receiverhelper.WithLogs(createLogsReceiver),
+		receiverhelper.WithTraces(createTraceReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -143,5 +144,32 @@ func createLogsReceiver(
 	return r, nil
 }
 
+// createTraceReceiver creates a trace receiver based on provided config.
+func createTraceReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	rCfg := cfg.(*Config)
+
+	err := rCfg.validate()
+	if err != nil {
+		return nil, err
+	}
+
+	receiverLock.Lock()
+	r := receivers[rCfg]
+	if r == nil {
+		r = newReceiver(params.Logger, *rCfg)
+		receivers[rCfg] = r
+	}
+	receiverLock.Unlock()
+
+	r.RegisterTraceConsumer(consumer)
+
+	return r, nil
+}
+
 var receiverLock sync.Mutex
 var receivers = map[*Config]*sfxReceiver{}
@@ -23,12 +23,14 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 	"unsafe"
 
 	resourcepb "github.com/census-instrumentation/opencensus-proto/gen-go/resource/v1"
 	"github.com/gorilla/mux"
+	zipkinmodel "github.com/openzipkin/zipkin-go/model"
 	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
 	"go.opencensus.io/trace"
 	"go.opentelemetry.io/collector/component"
@@ -38,6 +40,7 @@ import (
 	"go.opentelemetry.io/collector/obsreport"
 	"go.opentelemetry.io/collector/translator/conventions"
 	"go.opentelemetry.io/collector/translator/internaldata"
+	"go.opentelemetry.io/collector/translator/trace/zipkin"
 	"go.uber.org/zap"
 
 	"github.com/open-telemetry/opentelemetry-collector-contrib/internal/common/splunk"
@@ -48,7 +51,8 @@ const (
 
 	responseOK                 = "OK"
 	responseInvalidMethod      = "Only \"POST\" method is supported"
-	responseInvalidContentType = "\"Content-Type\" must be \"application/x-protobuf\""
+	responseInvalidContentType = "\"Content-Type\" must be \"application/x-protobuf\" or \"application/json\""
+	responseInvalidJSONType    = "\"Content-Type\" must be \"application/json\""
 	responseInvalidEncoding    = "\"Content-Encoding\" must be \"gzip\" or empty"
 	responseErrGzipReader      = "Error on gzip body"
 	responseErrReadBody        = "Failed to read message body"
@@ -57,6 +61,7 @@ const (
 
 	// Centralizing some HTTP and related string constants.
 	protobufContentType       = "application/x-protobuf"
+	jsonContentType           = "application/json"
 	gzipEncoding              = "gzip"
 	httpContentTypeHeader     = "Content-Type"
 	httpContentEncodingHeader = "Content-Encoding"
@@ -65,10 +70,12 @@ const (
 var (
 	errNilNextConsumer = errors.New("nil nextConsumer")
 	errEmptyEndpoint   = errors.New("empty endpoint")
+	errJSONOnly        = errors.New("only JSON is supported")
 
 	okRespBody               = initJSONResponse(responseOK)
 	invalidMethodRespBody    = initJSONResponse(responseInvalidMethod)
 	invalidContentRespBody   = initJSONResponse(responseInvalidContentType)
+	invalidJSONTypeRespBody  = initJSONResponse(responseInvalidJSONType)
 	invalidEncodingRespBody  = initJSONResponse(responseInvalidEncoding)
 	errGzipReaderRespBody    = initJSONResponse(responseErrGzipReader)
 	errReadBodyRespBody      = initJSONResponse(responseErrReadBody)
@@ -83,6 +90,7 @@ type sfxReceiver struct {
 	config          *Config
 	metricsConsumer consumer.MetricsConsumer
 	logsConsumer    consumer.LogsConsumer
+	traceConsumer   consumer.TraceConsumer
 	server          *http.Server
 
 	startOnce sync.Once
@@ -90,6 +98,7 @@ type sfxReceiver struct {
 }
 
 var _ component.MetricsReceiver = (*sfxReceiver)(nil)
+var _ component.TraceReceiver = (*sfxReceiver)(nil)
 
 // New creates the SignalFx receiver with the given configuration.
 func newReceiver(
@@ -118,6 +127,13 @@ func (r *sfxReceiver) RegisterLogsConsumer(lc consumer.LogsConsumer) {
 	r.logsConsumer = lc
 }
 
+func (r *sfxReceiver) RegisterTraceConsumer(tc consumer.TraceConsumer) {
+	r.Lock()
+	defer r.Unlock()
+
+	r.traceConsumer = tc
+}
+
 // StartMetricsReception tells the receiver to start its processing.
 // By convention the consumer of the received data is set when the receiver
 // instance is created.
@@ -125,7 +141,7 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 	r.Lock()
 	defer r.Unlock()
 
-	if r.metricsConsumer == nil && r.logsConsumer == nil {
+	if r.metricsConsumer == nil && r.logsConsumer == nil && r.traceConsumer == nil {
 		return errNilNextConsumer
 	}
 
@@ -141,9 +157,19 @@ func (r *sfxReceiver) Start(_ context.Context, host component.Host) error {
 			return
 		}
 
+		// Only the endpoints of the registered consumers are served.
 		mx := mux.NewRouter()
-		mx.HandleFunc("/v2/datapoint", r.handleDatapointReq)
-		mx.HandleFunc("/v2/event", r.handleEventReq)
+		if r.metricsConsumer != nil {
+			mx.HandleFunc("/v2/datapoint", r.handleDatapointReq)
+			mx.HandleFunc("/v1/datapoint", r.handleDatapointV1Req)
+			mx.HandleFunc("/v1/collectd", r.handleCollectdReq)
+		}
+		if r.logsConsumer != nil {
+			mx.HandleFunc("/v2/event", r.handleEventReq)
+		}
+		if r.traceConsumer != nil {
+			mx.HandleFunc("/v2/trace", r.handleTraceReq)
+		}
 
 		r.server = r.config.HTTPServerSettings.ToServer(mx)
 
@@ -175,21 +201,29 @@ func (r *sfxReceiver) Shutdown(context.Context) error {
 	return err
 }
 
-func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, bool) {
+// readBody returns the body of the request, along with its content type,
+// either protobuf or JSON.
+func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, req *http.Request) ([]byte, string, bool) {
 	if req.Method != http.MethodPost {
 		r.failRequest(ctx, resp, http.StatusBadRequest, invalidMethodRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
-	if req.Header.Get(httpContentTypeHeader) != protobufContentType {
+	// Ignores the parameters of the media type, e.g. the charset.
+	contentType := req.Header.Get(httpContentTypeHeader)
+	if i := strings.IndexByte(contentType, ';'); i != -1 {
+		contentType = contentType[:i]
+	}
+	contentType = strings.TrimSpace(contentType)
+	if contentType != protobufContentType && contentType != jsonContentType {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidContentRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
 	encoding := req.Header.Get(httpContentEncodingHeader)
 	if encoding != "" && encoding != gzipEncoding {
 		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidEncodingRespBody, nil)
-		return nil, false
+		return nil, "", false
 	}
 
 	bodyReader := req.Body
@@ -198,16 +232,16 @@ func (r *sfxReceiver) readBody(ctx context.Context, resp http.ResponseWriter, re
 		bodyReader, err = gzip.NewReader(bodyReader)
 		if err != nil {
 			r.failRequest(ctx, resp, http.StatusBadRequest, errGzipReaderRespBody, err)
-			return nil, false
+			return nil, "", false
 		}
 	}
 
 	body, err := ioutil.ReadAll(bodyReader)
 	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errReadBodyRespBody, err)
-		return nil, false
+		return nil, "", false
 	}
-	return body, true
+	return body, contentType, true
 }
 
 func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWriter, err error) {
@@ -221,6 +255,49 @@ func (r *sfxReceiver) writeResponse(ctx context.Context, resp http.ResponseWrite
 }
 
 func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Request) {
+	r.handleDatapoints(resp, req, func(body []byte, contentType string) ([]*sfxpb.DataPoint, error) {
+		if contentType == jsonContentType {
+			return jsonToSignalFxV2Datapoints(body)
+		}
+		msg := &sfxpb.DataPointUploadMessage{}
+		if err := msg.Unmarshal(body); err != nil {
+			return nil, err
+		}
+		return msg.Datapoints, nil
+	})
+}
+
+func (r *sfxReceiver) handleDatapointV1Req(resp http.ResponseWriter, req *http.Request) {
+	r.handleDatapoints(resp, req, func(body []byte, contentType string) ([]*sfxpb.DataPoint, error) {
+		decode := protobufToSignalFxV1Datapoints
+		if contentType == jsonContentType {
+			decode = jsonToSignalFxV1Datapoints
+		}
+		datapoints, err := decode(body)
+		if err != nil {
+			return nil, err
+		}
+		signalFxV1ToV2Datapoints(datapoints, time.Now())
+		return datapoints, nil
+	})
+}
+
+func (r *sfxReceiver) handleCollectdReq(resp http.ResponseWriter, req *http.Request) {
+	r.handleDatapoints(resp, req, func(body []byte, contentType string) ([]*sfxpb.DataPoint, error) {
+		if contentType != jsonContentType {
+			return nil, errJSONOnly
+		}
+		return collectdToSignalFxV2Datapoints(body, collectdDimensions(req.URL.Query()))
+	})
+}
+
+// handleDatapoints handles the requests of all the datapoint endpoints, which
+// only differ by how their body is decoded to SignalFx datapoints.
+func (r *sfxReceiver) handleDatapoints(
+	resp http.ResponseWriter,
+	req *http.Request,
+	decode func(body []byte, contentType string) ([]*sfxpb.DataPoint, error),
+) {
 	transport := "http"
 	if r.config.TLSSetting != nil {
 		transport = "https"
@@ -229,24 +306,28 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, contentType, ok := r.readBody(ctx, resp, req)
 	if !ok {
 		return
 	}
 
-	msg := &sfxpb.DataPointUploadMessage{}
-	if err := msg.Unmarshal(body); err != nil {
+	datapoints, err := decode(body, contentType)
+	if err == errJSONOnly {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidJSONTypeRespBody, err)
+		return
+	}
+	if err != nil {
 		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
 		return
 	}
 
-	if len(msg.Datapoints) == 0 {
+	if len(datapoints) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
 		resp.Write(okRespBody)
 		return
 	}
 
-	md, _ := signalFxV2ToMetricsData(r.logger, msg.Datapoints)
+	md, _ := signalFxV2ToMetricsData(r.logger, datapoints)
 
 	if r.config.AccessTokenPassthrough {
 		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
@@ -260,12 +341,12 @@ func (r *sfxReceiver) handleDatapointReq(resp http.ResponseWriter, req *http.Req
 		}
 	}
 
-	err := r.metricsConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
+	err = r.metricsConsumer.ConsumeMetrics(ctx, internaldata.OCToMetrics(md))
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
-		len(msg.Datapoints),
-		len(msg.Datapoints),
+		len(datapoints),
+		len(datapoints),
 		err)
 
 	r.writeResponse(ctx, resp, err)
@@ -280,24 +361,34 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
 	ctx = obsreport.StartMetricsReceiveOp(ctx, r.config.Name(), transport)
 
-	body, ok := r.readBody(ctx, resp, req)
+	body, contentType, ok := r.readBody(ctx, resp, req)
 	if !ok {
 		return
 	}
 
-	msg := &sfxpb.EventUploadMessage{}
-	if err := msg.Unmarshal(body); err != nil {
-		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
-		return
+	var events []*sfxpb.Event
+	if contentType == jsonContentType {
+		var err error
+		if events, err = jsonToSignalFxV2Events(body); err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+			return
+		}
+	} else {
+		msg := &sfxpb.EventUploadMessage{}
+		if err := msg.Unmarshal(body); err != nil {
+			r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+			return
+		}
+		events = msg.Events
 	}
 
-	if len(msg.Events) == 0 {
+	if len(events) == 0 {
 		obsreport.EndMetricsReceiveOp(ctx, typeStr, 0, 0, nil)
 		resp.Write(okRespBody)
 		return
 	}
 
-	logSlice := signalFxV2EventsToLogRecords(r.logger, msg.Events)
+	logSlice := signalFxV2EventsToLogRecords(r.logger, events)
 
 	ld := pdata.NewLogs()
 	rls := ld.ResourceLogs()
@@ -322,13 +413,74 @@ func (r *sfxReceiver) handleEventReq(resp http.ResponseWriter, req *http.Request
 	obsreport.EndMetricsReceiveOp(
 		ctx,
 		typeStr,
-		len(msg.Events),
-		len(msg.Events),
+		len(events),
+		len(events),
 		err)
 
 	r.writeResponse(ctx, resp, err)
 }
 
+func (r *sfxReceiver) handleTraceReq(resp http.ResponseWriter, req *http.Request) {
+	transport := "http"
+	if r.config.TLSSetting != nil {
+		transport = "https"
+	}
+
+	ctx := obsreport.ReceiverContext(req.Context(), r.config.Name(), transport, r.config.Name())
+	ctx = obsreport.StartTraceDataReceiveOp(ctx, r.config.Name(), transport)
+
+	body, contentType, ok := r.readBody(ctx, resp, req)
+	if !ok {
+		return
+	}
+
+	// Spans are only accepted in the Zipkin v2 JSON format.
+	if contentType != jsonContentType {
+		r.failRequest(ctx, resp, http.StatusUnsupportedMediaType, invalidJSONTypeRespBody, errJSONOnly)
+		return
+	}
+
+	var zipkinSpans []*zipkinmodel.SpanModel
+	if err := json.Unmarshal(body, &zipkinSpans); err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	td, err := zipkin.V2SpansToInternalTraces(zipkinSpans)
+	if err != nil {
+		r.failRequest(ctx, resp, http.StatusBadRequest, errUnmarshalBodyRespBody, err)
+		return
+	}
+
+	if td.SpanCount() == 0 {
+		obsreport.EndTraceDataReceiveOp(ctx, typeStr, 0, nil)
+		resp.Write(okRespBody)
+		return
+	}
+
+	if r.config.AccessTokenPassthrough {
+		if accessToken := req.Header.Get(splunk.SFxAccessTokenHeader); accessToken != "" {
+			rSpans := td.ResourceSpans()
+			for i := 0; i < rSpans.Len(); i++ {
+				rSpan := rSpans.At(i)
+				if !rSpan.IsNil() {
+					resource := rSpan.Resource()
+					if resource.IsNil() {
+						resource.InitEmpty()
+					}
+					resource.Attributes().UpsertString(splunk.SFxAccessTokenLabel, accessToken)
+				}
+			}
+		}
+	}
+
+	numSpans := td.SpanCount()
+	err = r.traceConsumer.ConsumeTraces(ctx, td)
+	obsreport.EndTraceDataReceiveOp(ctx, typeStr, numSpans, err)
+
+	r.writeResponse(ctx, resp, err)
+}
+
 func (r *sfxReceiver) failRequest(
 	ctx context.Context,
 	resp http.ResponseWriter,
@@ -20,6 +20,7 @@ import (
 	"context"
 	"crypto/tls"
 	"crypto/x509"
+	"encoding/binary"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -269,6 +270,32 @@ func Test_sfxReceiver_handleReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				msg := `{"gauge": [{"metric": "single", "value": 13, "dimensions": {"k0": "v0"}}]}`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(msg)))
+				req.Header.Set("Content-Type", "application/json; charset=utf-8")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				msg := `{"histogram": [{"metric": "single", "value": 13}]}`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(msg)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
 		{
 			name: "msg_accepted_gzipped",
 			req: func() *http.Request {
@@ -426,6 +453,32 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 				assert.Equal(t, responseOK, body)
 			},
 		},
+		{
+			name: "json_msg_accepted",
+			req: func() *http.Request {
+				msg := `[{"category": "USER_DEFINED", "eventType": "single", "properties": {"a": "b"}}]`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(msg)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "bad_json_in_body",
+			req: func() *http.Request {
+				msg := `[{"category": "NOT_A_CATEGORY", "eventType": "single"}]`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(msg)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
 		{
 			name: "msg_accepted_gzipped",
 			req: func() *http.Request {
@@ -487,6 +540,245 @@ func Test_sfxReceiver_handleEventReq(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_handleV1Req(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	v1Datapoint := &sfxpb.DataPoint{
+		Source: "host0",
+		Metric: "single",
+		Value: sfxpb.Datum{
+			IntValue: int64Ptr(13),
+		},
+	}
+	v1DatapointBytes, err := v1Datapoint.Marshal()
+	require.NoError(t, err)
+	v1Msg := make([]byte, binary.MaxVarintLen64)
+	v1Msg = append(v1Msg[:binary.PutUvarint(v1Msg, uint64(len(v1DatapointBytes)))], v1DatapointBytes...)
+
+	tests := []struct {
+		name        string
+		collectd    bool
+		req         *http.Request
+		wantStatus  int
+		wantBody    string
+		wantMetrics []string
+	}{
+		{
+			name: "protobuf_datapoints",
+			req: func() *http.Request {
+				body := append(append([]byte{}, v1Msg...), v1Msg...)
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(body))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantMetrics: []string{"single", "single"},
+		},
+		{
+			name: "bad_protobuf_length",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(v1Msg[:len(v1Msg)-1]))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			wantStatus: http.StatusBadRequest,
+			wantBody:   responseErrUnmarshalBody,
+		},
+		{
+			name: "json_datapoints",
+			req: func() *http.Request {
+				body := `{"source": "host0", "metric": "single", "value": 13}{"source": "host1", "metric": "other", "value": 1.5}`
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(body)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantMetrics: []string{"single", "other"},
+		},
+		{
+			name:     "collectd_records",
+			collectd: true,
+			req: func() *http.Request {
+				body := `[{"dsnames": ["rx", "tx"], "dstypes": ["derive", "derive"], "values": [1, 2], "host": "host0",
+					"plugin": "interface", "plugin_instance": "eth0", "time": 1415062577.494, "type": "if_octets"}]`
+				req := httptest.NewRequest("POST", "http://localhost?sfxdim_env=test", bytes.NewReader([]byte(body)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			wantStatus:  http.StatusAccepted,
+			wantBody:    responseOK,
+			wantMetrics: []string{"if_octets.rx", "if_octets.tx"},
+		},
+		{
+			name:     "collectd_protobuf",
+			collectd: true,
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader(v1Msg))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			wantStatus: http.StatusUnsupportedMediaType,
+			wantBody:   responseInvalidJSONType,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkMetricsExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterMetricsConsumer(sink)
+
+			w := httptest.NewRecorder()
+			if tt.collectd {
+				rcv.handleCollectdReq(w, tt.req)
+			} else {
+				rcv.handleDatapointV1Req(w, tt.req)
+			}
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+			assert.Equal(t, tt.wantStatus, resp.StatusCode)
+			assert.Equal(t, tt.wantBody, bodyStr)
+
+			if tt.wantMetrics == nil {
+				assert.Empty(t, sink.AllMetrics())
+				return
+			}
+			mds := sink.AllMetrics()
+			require.Len(t, mds, 1)
+			got := internaldata.MetricsToOC(mds[0])
+			require.Len(t, got, 1)
+			var gotMetrics []string
+			for _, metric := range got[0].Metrics {
+				gotMetrics = append(gotMetrics, metric.MetricDescriptor.Name)
+			}
+			assert.Equal(t, tt.wantMetrics, gotMetrics)
+		})
+	}
+}
+
+func Test_sfxReceiver_handleTraceReq(t *testing.T) {
+	config := createDefaultConfig().(*Config)
+	config.Endpoint = "localhost:0" // Actually not creating the endpoint
+
+	zipkinSpans := `[{
+		"traceId": "4d1e00c0db9010db86154a4ba6e91385",
+		"parentId": "86154a4ba6e91385",
+		"id": "4d1e00c0db9010db",
+		"kind": "CLIENT",
+		"name": "get",
+		"timestamp": 1472470996199000,
+		"duration": 207000,
+		"localEndpoint": {"serviceName": "frontend", "ipv4": "127.0.0.1"},
+		"tags": {"http.path": "/api"}
+	}]`
+
+	tests := []struct {
+		name           string
+		req            *http.Request
+		wantSpans      int
+		assertResponse func(t *testing.T, status int, body string)
+	}{
+		{
+			name: "protobuf_content_type",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(zipkinSpans)))
+				req.Header.Set("Content-Type", "application/x-protobuf")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusUnsupportedMediaType, status)
+				assert.Equal(t, responseInvalidJSONType, body)
+			},
+		},
+		{
+			name: "bad_data_in_body",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(`{"traceId": 1}`)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusBadRequest, status)
+				assert.Equal(t, responseErrUnmarshalBody, body)
+			},
+		},
+		{
+			name: "empty_array",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(`[]`)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusOK, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "msg_accepted",
+			req: func() *http.Request {
+				req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(zipkinSpans)))
+				req.Header.Set("Content-Type", "application/json")
+				return req
+			}(),
+			wantSpans: 1,
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+		{
+			name: "msg_accepted_gzipped",
+			req: func() *http.Request {
+				var buf bytes.Buffer
+				gzipWriter := gzip.NewWriter(&buf)
+				_, err := gzipWriter.Write([]byte(zipkinSpans))
+				require.NoError(t, err)
+				require.NoError(t, gzipWriter.Close())
+
+				req := httptest.NewRequest("POST", "http://localhost", &buf)
+				req.Header.Set("Content-Type", "application/json")
+				req.Header.Set("Content-Encoding", "gzip")
+				return req
+			}(),
+			wantSpans: 1,
+			assertResponse: func(t *testing.T, status int, body string) {
+				assert.Equal(t, http.StatusAccepted, status)
+				assert.Equal(t, responseOK, body)
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sink := new(exportertest.SinkTraceExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterTraceConsumer(sink)
+
+			w := httptest.NewRecorder()
+			rcv.handleTraceReq(w, tt.req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+
+			tt.assertResponse(t, resp.StatusCode, bodyStr)
+			assert.Equal(t, tt.wantSpans, sink.SpansCount())
+		})
+	}
+}
+
 func Test_sfxReceiver_TLS(t *testing.T) {
 	addr := testutil.GetAvailableLocalAddress(t)
 	cfg := createDefaultConfig().(*Config)
@@ -748,6 +1040,85 @@ func Test_sfxReceiver_EventAccessTokenPassthrough(t *testing.T) {
 	}
 }
 
+func Test_sfxReceiver_TraceAccessTokenPassthrough(t *testing.T) {
+	tests := []struct {
+		name        string
+		passthrough bool
+		token       string
+	}{
+		{
+			name:        "No token provided and passthrough false",
+			passthrough: false,
+			token:       "",
+		},
+		{
+			name:        "No token provided and passthrough true",
+			passthrough: true,
+			token:       "",
+		},
+		{
+			name:        "token provided and passthrough false",
+			passthrough: false,
+			token:       "myToken",
+		},
+		{
+			name:        "token provided and passthrough true",
+			passthrough: true,
+			token:       "myToken",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := createDefaultConfig().(*Config)
+			config.Endpoint = "localhost:0"
+			config.AccessTokenPassthrough = tt.passthrough
+
+			sink := new(exportertest.SinkTraceExporter)
+			rcv := newReceiver(zap.NewNop(), *config)
+			rcv.RegisterTraceConsumer(sink)
+
+			zipkinSpans := `[{"traceId": "4d1e00c0db9010db86154a4ba6e91385", "id": "4d1e00c0db9010db", "name": "get",
+				"timestamp": 1472470996199000, "duration": 207000, "localEndpoint": {"serviceName": "frontend"}}]`
+			req := httptest.NewRequest("POST", "http://localhost", bytes.NewReader([]byte(zipkinSpans)))
+			req.Header.Set("Content-Type", "application/json")
+			if tt.token != "" {
+				req.Header.Set("x-sf-token", tt.token)
+			}
+
+			w := httptest.NewRecorder()
+			rcv.handleTraceReq(w, req)
+
+			resp := w.Result()
+			respBytes, err := ioutil.ReadAll(resp.Body)
+			assert.NoError(t, err)
+
+			var bodyStr string
+			assert.NoError(t, json.Unmarshal(respBytes, &bodyStr))
+
+			assert.Equal(t, http.StatusAccepted, resp.StatusCode)
+			assert.Equal(t, responseOK, bodyStr)
+
+			tds := sink.AllTraces()
+			require.Len(t, tds, 1)
+			rss := tds[0].ResourceSpans()
+			require.Equal(t, 1, rss.Len())
+			attrs := rss.At(0).Resource().Attributes()
+			serviceName, ok := attrs.Get("service.name")
+			require.True(t, ok)
+			assert.Equal(t, "frontend", serviceName.StringVal())
+
+			tokenLabel, ok := attrs.Get("com.splunk.signalfx.access_token")
+			if tt.passthrough && tt.token != "" {
+				require.True(t, ok)
+				assert.Equal(t, tt.token, tokenLabel.StringVal())
+			} else {
+				assert.False(t, ok)
+			}
+		})
+	}
+}
+
 func buildSFxDatapointMsg(time int64, value int64, dimensions uint) *sfxpb.DataPointUploadMessage {
 	return &sfxpb.DataPointUploadMessage{
 		Datapoints: []*sfxpb.DataPoint{
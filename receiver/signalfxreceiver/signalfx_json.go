@@ -0,0 +1,245 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// The metric types of the JSON datapoint format, in the order their
+// datapoints are converted.
+var jsonMetricTypes = []struct {
+	name       string
+	metricType sfxpb.MetricType
+}{
+	{name: "gauge", metricType: sfxpb.MetricType_GAUGE},
+	{name: "counter", metricType: sfxpb.MetricType_COUNTER},
+	{name: "cumulative_counter", metricType: sfxpb.MetricType_CUMULATIVE_COUNTER},
+}
+
+// jsonDatapointV2 is a datapoint of the JSON body of /v2/datapoint, where
+// datapoints are grouped by metric type, e.g.:
+// {"gauge": [{"metric": "cpu.utilization", "value": 3, "dimensions": {"host": "h0"}}]}
+type jsonDatapointV2 struct {
+	Metric     string            `json:"metric"`
+	Timestamp  int64             `json:"timestamp"`
+	Value      interface{}       `json:"value"`
+	Dimensions map[string]string `json:"dimensions"`
+}
+
+// jsonDatapointV1 is a datapoint of the JSON body of /v1/datapoint, a stream of
+// objects without type, timestamp nor dimensions other than the source, e.g.:
+// {"source": "h0", "metric": "cpu.utilization", "value": 3}
+type jsonDatapointV1 struct {
+	Source string      `json:"source"`
+	Metric string      `json:"metric"`
+	Value  interface{} `json:"value"`
+}
+
+// jsonEventV2 is an event of the JSON array of /v2/event.
+type jsonEventV2 struct {
+	Category   *string                `json:"category"`
+	EventType  string                 `json:"eventType"`
+	Dimensions map[string]string      `json:"dimensions"`
+	Properties map[string]interface{} `json:"properties"`
+	Timestamp  int64                  `json:"timestamp"`
+}
+
+func newJSONDecoder(body []byte) *json.Decoder {
+	decoder := json.NewDecoder(bytes.NewReader(body))
+	// Keeps integers apart from floats.
+	decoder.UseNumber()
+	return decoder
+}
+
+// jsonToSignalFxV2Datapoints decodes the JSON body of /v2/datapoint.
+func jsonToSignalFxV2Datapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var msg map[string][]*jsonDatapointV2
+	if err := newJSONDecoder(body).Decode(&msg); err != nil {
+		return nil, err
+	}
+
+	for name := range msg {
+		if !isJSONMetricType(name) {
+			return nil, fmt.Errorf("unknown metric type %q", name)
+		}
+	}
+
+	var datapoints []*sfxpb.DataPoint
+	for _, mt := range jsonMetricTypes {
+		for _, jsonDatapoint := range msg[mt.name] {
+			if jsonDatapoint == nil {
+				continue
+			}
+			datum, err := jsonToDatum(jsonDatapoint.Value)
+			if err != nil {
+				return nil, fmt.Errorf("metric %q: %w", jsonDatapoint.Metric, err)
+			}
+			metricType := mt.metricType
+			datapoints = append(datapoints, &sfxpb.DataPoint{
+				Metric:     jsonDatapoint.Metric,
+				Timestamp:  jsonDatapoint.Timestamp,
+				Value:      datum,
+				MetricType: &metricType,
+				Dimensions: jsonToDimensions(jsonDatapoint.Dimensions),
+			})
+		}
+	}
+	return datapoints, nil
+}
+
+// jsonToSignalFxV1Datapoints decodes the JSON body of /v1/datapoint, which
+// still has to be completed by signalFxV1ToV2Datapoints.
+func jsonToSignalFxV1Datapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var datapoints []*sfxpb.DataPoint
+	decoder := newJSONDecoder(body)
+	for {
+		var jsonDatapoint jsonDatapointV1
+		if err := decoder.Decode(&jsonDatapoint); err != nil {
+			if err == io.EOF {
+				return datapoints, nil
+			}
+			return nil, err
+		}
+		datum, err := jsonToDatum(jsonDatapoint.Value)
+		if err != nil {
+			return nil, fmt.Errorf("metric %q: %w", jsonDatapoint.Metric, err)
+		}
+		datapoints = append(datapoints, &sfxpb.DataPoint{
+			Source: jsonDatapoint.Source,
+			Metric: jsonDatapoint.Metric,
+			Value:  datum,
+		})
+	}
+}
+
+// jsonToSignalFxV2Events decodes the JSON body of /v2/event.
+func jsonToSignalFxV2Events(body []byte) ([]*sfxpb.Event, error) {
+	var msg []*jsonEventV2
+	if err := newJSONDecoder(body).Decode(&msg); err != nil {
+		return nil, err
+	}
+
+	events := make([]*sfxpb.Event, 0, len(msg))
+	for _, jsonEvent := range msg {
+		if jsonEvent == nil {
+			continue
+		}
+		event := &sfxpb.Event{
+			EventType:  jsonEvent.EventType,
+			Dimensions: jsonToDimensions(jsonEvent.Dimensions),
+			Timestamp:  jsonEvent.Timestamp,
+		}
+		if jsonEvent.Category != nil {
+			category, ok := sfxpb.EventCategory_value[*jsonEvent.Category]
+			if !ok {
+				return nil, fmt.Errorf("event %q: unknown category %q", jsonEvent.EventType, *jsonEvent.Category)
+			}
+			event.Category = (*sfxpb.EventCategory)(&category)
+		}
+		keys := make([]string, 0, len(jsonEvent.Properties))
+		for key := range jsonEvent.Properties {
+			keys = append(keys, key)
+		}
+		sort.Strings(keys)
+		for _, key := range keys {
+			value, err := jsonToPropertyValue(jsonEvent.Properties[key])
+			if err != nil {
+				return nil, fmt.Errorf("event %q: property %q: %w", jsonEvent.EventType, key, err)
+			}
+			event.Properties = append(event.Properties, &sfxpb.Property{
+				Key:   key,
+				Value: value,
+			})
+		}
+		events = append(events, event)
+	}
+	return events, nil
+}
+
+func isJSONMetricType(name string) bool {
+	for _, mt := range jsonMetricTypes {
+		if mt.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func jsonToDatum(value interface{}) (sfxpb.Datum, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return sfxpb.Datum{IntValue: &i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return sfxpb.Datum{}, err
+		}
+		return sfxpb.Datum{DoubleValue: &f}, nil
+	case string:
+		return sfxpb.Datum{StrValue: &v}, nil
+	case nil:
+		return sfxpb.Datum{}, errSFxNoDatumValue
+	}
+	return sfxpb.Datum{}, fmt.Errorf("unsupported value type %T", value)
+}
+
+func jsonToPropertyValue(value interface{}) (*sfxpb.PropertyValue, error) {
+	switch v := value.(type) {
+	case json.Number:
+		if i, err := v.Int64(); err == nil {
+			return &sfxpb.PropertyValue{IntValue: &i}, nil
+		}
+		f, err := v.Float64()
+		if err != nil {
+			return nil, err
+		}
+		return &sfxpb.PropertyValue{DoubleValue: &f}, nil
+	case string:
+		return &sfxpb.PropertyValue{StrValue: &v}, nil
+	case bool:
+		return &sfxpb.PropertyValue{BoolValue: &v}, nil
+	}
+	return nil, fmt.Errorf("unsupported value type %T", value)
+}
+
+// jsonToDimensions converts the dimensions map of the JSON formats, sorted
+// by key so that the converted label keys are reproducible.
+func jsonToDimensions(dimensions map[string]string) []*sfxpb.Dimension {
+	if len(dimensions) == 0 {
+		return nil
+	}
+	keys := make([]string, 0, len(dimensions))
+	for key := range dimensions {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+
+	sfxDimensions := make([]*sfxpb.Dimension, 0, len(dimensions))
+	for _, key := range keys {
+		sfxDimensions = append(sfxDimensions, &sfxpb.Dimension{
+			Key:   key,
+			Value: dimensions[key],
+		})
+	}
+	return sfxDimensions
+}
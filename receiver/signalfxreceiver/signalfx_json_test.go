@@ -0,0 +1,170 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"testing"
+	"time"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func Test_jsonToSignalFxV2Datapoints(t *testing.T) {
+	tests := []struct {
+		name    string
+		body    string
+		want    []*sfxpb.DataPoint
+		wantErr bool
+	}{
+		{
+			name: "all_types",
+			body: `{
+				"cumulative_counter": [{"metric": "c", "value": "7", "timestamp": 1000}],
+				"counter": [{"metric": "b", "value": 1.5, "timestamp": 1000}],
+				"gauge": [{"metric": "a", "value": 3, "timestamp": 1000, "dimensions": {"k1": "v1", "k0": "v0"}}]
+			}`,
+			want: []*sfxpb.DataPoint{
+				{
+					Metric:     "a",
+					Timestamp:  1000,
+					Value:      sfxpb.Datum{IntValue: int64Ptr(3)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+					Dimensions: []*sfxpb.Dimension{
+						{Key: "k0", Value: "v0"},
+						{Key: "k1", Value: "v1"},
+					},
+				},
+				{
+					Metric:     "b",
+					Timestamp:  1000,
+					Value:      sfxpb.Datum{DoubleValue: float64Ptr(1.5)},
+					MetricType: sfxTypePtr(sfxpb.MetricType_COUNTER),
+				},
+				{
+					Metric:     "c",
+					Timestamp:  1000,
+					Value:      sfxpb.Datum{StrValue: strPtr("7")},
+					MetricType: sfxTypePtr(sfxpb.MetricType_CUMULATIVE_COUNTER),
+				},
+			},
+		},
+		{
+			name: "empty",
+			body: `{}`,
+		},
+		{
+			name:    "unknown_type",
+			body:    `{"histogram": [{"metric": "a", "value": 3}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "missing_value",
+			body:    `{"gauge": [{"metric": "a"}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "object_value",
+			body:    `{"gauge": [{"metric": "a", "value": {}}]}`,
+			wantErr: true,
+		},
+		{
+			name:    "not_json",
+			body:    `gauge`,
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jsonToSignalFxV2Datapoints([]byte(tt.body))
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_jsonToSignalFxV1Datapoints(t *testing.T) {
+	body := `{"source": "host0", "metric": "a", "value": 3}
+		{"metric": "b", "value": 1.5}`
+	datapoints, err := jsonToSignalFxV1Datapoints([]byte(body))
+	require.NoError(t, err)
+
+	now := time.Unix(1600000000, 0)
+	signalFxV1ToV2Datapoints(datapoints, now)
+	assert.Equal(t, []*sfxpb.DataPoint{
+		{
+			Metric:     "a",
+			Timestamp:  1600000000000,
+			Value:      sfxpb.Datum{IntValue: int64Ptr(3)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+			Dimensions: []*sfxpb.Dimension{{Key: "sf_source", Value: "host0"}},
+		},
+		{
+			Metric:     "b",
+			Timestamp:  1600000000000,
+			Value:      sfxpb.Datum{DoubleValue: float64Ptr(1.5)},
+			MetricType: sfxTypePtr(sfxpb.MetricType_GAUGE),
+		},
+	}, datapoints)
+
+	_, err = jsonToSignalFxV1Datapoints([]byte(`{"metric": "a", "value": 3}{"metric"`))
+	assert.Error(t, err)
+}
+
+func Test_jsonToSignalFxV2Events(t *testing.T) {
+	body := `[
+		{
+			"category": "ALERT",
+			"eventType": "deploy",
+			"timestamp": 1000,
+			"dimensions": {"host": "host0"},
+			"properties": {"s": "v", "i": 2, "d": 2.5, "b": true}
+		},
+		{"eventType": "restart"}
+	]`
+	got, err := jsonToSignalFxV2Events([]byte(body))
+	require.NoError(t, err)
+	assert.Equal(t, []*sfxpb.Event{
+		{
+			EventType:  "deploy",
+			Category:   sfxCategoryPtr(sfxpb.EventCategory_ALERT),
+			Timestamp:  1000,
+			Dimensions: []*sfxpb.Dimension{{Key: "host", Value: "host0"}},
+			Properties: []*sfxpb.Property{
+				{Key: "b", Value: &sfxpb.PropertyValue{BoolValue: boolPtr(true)}},
+				{Key: "d", Value: &sfxpb.PropertyValue{DoubleValue: float64Ptr(2.5)}},
+				{Key: "i", Value: &sfxpb.PropertyValue{IntValue: int64Ptr(2)}},
+				{Key: "s", Value: &sfxpb.PropertyValue{StrValue: strPtr("v")}},
+			},
+		},
+		{
+			EventType: "restart",
+		},
+	}, got)
+
+	_, err = jsonToSignalFxV2Events([]byte(`[{"category": "NOT_A_CATEGORY"}]`))
+	assert.Error(t, err)
+	_, err = jsonToSignalFxV2Events([]byte(`[{"properties": {"o": {}}}]`))
+	assert.Error(t, err)
+}
+
+func boolPtr(b bool) *bool {
+	return &b
+}
@@ -0,0 +1,72 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package signalfxreceiver
+
+import (
+	"encoding/binary"
+	"errors"
+	"time"
+
+	sfxpb "github.com/signalfx/com_signalfx_metrics_protobuf/model"
+)
+
+// sfSourceDimension is the dimension the source of v1 datapoints is kept as.
+const sfSourceDimension = "sf_source"
+
+var errInvalidV1Length = errors.New("invalid length prefix of v1 datapoint")
+
+// protobufToSignalFxV1Datapoints decodes the protobuf body of /v1/datapoint, a
+// stream of datapoints each prefixed by its length as a varint.
+func protobufToSignalFxV1Datapoints(body []byte) ([]*sfxpb.DataPoint, error) {
+	var datapoints []*sfxpb.DataPoint
+	for len(body) > 0 {
+		length, n := binary.Uvarint(body)
+		if n <= 0 || length > uint64(len(body)-n) {
+			return nil, errInvalidV1Length
+		}
+		body = body[n:]
+
+		datapoint := &sfxpb.DataPoint{}
+		if err := datapoint.Unmarshal(body[:length]); err != nil {
+			return nil, err
+		}
+		datapoints = append(datapoints, datapoint)
+		body = body[length:]
+	}
+	return datapoints, nil
+}
+
+// signalFxV1ToV2Datapoints completes v1 datapoints, which have neither type,
+// timestamp nor dimensions, into v2 ones: they become gauges received now,
+// with their source as the sf_source dimension.
+func signalFxV1ToV2Datapoints(datapoints []*sfxpb.DataPoint, now time.Time) {
+	timestamp := now.UnixNano() / int64(time.Millisecond)
+	for _, datapoint := range datapoints {
+		if datapoint.MetricType == nil {
+			metricType := sfxpb.MetricType_GAUGE
+			datapoint.MetricType = &metricType
+		}
+		if datapoint.Timestamp == 0 {
+			datapoint.Timestamp = timestamp
+		}
+		if datapoint.Source != "" {
+			datapoint.Dimensions = append(datapoint.Dimensions, &sfxpb.Dimension{
+				Key:   sfSourceDimension,
+				Value: datapoint.Source,
+			})
+			datapoint.Source = ""
+		}
+	}
+}
@@ -32,4 +32,12 @@ type Config struct {
 	// ExtractCollectdTags instructs the Wavefront receiver to attempt to extract
 	// tags in the CollectD format from the metric name. The default is false.
 	ExtractCollectdTags bool `mapstructure:"extract_collectd_tags"`
+
+	// HistogramEndpoint is the address on which histogram distributions are
+	// received. Distributions are not received if it is empty, the default.
+	HistogramEndpoint string `mapstructure:"histogram_endpoint"`
+
+	// TracingEndpoint is the address on which spans are received when the
+	// receiver is part of a traces pipeline.
+	TracingEndpoint string `mapstructure:"tracing_endpoint"`
 }
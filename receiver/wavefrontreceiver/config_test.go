@@ -57,6 +57,8 @@ func TestLoadConfig(t *testing.T) {
 			},
 			TCPIdleTimeout:      5 * time.Second,
 			ExtractCollectdTags: true,
+			HistogramEndpoint:   "localhost:40000",
+			TracingEndpoint:     "localhost:30001",
 		},
 		r1)
 }
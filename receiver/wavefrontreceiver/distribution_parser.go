@@ -0,0 +1,200 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/protocol"
+)
+
+// distributionParser converts histogram distributions in the Wavefront
+// format, see https://docs.wavefront.com/proxies_histograms.html, into
+// distribution metrics.
+type distributionParser struct{}
+
+var _ (protocol.Parser) = (*distributionParser)(nil)
+var _ (protocol.ParserConfig) = (*distributionParser)(nil)
+
+// The granularities of the distributions, which are the duration of the
+// interval during which their values were aggregated.
+var distributionGranularities = map[string]time.Duration{
+	"!M": time.Minute,
+	"!H": time.Hour,
+	"!D": 24 * time.Hour,
+}
+
+// BuildParser creates a new Parser instance that receives Wavefront
+// distributions.
+func (dp *distributionParser) BuildParser() (protocol.Parser, error) {
+	return dp, nil
+}
+
+// centroid is a value of a distribution and the number of times it was seen.
+type centroid struct {
+	count int64
+	mean  float64
+}
+
+// Parse receives the string with a Wavefront distribution, and transforms it
+// to a cumulative distribution metric, whose start is the timestamp of the
+// distribution, and its end the timestamp plus the granularity. Each line
+// received represents a Wavefront distribution in the following format:
+//
+// 	"{!M | !H | !D} [<timestamp>] {#<count> <mean>}+ <metricName> source=<source> [pointTags]"
+//
+// The means of the centroids are used to build the buckets of the
+// distribution: each centroid is counted in its own bucket, bounded by the
+// midpoints between its mean and the means of the adjacent centroids.
+func (dp *distributionParser) Parse(line string) (*metricspb.Metric, error) {
+	granularityStr, rest := splitFirstToken(line)
+	granularity, ok := distributionGranularities[granularityStr]
+	if !ok {
+		return nil, fmt.Errorf("invalid granularity for wavefront distribution [%s]", line)
+	}
+
+	var ts timestamppb.Timestamp
+	token, afterToken := splitFirstToken(rest)
+	if unixTime, err := strconv.ParseInt(token, 10, 64); err == nil {
+		ts.Seconds = unixTime
+		rest = afterToken
+	} else {
+		// Timestamp can be omitted, use the start of the current interval.
+		ts.Seconds = time.Now().Truncate(granularity).Unix()
+	}
+
+	var centroids []centroid
+	for strings.HasPrefix(rest, "#") {
+		var countStr, meanStr string
+		countStr, rest = splitFirstToken(rest)
+		meanStr, rest = splitFirstToken(rest)
+		count, err := strconv.ParseInt(countStr[1:], 10, 64)
+		if err != nil || count < 0 {
+			return nil, fmt.Errorf("invalid centroid count for wavefront distribution [%s]", line)
+		}
+		mean, err := strconv.ParseFloat(meanStr, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid centroid mean for wavefront distribution [%s]: %v", line, err)
+		}
+		centroids = append(centroids, centroid{count: count, mean: mean})
+	}
+	if len(centroids) == 0 {
+		return nil, fmt.Errorf("no centroids for wavefront distribution [%s]", line)
+	}
+
+	metricNameStr, tags := splitFirstToken(rest)
+	metricName := unDoubleQuote(metricNameStr)
+	if metricName == "" {
+		return nil, fmt.Errorf("empty name for wavefront distribution [%s]", line)
+	}
+
+	labelKeys, labelValues, err := buildLabels(tags)
+	if err != nil {
+		return nil, fmt.Errorf("invalid wavefront distribution [%s]: %v", line, err)
+	}
+
+	metric := &metricspb.Metric{
+		MetricDescriptor: &metricspb.MetricDescriptor{
+			Name:      metricName,
+			Type:      metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION,
+			LabelKeys: labelKeys,
+		},
+		Timeseries: []*metricspb.TimeSeries{
+			{
+				StartTimestamp: &ts,
+				LabelValues:    labelValues,
+				Points: []*metricspb.Point{
+					{
+						Timestamp: &timestamppb.Timestamp{
+							Seconds: ts.Seconds + int64(granularity/time.Second),
+						},
+						Value: &metricspb.Point_DistributionValue{
+							DistributionValue: buildDistributionValue(centroids),
+						},
+					},
+				},
+			},
+		},
+	}
+	return metric, nil
+}
+
+func buildDistributionValue(centroids []centroid) *metricspb.DistributionValue {
+	// Sort the centroids by mean and merge the ones with the same mean.
+	sort.SliceStable(centroids, func(i, j int) bool {
+		return centroids[i].mean < centroids[j].mean
+	})
+	merged := centroids[:1]
+	for _, c := range centroids[1:] {
+		last := &merged[len(merged)-1]
+		if c.mean == last.mean {
+			last.count += c.count
+			continue
+		}
+		merged = append(merged, c)
+	}
+
+	var count int64
+	var sum float64
+	for _, c := range merged {
+		count += c.count
+		sum += float64(c.count) * c.mean
+	}
+
+	var sumOfSquaredDeviation float64
+	bounds := make([]float64, 0, len(merged)-1)
+	buckets := make([]*metricspb.DistributionValue_Bucket, 0, len(merged))
+	for i, c := range merged {
+		if count > 0 {
+			deviation := c.mean - sum/float64(count)
+			sumOfSquaredDeviation += float64(c.count) * deviation * deviation
+		}
+		if i > 0 {
+			bounds = append(bounds, (merged[i-1].mean+c.mean)/2)
+		}
+		buckets = append(buckets, &metricspb.DistributionValue_Bucket{Count: c.count})
+	}
+
+	return &metricspb.DistributionValue{
+		Count:                 count,
+		Sum:                   sum,
+		SumOfSquaredDeviation: sumOfSquaredDeviation,
+		BucketOptions: &metricspb.DistributionValue_BucketOptions{
+			Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+				Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+					Bounds: bounds,
+				},
+			},
+		},
+		Buckets: buckets,
+	}
+}
+
+// splitFirstToken returns the first space separated token of the string and
+// the rest of it, without leading spaces.
+func splitFirstToken(s string) (string, string) {
+	idx := strings.IndexByte(s, ' ')
+	if idx == -1 {
+		return s, ""
+	}
+	return s[:idx], strings.TrimLeft(s[idx+1:], " ")
+}
@@ -0,0 +1,165 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+	"time"
+
+	metricspb "github.com/census-instrumentation/opencensus-proto/gen-go/metrics/v1"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+func Test_distributionParser_Parse(t *testing.T) {
+	tests := []struct {
+		line    string
+		want    *metricspb.Metric
+		wantErr bool
+	}{
+		{
+			line: "!M 1493773500 #20 30.0 #10 5.1 request.latency source=appServer1 region=us-west",
+			want: buildDistributionMetric(
+				"request.latency",
+				[]string{"source", "region"},
+				[]string{"appServer1", "us-west"},
+				1493773500,
+				1493773560,
+				&metricspb.DistributionValue{
+					Count:                 30,
+					Sum:                   651,
+					SumOfSquaredDeviation: 4133.400000000001,
+					BucketOptions:         explicitBounds(17.55),
+					Buckets: []*metricspb.DistributionValue_Bucket{
+						{Count: 10},
+						{Count: 20},
+					},
+				}),
+		},
+		{
+			line: "!H 1493773200 #1 1 #2 3 #1 1 \"quoted.name\" source=s0",
+			want: buildDistributionMetric(
+				"quoted.name",
+				[]string{"source"},
+				[]string{"s0"},
+				1493773200,
+				1493776800,
+				&metricspb.DistributionValue{
+					Count:                 4,
+					Sum:                   8,
+					SumOfSquaredDeviation: 4,
+					BucketOptions:         explicitBounds(2),
+					Buckets: []*metricspb.DistributionValue_Bucket{
+						{Count: 2},
+						{Count: 2},
+					},
+				}),
+		},
+		{
+			line: "!D 1493769600 #3 -1.5 no.tags",
+			want: buildDistributionMetric(
+				"no.tags",
+				nil,
+				nil,
+				1493769600,
+				1493856000,
+				&metricspb.DistributionValue{
+					Count:         3,
+					Sum:           -4.5,
+					BucketOptions: explicitBounds(),
+					Buckets: []*metricspb.DistributionValue_Bucket{
+						{Count: 3},
+					},
+				}),
+		},
+		{
+			line:    "!S 1493773500 #20 30.0 request.latency source=s0",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1493773500 request.latency source=s0",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1493773500 #x 30.0 request.latency source=s0",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1493773500 #20 thirty request.latency source=s0",
+			wantErr: true,
+		},
+		{
+			line:    "!M 1493773500 #20 30.0 request.latency source",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.line, func(t *testing.T) {
+			got, err := (&distributionParser{}).Parse(tt.line)
+			if tt.wantErr {
+				assert.Error(t, err)
+				return
+			}
+
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, got)
+		})
+	}
+}
+
+func Test_distributionParser_ParseNoTimestamp(t *testing.T) {
+	got, err := (&distributionParser{}).Parse("!M #1 2 no.timestamp source=s0")
+	require.NoError(t, err)
+
+	start := got.Timeseries[0].StartTimestamp.Seconds
+	assert.Equal(t, time.Now().Truncate(time.Minute).Unix(), start, "start of the current minute")
+	assert.Equal(t, start+60, got.Timeseries[0].Points[0].Timestamp.Seconds)
+}
+
+func buildDistributionMetric(
+	name string,
+	keys []string,
+	values []string,
+	start int64,
+	end int64,
+	distribution *metricspb.DistributionValue,
+) *metricspb.Metric {
+	metric := buildMetric(
+		metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION,
+		name,
+		keys,
+		values,
+		&metricspb.Point{
+			Timestamp: &timestamppb.Timestamp{Seconds: end},
+			Value:     &metricspb.Point_DistributionValue{DistributionValue: distribution},
+		},
+	)
+	metric.Timeseries[0].StartTimestamp = &timestamppb.Timestamp{Seconds: start}
+	return metric
+}
+
+func explicitBounds(bounds ...float64) *metricspb.DistributionValue_BucketOptions {
+	if bounds == nil {
+		bounds = []float64{}
+	}
+	return &metricspb.DistributionValue_BucketOptions{
+		Type: &metricspb.DistributionValue_BucketOptions_Explicit_{
+			Explicit: &metricspb.DistributionValue_BucketOptions_Explicit{
+				Bounds: bounds,
+			},
+		},
+	}
+}
@@ -40,7 +40,8 @@ func NewFactory() component.ReceiverFactory {
 	return receiverhelper.NewFactory(
 		typeStr,
 		createDefaultConfig,
-		receiverhelper.WithMetrics(createMetricsReceiver))
+		receiverhelper.WithMetrics(createMetricsReceiver),
+		receiverhelper.WithTraces(createTraceReceiver))
 }
 
 func createDefaultConfig() configmodels.Receiver {
@@ -52,7 +53,8 @@ func createDefaultConfig() configmodels.Receiver {
 		TCPAddr: confignet.TCPAddr{
 			Endpoint: "localhost:2003",
 		},
-		TCPIdleTimeout: transport.TCPIdleTimeoutDefault,
+		TCPIdleTimeout:  transport.TCPIdleTimeoutDefault,
+		TracingEndpoint: "localhost:30000",
 	}
 }
 
@@ -85,5 +87,31 @@ func createMetricsReceiver(
 			},
 		},
 	}
-	return carbonreceiver.New(params.Logger, carbonCfg, consumer)
+	metricsReceiver, err := carbonreceiver.New(params.Logger, carbonCfg, consumer)
+	if err != nil || rCfg.HistogramEndpoint == "" {
+		return metricsReceiver, err
+	}
+
+	// Distributions are received on their own port, in the same way as the
+	// metrics but with a dedicated parser.
+	carbonCfg.Endpoint = rCfg.HistogramEndpoint
+	carbonCfg.Parser = &protocol.Config{
+		Type:   "plaintext",
+		Config: &distributionParser{},
+	}
+	histogramReceiver, err := carbonreceiver.New(params.Logger, carbonCfg, consumer)
+	if err != nil {
+		_ = metricsReceiver.Shutdown(ctx)
+		return nil, err
+	}
+	return metricsReceivers{metricsReceiver, histogramReceiver}, nil
+}
+
+func createTraceReceiver(
+	_ context.Context,
+	params component.ReceiverCreateParams,
+	cfg configmodels.Receiver,
+	consumer consumer.TraceConsumer,
+) (component.TraceReceiver, error) {
+	return newTraceReceiver(params.Logger, cfg.(*Config), consumer)
 }
@@ -19,6 +19,7 @@ import (
 	"testing"
 
 	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
 	"go.opentelemetry.io/collector/component"
 	"go.opentelemetry.io/collector/config/configcheck"
 	"go.opentelemetry.io/collector/exporter/exportertest"
@@ -40,4 +41,21 @@ func TestCreateReceiver(t *testing.T) {
 	tReceiver, err := createMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
 	assert.NoError(t, err)
 	assert.NotNil(t, tReceiver, "receiver creation failed")
+
+	traceReceiver, err := createTraceReceiver(context.Background(), params, cfg, exportertest.NewNopTraceExporter())
+	assert.NoError(t, err)
+	assert.NotNil(t, traceReceiver, "trace receiver creation failed")
+}
+
+func TestCreateReceiverWithHistograms(t *testing.T) {
+	cfg := createDefaultConfig().(*Config)
+	cfg.Endpoint = "localhost:0"
+	cfg.HistogramEndpoint = "localhost:0"
+
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	mReceiver, err := createMetricsReceiver(context.Background(), params, cfg, exportertest.NewNopMetricsExporter())
+	require.NoError(t, err)
+	require.IsType(t, metricsReceivers{}, mReceiver)
+	assert.Len(t, mReceiver, 2)
+	assert.NoError(t, mReceiver.Shutdown(context.Background()))
 }
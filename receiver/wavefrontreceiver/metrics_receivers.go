@@ -0,0 +1,53 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"context"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+)
+
+// metricsReceivers starts and stops together the receivers of the metrics
+// and of the histogram distributions, which listen on different ports.
+type metricsReceivers []component.MetricsReceiver
+
+var _ component.MetricsReceiver = (metricsReceivers)(nil)
+
+// Start starts all the receivers, the ones already started are shut down if
+// any of them fails to start.
+func (mr metricsReceivers) Start(ctx context.Context, host component.Host) error {
+	for i, r := range mr {
+		if err := r.Start(ctx, host); err != nil {
+			for _, started := range mr[:i] {
+				_ = started.Shutdown(ctx)
+			}
+			return err
+		}
+	}
+	return nil
+}
+
+// Shutdown shuts down all the receivers.
+func (mr metricsReceivers) Shutdown(ctx context.Context) error {
+	var errs []error
+	for _, r := range mr {
+		if err := r.Shutdown(ctx); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return componenterror.CombineErrors(errs)
+}
@@ -152,3 +152,36 @@ func Test_wavefrontreceiver_EndToEnd(t *testing.T) {
 		sink.Reset()
 	}
 }
+
+func Test_wavefrontreceiver_Histograms(t *testing.T) {
+	rCfg := createDefaultConfig().(*Config)
+	rCfg.TCPIdleTimeout = time.Second
+	rCfg.Endpoint = testutil.GetAvailableLocalAddress(t)
+	addr := testutil.GetAvailableLocalAddress(t)
+	rCfg.HistogramEndpoint = addr
+
+	sink := new(exportertest.SinkMetricsExporter)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	rcvr, err := createMetricsReceiver(context.Background(), params, rCfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	_, err = fmt.Fprint(conn, "!M 1493773500 #20 30.0 #10 5.1 request.latency source=appServer1\n")
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	testutil.WaitFor(t, func() bool {
+		return sink.MetricsCount() == 1
+	})
+	ocmds := internaldata.MetricsToOC(sink.AllMetrics()[0])
+	require.Len(t, ocmds, 1)
+	require.Len(t, ocmds[0].Metrics, 1)
+	metric := ocmds[0].Metrics[0]
+	assert.Equal(t, "request.latency", metric.GetMetricDescriptor().GetName())
+	assert.Equal(t, metricspb.MetricDescriptor_CUMULATIVE_DISTRIBUTION, metric.GetMetricDescriptor().GetType())
+	assert.Equal(t, int64(30), metric.Timeseries[0].Points[0].GetDistributionValue().GetCount())
+}
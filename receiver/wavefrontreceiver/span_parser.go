@@ -0,0 +1,167 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+// The span tags with a special meaning, see
+// https://docs.wavefront.com/trace_data_details.html#span-tags.
+const (
+	spanTagTraceID  = "traceId"
+	spanTagSpanID   = "spanId"
+	spanTagParent   = "parent"
+	spanTagSource   = "source"
+	spanTagService  = "service"
+	spanTagError    = "error"
+	spanTagSpanKind = "span.kind"
+)
+
+var spanKinds = map[string]pdata.SpanKind{
+	"client":   pdata.SpanKindCLIENT,
+	"server":   pdata.SpanKindSERVER,
+	"producer": pdata.SpanKindPRODUCER,
+	"consumer": pdata.SpanKindCONSUMER,
+	"internal": pdata.SpanKindINTERNAL,
+}
+
+// parseSpan converts a span in the Wavefront format, see
+// https://docs.wavefront.com/trace_data_details.html#wavefront-span-format,
+// into the internal format of the Collector. Each line received represents a
+// Wavefront span in the following format:
+//
+// 	"<operationName> source=<source> <spanTags> <start_milliseconds> <duration_milliseconds>"
+//
+// The UUIDs of the "traceId", "spanId" and "parent" tags are converted to the
+// trace, span and parent span IDs, of which only the last 8 bytes are used for
+// span IDs. The "source" and "service" tags are converted to the "host.name"
+// and "service.name" resource attributes, and the other tags to attributes of
+// the span. If the span has more than one parent only the first one is used.
+func parseSpan(line string) (pdata.Traces, error) {
+	traces := pdata.NewTraces()
+
+	nameStr, rest := splitFirstToken(line)
+	name := unDoubleQuote(nameStr)
+	if name == "" {
+		return traces, fmt.Errorf("empty name for wavefront span [%s]", line)
+	}
+
+	idx := strings.LastIndexByte(rest, ' ')
+	if idx == -1 {
+		return traces, fmt.Errorf("invalid wavefront span [%s]", line)
+	}
+	duration, err := strconv.ParseInt(rest[idx+1:], 10, 64)
+	if err != nil || duration < 0 {
+		return traces, fmt.Errorf("invalid duration for wavefront span [%s]", line)
+	}
+	rest = strings.TrimRight(rest[:idx], " ")
+	idx = strings.LastIndexByte(rest, ' ')
+	if idx == -1 {
+		return traces, fmt.Errorf("invalid wavefront span [%s]", line)
+	}
+	start, err := strconv.ParseInt(rest[idx+1:], 10, 64)
+	if err != nil {
+		return traces, fmt.Errorf("invalid start for wavefront span [%s]", line)
+	}
+	keys, values, err := buildLabels(strings.TrimRight(rest[:idx], " "))
+	if err != nil {
+		return traces, fmt.Errorf("invalid wavefront span [%s]: %v", line, err)
+	}
+
+	traces.ResourceSpans().Resize(1)
+	rs := traces.ResourceSpans().At(0)
+	rs.Resource().InitEmpty()
+	rs.InstrumentationLibrarySpans().Resize(1)
+	ils := rs.InstrumentationLibrarySpans().At(0)
+	ils.Spans().Resize(1)
+	span := ils.Spans().At(0)
+
+	span.SetName(name)
+	startTime := time.Unix(0, 0).Add(time.Duration(start) * time.Millisecond)
+	span.SetStartTime(pdata.TimestampUnixNano(startTime.UnixNano()))
+	span.SetEndTime(pdata.TimestampUnixNano(startTime.Add(time.Duration(duration) * time.Millisecond).UnixNano()))
+
+	var hasTraceID, hasSpanID, hasParent bool
+	resourceAttrs := rs.Resource().Attributes()
+	attrs := span.Attributes()
+	for i, key := range keys {
+		value := values[i].Value
+		switch key.Key {
+		case spanTagTraceID:
+			id, err := parseUUID(value)
+			if err != nil {
+				return traces, fmt.Errorf("invalid traceId for wavefront span [%s]: %v", line, err)
+			}
+			span.SetTraceID(pdata.TraceID(id))
+			hasTraceID = true
+		case spanTagSpanID:
+			id, err := parseUUID(value)
+			if err != nil {
+				return traces, fmt.Errorf("invalid spanId for wavefront span [%s]: %v", line, err)
+			}
+			span.SetSpanID(pdata.SpanID(id[8:]))
+			hasSpanID = true
+		case spanTagParent:
+			if hasParent {
+				continue
+			}
+			id, err := parseUUID(value)
+			if err != nil {
+				return traces, fmt.Errorf("invalid parent for wavefront span [%s]: %v", line, err)
+			}
+			span.SetParentSpanID(pdata.SpanID(id[8:]))
+			hasParent = true
+		case spanTagSource:
+			resourceAttrs.UpsertString(conventions.AttributeHostName, value)
+		case spanTagService:
+			resourceAttrs.UpsertString(conventions.AttributeServiceName, value)
+		default:
+			if key.Key == spanTagError && value == "true" {
+				span.Status().InitEmpty()
+				span.Status().SetCode(pdata.StatusCodeUnknownError)
+			}
+			if kind, ok := spanKinds[value]; ok && key.Key == spanTagSpanKind {
+				span.SetKind(kind)
+			}
+			attrs.UpsertString(key.Key, value)
+		}
+	}
+	if !hasTraceID || !hasSpanID {
+		return traces, fmt.Errorf("missing traceId or spanId for wavefront span [%s]", line)
+	}
+
+	return traces, nil
+}
+
+// parseUUID returns the 16 bytes of a UUID, with or without dashes.
+func parseUUID(s string) ([]byte, error) {
+	id, err := hex.DecodeString(strings.Replace(s, "-", "", -1))
+	if err != nil {
+		return nil, err
+	}
+	if len(id) != 16 {
+		return nil, errors.New("UUID must be 16 bytes")
+	}
+	return id, nil
+}
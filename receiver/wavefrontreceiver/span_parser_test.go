@@ -0,0 +1,99 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/consumer/pdata"
+	"go.opentelemetry.io/collector/translator/conventions"
+)
+
+func Test_parseSpan(t *testing.T) {
+	line := "getAllUsers source=localhost traceId=7b3bf470-9456-11e8-9eb6-529269fb1459 " +
+		"spanId=0313bafe-9457-11e8-9eb6-529269fb1459 parent=2f64e538-9457-11e8-9eb6-529269fb1459 " +
+		"parent=00000000-0000-0000-0000-000000000001 application=Wavefront service=auth " +
+		"http.method=GET span.kind=server error=true 1552949776000 343"
+
+	traces, err := parseSpan(line)
+	require.NoError(t, err)
+	require.Equal(t, 1, traces.SpanCount())
+
+	rs := traces.ResourceSpans().At(0)
+	resourceAttrs := rs.Resource().Attributes()
+	assert.Equal(t, 2, resourceAttrs.Len())
+	host, ok := resourceAttrs.Get(conventions.AttributeHostName)
+	require.True(t, ok)
+	assert.Equal(t, "localhost", host.StringVal())
+	service, ok := resourceAttrs.Get(conventions.AttributeServiceName)
+	require.True(t, ok)
+	assert.Equal(t, "auth", service.StringVal())
+
+	span := rs.InstrumentationLibrarySpans().At(0).Spans().At(0)
+	assert.Equal(t, "getAllUsers", span.Name())
+	assert.Equal(t,
+		pdata.TraceID([]byte{0x7b, 0x3b, 0xf4, 0x70, 0x94, 0x56, 0x11, 0xe8, 0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}),
+		span.TraceID())
+	assert.Equal(t, pdata.SpanID([]byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}), span.SpanID())
+	assert.Equal(t, pdata.SpanID([]byte{0x9e, 0xb6, 0x52, 0x92, 0x69, 0xfb, 0x14, 0x59}), span.ParentSpanID())
+	assert.Equal(t, pdata.TimestampUnixNano(1552949776000000000), span.StartTime())
+	assert.Equal(t, pdata.TimestampUnixNano(1552949776343000000), span.EndTime())
+	assert.Equal(t, pdata.SpanKindSERVER, span.Kind())
+	assert.Equal(t, pdata.StatusCodeUnknownError, span.Status().Code())
+
+	attrs := span.Attributes()
+	assert.Equal(t, 4, attrs.Len())
+	for key, want := range map[string]string{
+		"application": "Wavefront",
+		"http.method": "GET",
+		"span.kind":   "server",
+		"error":       "true",
+	} {
+		got, ok := attrs.Get(key)
+		if assert.True(t, ok, key) {
+			assert.Equal(t, want, got.StringVal(), key)
+		}
+	}
+}
+
+func Test_parseSpanErrors(t *testing.T) {
+	const (
+		traceID = "traceId=7b3bf470-9456-11e8-9eb6-529269fb1459"
+		spanID  = "spanId=0313bafe-9457-11e8-9eb6-529269fb1459"
+	)
+	tests := []struct {
+		name string
+		line string
+	}{
+		{name: "empty_name", line: "\"\" source=s0 " + traceID + " " + spanID + " 1552949776000 343"},
+		{name: "no_duration", line: "op source=s0 " + traceID + " " + spanID + " 1552949776000"},
+		{name: "invalid_duration", line: "op source=s0 " + traceID + " " + spanID + " 1552949776000 -1"},
+		{name: "invalid_start", line: "op source=s0 " + traceID + " " + spanID + " start 343"},
+		{name: "missing_trace_id", line: "op source=s0 " + spanID + " 1552949776000 343"},
+		{name: "missing_span_id", line: "op source=s0 " + traceID + " 1552949776000 343"},
+		{name: "invalid_trace_id", line: "op source=s0 traceId=7b3bf470 " + spanID + " 1552949776000 343"},
+		{name: "invalid_span_id", line: "op source=s0 " + traceID + " spanId=xyz 1552949776000 343"},
+		{name: "invalid_parent", line: "op source=s0 " + traceID + " " + spanID + " parent=1 1552949776000 343"},
+		{name: "invalid_tags", line: "op source " + traceID + " " + spanID + " 1552949776000 343"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := parseSpan(tt.line)
+			assert.Error(t, err)
+		})
+	}
+}
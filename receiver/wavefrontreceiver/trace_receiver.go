@@ -0,0 +1,217 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/consumer"
+	"go.opentelemetry.io/collector/obsreport"
+	"go.uber.org/zap"
+
+	"github.com/open-telemetry/opentelemetry-collector-contrib/receiver/carbonreceiver/transport"
+)
+
+const (
+	traceTransport = "tcp"
+	traceFormat    = "wavefront"
+)
+
+// traceReceiver receives Wavefront spans over TCP, one span per line.
+type traceReceiver struct {
+	sync.Mutex
+	logger       *zap.Logger
+	name         string
+	endpoint     string
+	idleTimeout  time.Duration
+	nextConsumer consumer.TraceConsumer
+
+	ln   net.Listener
+	done chan struct{}
+	wg   sync.WaitGroup
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+var _ component.TraceReceiver = (*traceReceiver)(nil)
+
+func newTraceReceiver(
+	logger *zap.Logger,
+	config *Config,
+	nextConsumer consumer.TraceConsumer,
+) (*traceReceiver, error) {
+	if nextConsumer == nil {
+		return nil, componenterror.ErrNilNextConsumer
+	}
+	if config.TracingEndpoint == "" {
+		return nil, errors.New("tracing_endpoint must be specified to receive spans")
+	}
+
+	idleTimeout := config.TCPIdleTimeout
+	if idleTimeout <= 0 {
+		idleTimeout = transport.TCPIdleTimeoutDefault
+	}
+
+	return &traceReceiver{
+		logger:       logger,
+		name:         config.Name(),
+		endpoint:     config.TracingEndpoint,
+		idleTimeout:  idleTimeout,
+		nextConsumer: nextConsumer,
+		done:         make(chan struct{}),
+	}, nil
+}
+
+// Start listens on the tracing endpoint.
+func (r *traceReceiver) Start(_ context.Context, host component.Host) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStarted
+	r.startOnce.Do(func() {
+		r.ln, err = net.Listen("tcp", r.endpoint)
+		if err != nil {
+			return
+		}
+
+		r.wg.Add(1)
+		go func() {
+			defer r.wg.Done()
+			if acceptErr := r.acceptConnections(); acceptErr != nil {
+				host.ReportFatalError(acceptErr)
+			}
+		}()
+	})
+	return err
+}
+
+// Shutdown stops listening and closes the connections.
+func (r *traceReceiver) Shutdown(context.Context) error {
+	r.Lock()
+	defer r.Unlock()
+
+	err := componenterror.ErrAlreadyStopped
+	r.stopOnce.Do(func() {
+		err = nil
+		if r.ln == nil {
+			return
+		}
+		close(r.done)
+		err = r.ln.Close()
+		r.wg.Wait()
+	})
+	return err
+}
+
+func (r *traceReceiver) acceptConnections() error {
+	conns := make(map[net.Conn]struct{})
+	var connsMtx sync.Mutex
+	var connsWg sync.WaitGroup
+	defer func() {
+		// Close any lingering connection.
+		connsMtx.Lock()
+		for conn := range conns {
+			conn.Close()
+		}
+		connsMtx.Unlock()
+		connsWg.Wait()
+	}()
+
+	for {
+		conn, err := r.ln.Accept()
+		if err != nil {
+			select {
+			case <-r.done:
+				// The listener was closed by Shutdown.
+				return nil
+			default:
+			}
+			if netErr, ok := err.(net.Error); ok && netErr.Temporary() {
+				continue
+			}
+			return err
+		}
+
+		connsMtx.Lock()
+		conns[conn] = struct{}{}
+		connsMtx.Unlock()
+		connsWg.Add(1)
+		go func() {
+			defer connsWg.Done()
+			r.handleConnection(conn)
+			connsMtx.Lock()
+			delete(conns, conn)
+			connsMtx.Unlock()
+		}()
+	}
+}
+
+func (r *traceReceiver) handleConnection(conn net.Conn) {
+	defer conn.Close()
+	receiverCtx := obsreport.ReceiverContext(context.Background(), r.name, traceTransport, "")
+	reader := bufio.NewReader(conn)
+	for {
+		// Idle connections are purged by the deadline.
+		if err := conn.SetDeadline(time.Now().Add(r.idleTimeout)); err != nil {
+			r.logger.Debug("Wavefront span connection error", zap.Error(err))
+			return
+		}
+
+		// Data can be returned along with an error, typically io.EOF.
+		bytes, err := reader.ReadBytes('\n')
+		line := strings.TrimSpace(string(bytes))
+		if line != "" {
+			if consumeErr := r.consumeSpan(receiverCtx, line); consumeErr != nil {
+				// The protocol doesn't account for returning errors, so the
+				// connection is closed to report it to the client.
+				return
+			}
+		}
+
+		if err == io.EOF {
+			return
+		}
+		if err != nil {
+			r.logger.Debug("Wavefront span connection error", zap.Error(err))
+			return
+		}
+	}
+}
+
+// consumeSpan passes the span of the line to the next consumer, and returns
+// the error of the next consumer if any.
+func (r *traceReceiver) consumeSpan(receiverCtx context.Context, line string) error {
+	ctx := obsreport.StartTraceDataReceiveOp(receiverCtx, r.name, traceTransport)
+	traces, err := parseSpan(line)
+	if err != nil {
+		r.logger.Debug("Wavefront span translation error", zap.Error(err))
+		obsreport.EndTraceDataReceiveOp(ctx, traceFormat, 1, err)
+		return nil
+	}
+
+	err = r.nextConsumer.ConsumeTraces(ctx, traces)
+	obsreport.EndTraceDataReceiveOp(ctx, traceFormat, 1, err)
+	return err
+}
@@ -0,0 +1,81 @@
+// Copyright 2020, OpenTelemetry Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package wavefrontreceiver
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/collector/component"
+	"go.opentelemetry.io/collector/component/componenterror"
+	"go.opentelemetry.io/collector/component/componenttest"
+	"go.opentelemetry.io/collector/exporter/exportertest"
+	"go.opentelemetry.io/collector/testutil"
+	"go.uber.org/zap"
+)
+
+func Test_traceReceiver_EndToEnd(t *testing.T) {
+	rCfg := createDefaultConfig().(*Config)
+	rCfg.TCPIdleTimeout = time.Second
+	addr := testutil.GetAvailableLocalAddress(t)
+	rCfg.TracingEndpoint = addr
+
+	sink := new(exportertest.SinkTraceExporter)
+	params := component.ReceiverCreateParams{Logger: zap.NewNop()}
+	rcvr, err := createTraceReceiver(context.Background(), params, rCfg, sink)
+	require.NoError(t, err)
+
+	require.NoError(t, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+	defer rcvr.Shutdown(context.Background())
+	assert.Equal(t, componenterror.ErrAlreadyStarted, rcvr.Start(context.Background(), componenttest.NewNopHost()))
+
+	conn, err := net.Dial("tcp", addr)
+	require.NoError(t, err)
+	msg := "op0 source=s0 traceId=7b3bf470-9456-11e8-9eb6-529269fb1459 spanId=0313bafe-9457-11e8-9eb6-529269fb1459 1552949776000 343\n" +
+		"invalid span\n" +
+		"op1 source=s0 traceId=7b3bf470-9456-11e8-9eb6-529269fb1459 spanId=0313bafe-9457-11e8-9eb6-529269fb1460 1552949776100 20"
+	_, err = fmt.Fprint(conn, msg)
+	require.NoError(t, err)
+	require.NoError(t, conn.Close())
+
+	testutil.WaitFor(t, func() bool {
+		return sink.SpansCount() == 2
+	})
+	traces := sink.AllTraces()
+	require.Len(t, traces, 2)
+	names := make([]string, 0, len(traces))
+	for _, td := range traces {
+		names = append(names, td.ResourceSpans().At(0).InstrumentationLibrarySpans().At(0).Spans().At(0).Name())
+	}
+	assert.ElementsMatch(t, []string{"op0", "op1"}, names)
+
+	assert.NoError(t, rcvr.Shutdown(context.Background()))
+	assert.Equal(t, componenterror.ErrAlreadyStopped, rcvr.Shutdown(context.Background()))
+}
+
+func Test_newTraceReceiver(t *testing.T) {
+	rCfg := createDefaultConfig().(*Config)
+	_, err := newTraceReceiver(zap.NewNop(), rCfg, nil)
+	assert.Equal(t, componenterror.ErrNilNextConsumer, err)
+
+	rCfg.TracingEndpoint = ""
+	_, err = newTraceReceiver(zap.NewNop(), rCfg, exportertest.NewNopTraceExporter())
+	assert.Error(t, err)
+}